@@ -9,12 +9,14 @@ type Initialization struct {
 	Repo       service.Repository
 	Svc        service.Service
 	Controller controller.Controller
+	Settings   Settings
 }
 
-func NewInitialization(repo service.Repository, svc service.Service, controller controller.Controller) *Initialization {
+func NewInitialization(repo service.Repository, svc service.Service, controller controller.Controller, settings Settings) *Initialization {
 	return &Initialization{
 		Repo:       repo,
 		Svc:        svc,
 		Controller: controller,
+		Settings:   settings,
 	}
 }
@@ -0,0 +1,445 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/storage"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSettingsFile é lido quando nenhum arquivo é informado e ele existe.
+const DefaultSettingsFile = "teamtask.yaml"
+
+// Settings reúne a configuração da aplicação. Os valores vêm, em ordem crescente de
+// precedência, dos padrões, do arquivo YAML, das variáveis de ambiente e das flags.
+type Settings struct {
+	Database     DatabaseSettings      `yaml:"database"`
+	Server       ServerSettings        `yaml:"server"`
+	Log          LogSettings           `yaml:"log"`
+	Auth         AuthSettings          `yaml:"auth"`
+	Workflow     service.Workflow      `yaml:"workflow"` // status das tarefas e transições permitidas
+	Priorities   service.Priorities    `yaml:"priorities"`
+	Subtasks     service.SubtaskPolicy `yaml:"subtasks"`
+	Dependencies service.LinkPolicy    `yaml:"dependencies"`
+	Attachments  AttachmentSettings    `yaml:"attachments"`
+	Trash        TrashSettings         `yaml:"trash"`
+}
+
+// DatabaseSettings configura a conexão com o banco de dados.
+type DatabaseSettings struct {
+	Driver          string        `yaml:"driver"` // mysql, sqlite ou postgres
+	DSN             string        `yaml:"dsn"`
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+	AutoMigrate     bool          `yaml:"autoMigrate"`
+}
+
+// ServerSettings configura o servidor HTTP.
+type ServerSettings struct {
+	Address     string   `yaml:"address"`
+	CORSOrigins []string `yaml:"corsOrigins"` // "*" libera qualquer origem
+}
+
+// LogSettings configura o logger.
+type LogSettings struct {
+	Level string `yaml:"level"`
+}
+
+// AuthSettings configura a emissão de tokens.
+type AuthSettings struct {
+	TokenSecret     string        `yaml:"tokenSecret"`
+	AccessTokenTTL  time.Duration `yaml:"accessTokenTTL"`
+	RefreshTokenTTL time.Duration `yaml:"refreshTokenTTL"`
+}
+
+// AttachmentSettings configura onde o conteúdo dos anexos é gravado e quais arquivos são
+// aceitos.
+type AttachmentSettings struct {
+	Storage                  string           `yaml:"storage"` // local ou s3
+	Dir                      string           `yaml:"dir"`     // diretório do armazenamento local
+	S3                       storage.S3Config `yaml:"s3"`
+	service.AttachmentPolicy `yaml:",inline"`
+}
+
+// TrashSettings configura a limpeza da lixeira de tarefas e usuários.
+type TrashSettings struct {
+	RetentionDays int           `yaml:"retentionDays"` // dias na lixeira antes de apagar de vez; zero desliga a limpeza
+	PurgeInterval time.Duration `yaml:"purgeInterval"` // intervalo entre as limpezas
+}
+
+// DefaultSettings retorna a configuração usada quando nada é informado.
+func DefaultSettings() Settings {
+	return Settings{
+		Database: DatabaseSettings{
+			Driver:          "mysql",
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Server: ServerSettings{
+			Address:     ":8000",
+			CORSOrigins: []string{"*"},
+		},
+		Log: LogSettings{Level: "info"},
+		Auth: AuthSettings{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+		Workflow:     service.DefaultWorkflow(),
+		Priorities:   service.DefaultPriorities(),
+		Subtasks:     service.DefaultSubtaskPolicy(),
+		Dependencies: service.DefaultLinkPolicy(),
+		Attachments: AttachmentSettings{
+			Storage:          "local",
+			Dir:              "attachments",
+			AttachmentPolicy: service.DefaultAttachmentPolicy(),
+		},
+		Trash: TrashSettings{
+			RetentionDays: 30,
+			PurgeInterval: time.Hour,
+		},
+	}
+}
+
+// setting liga uma variável de ambiente e uma flag ao campo que elas alteram.
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	set   func(s *Settings, value string) error
+}
+
+var settingsTable = []setting{
+	{"TEAMTASK_DB_DRIVER", "db-driver", "banco de dados: mysql, sqlite ou postgres", func(s *Settings, v string) error {
+		s.Database.Driver = v
+		return nil
+	}},
+	{"TEAMTASK_DB_DSN", "db-dsn", "DSN do banco de dados (no SQLite, o caminho do arquivo)", func(s *Settings, v string) error {
+		s.Database.DSN = v
+		return nil
+	}},
+	{"TEAMTASK_DB_MAX_OPEN_CONNS", "db-max-open-conns", "máximo de conexões abertas", func(s *Settings, v string) error {
+		return parseInt(v, &s.Database.MaxOpenConns)
+	}},
+	{"TEAMTASK_DB_MAX_IDLE_CONNS", "db-max-idle-conns", "máximo de conexões ociosas", func(s *Settings, v string) error {
+		return parseInt(v, &s.Database.MaxIdleConns)
+	}},
+	{"TEAMTASK_DB_CONN_MAX_LIFETIME", "db-conn-max-lifetime", "tempo máximo de vida de uma conexão (ex.: 30m)", func(s *Settings, v string) error {
+		return parseDuration(v, &s.Database.ConnMaxLifetime)
+	}},
+	{"TEAMTASK_AUTO_MIGRATE", "auto-migrate", "aplica as migrações pendentes ao iniciar", func(s *Settings, v string) error {
+		return parseBool(v, &s.Database.AutoMigrate)
+	}},
+	{"TEAMTASK_LISTEN_ADDR", "listen", "endereço do servidor HTTP (ex.: :8000)", func(s *Settings, v string) error {
+		s.Server.Address = v
+		return nil
+	}},
+	{"TEAMTASK_CORS_ORIGINS", "cors-origins", "origens liberadas no CORS, separadas por vírgula", func(s *Settings, v string) error {
+		s.Server.CORSOrigins = splitList(v)
+		return nil
+	}},
+	{"TEAMTASK_LOG_LEVEL", "log-level", "nível de log: debug, info, warn ou error", func(s *Settings, v string) error {
+		s.Log.Level = v
+		return nil
+	}},
+	{"TEAMTASK_TOKEN_SECRET", "token-secret", "chave de assinatura dos tokens", func(s *Settings, v string) error {
+		s.Auth.TokenSecret = v
+		return nil
+	}},
+	{"TEAMTASK_ACCESS_TOKEN_TTL", "access-token-ttl", "validade dos access tokens (ex.: 15m)", func(s *Settings, v string) error {
+		return parseDuration(v, &s.Auth.AccessTokenTTL)
+	}},
+	{"TEAMTASK_REFRESH_TOKEN_TTL", "refresh-token-ttl", "validade dos refresh tokens (ex.: 720h)", func(s *Settings, v string) error {
+		return parseDuration(v, &s.Auth.RefreshTokenTTL)
+	}},
+	{"TEAMTASK_ATTACHMENTS_STORAGE", "attachments-storage", "armazenamento dos anexos: local ou s3", func(s *Settings, v string) error {
+		s.Attachments.Storage = v
+		return nil
+	}},
+	{"TEAMTASK_ATTACHMENTS_DIR", "attachments-dir", "diretório dos anexos no armazenamento local", func(s *Settings, v string) error {
+		s.Attachments.Dir = v
+		return nil
+	}},
+	{"TEAMTASK_ATTACHMENTS_MAX_SIZE", "attachments-max-size", "tamanho máximo de um anexo, em bytes", func(s *Settings, v string) error {
+		return parseInt64(v, &s.Attachments.MaxSize)
+	}},
+	{"TEAMTASK_S3_ENDPOINT", "s3-endpoint", "endereço do serviço compatível com o S3 (ex.: https://s3.us-east-1.amazonaws.com)", func(s *Settings, v string) error {
+		s.Attachments.S3.Endpoint = v
+		return nil
+	}},
+	{"TEAMTASK_S3_REGION", "s3-region", "região do bucket de anexos", func(s *Settings, v string) error {
+		s.Attachments.S3.Region = v
+		return nil
+	}},
+	{"TEAMTASK_S3_BUCKET", "s3-bucket", "bucket dos anexos", func(s *Settings, v string) error {
+		s.Attachments.S3.Bucket = v
+		return nil
+	}},
+	{"TEAMTASK_S3_ACCESS_KEY", "s3-access-key", "chave de acesso do bucket", func(s *Settings, v string) error {
+		s.Attachments.S3.AccessKey = v
+		return nil
+	}},
+	{"TEAMTASK_S3_SECRET_KEY", "s3-secret-key", "chave secreta do bucket", func(s *Settings, v string) error {
+		s.Attachments.S3.SecretKey = v
+		return nil
+	}},
+	{"TEAMTASK_TRASH_RETENTION_DAYS", "trash-retention-days", "dias na lixeira antes de apagar de vez (0 desliga a limpeza)", func(s *Settings, v string) error {
+		return parseInt(v, &s.Trash.RetentionDays)
+	}},
+	{"TEAMTASK_TRASH_PURGE_INTERVAL", "trash-purge-interval", "intervalo entre as limpezas da lixeira (ex.: 1h)", func(s *Settings, v string) error {
+		return parseDuration(v, &s.Trash.PurgeInterval)
+	}},
+}
+
+// LoadSettings monta a configuração a partir dos argumentos da linha de comando, das
+// variáveis de ambiente e do arquivo indicado por -config ou TEAMTASK_CONFIG. Retorna
+// também os argumentos que sobraram depois das flags, como um subcomando. Todos os
+// campos inválidos são reportados juntos no erro.
+func LoadSettings(args []string) (Settings, []string, error) {
+	fs := flag.NewFlagSet("teamtask", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configFile := fs.String("config", "", "arquivo de configuração YAML")
+	flagValues := make(map[string]*string, len(settingsTable))
+	for _, s := range settingsTable {
+		flagValues[s.flag] = fs.String(s.flag, "", s.usage+" (env "+s.env+")")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Settings{}, nil, err
+	}
+
+	settings := DefaultSettings()
+	var errs []error
+
+	// Arquivo: o padrão é opcional, um arquivo informado precisa existir
+	path := *configFile
+	if path == "" {
+		path = os.Getenv("TEAMTASK_CONFIG")
+	}
+	required := path != ""
+	if !required {
+		path = DefaultSettingsFile
+	}
+	if err := loadSettingsFile(path, required, &settings); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Variáveis de ambiente
+	for _, s := range settingsTable {
+		if value, ok := os.LookupEnv(s.env); ok {
+			if err := s.set(&settings, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+
+	// Flags, apenas as informadas explicitamente
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settingsTable {
+			if s.flag == f.Name {
+				if err := s.set(&settings, *flagValues[s.flag]); err != nil {
+					errs = append(errs, fmt.Errorf("-%s: %w", s.flag, err))
+				}
+			}
+		}
+	})
+
+	if err := settings.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return Settings{}, nil, fmt.Errorf("configuração inválida:\n%w", errors.Join(errs...))
+	}
+
+	return settings, fs.Args(), nil
+}
+
+// SettingsUsage descreve as flags e variáveis de ambiente aceitas.
+func SettingsUsage() string {
+	var b strings.Builder
+	b.WriteString("  -config\n\tarquivo de configuração YAML (env TEAMTASK_CONFIG)\n")
+	for _, s := range settingsTable {
+		fmt.Fprintf(&b, "  -%s\n\t%s (env %s)\n", s.flag, s.usage, s.env)
+	}
+	return b.String()
+}
+
+func loadSettingsFile(path string, required bool, settings *Settings) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !required && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("arquivo de configuração: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(settings); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("arquivo de configuração %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate verifica todos os campos e reporta cada um que estiver inválido.
+func (s Settings) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if s.Database.Driver != "mysql" && s.Database.Driver != "sqlite" && s.Database.Driver != "postgres" {
+		invalid("database.driver", "banco de dados não suportado %q", s.Database.Driver)
+	}
+	if s.Database.DSN == "" {
+		invalid("database.dsn", "obrigatório")
+	}
+	if s.Database.MaxOpenConns < 0 {
+		invalid("database.maxOpenConns", "não pode ser negativo")
+	}
+	if s.Database.MaxIdleConns < 0 {
+		invalid("database.maxIdleConns", "não pode ser negativo")
+	} else if s.Database.MaxOpenConns > 0 && s.Database.MaxIdleConns > s.Database.MaxOpenConns {
+		invalid("database.maxIdleConns", "não pode passar de database.maxOpenConns (%d)", s.Database.MaxOpenConns)
+	}
+	if s.Database.ConnMaxLifetime < 0 {
+		invalid("database.connMaxLifetime", "não pode ser negativo")
+	}
+
+	if _, _, err := net.SplitHostPort(s.Server.Address); err != nil {
+		invalid("server.address", "endereço inválido %q", s.Server.Address)
+	}
+	if len(s.Server.CORSOrigins) == 0 {
+		invalid("server.corsOrigins", "informe ao menos uma origem ou \"*\"")
+	}
+	for _, origin := range s.Server.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			invalid("server.corsOrigins", "origem inválida %q", origin)
+		}
+	}
+
+	if _, err := zapcore.ParseLevel(s.Log.Level); err != nil {
+		invalid("log.level", "nível desconhecido %q", s.Log.Level)
+	}
+
+	if s.Auth.TokenSecret != "" && len(s.Auth.TokenSecret) < 32 {
+		invalid("auth.tokenSecret", "precisa ter ao menos 32 caracteres")
+	}
+	if s.Auth.AccessTokenTTL <= 0 {
+		invalid("auth.accessTokenTTL", "precisa ser positivo")
+	}
+	if s.Auth.RefreshTokenTTL <= 0 {
+		invalid("auth.refreshTokenTTL", "precisa ser positivo")
+	} else if s.Auth.RefreshTokenTTL < s.Auth.AccessTokenTTL {
+		invalid("auth.refreshTokenTTL", "não pode ser menor que auth.accessTokenTTL")
+	}
+
+	if err := s.Workflow.Validate(); err != nil {
+		invalid("workflow", "%s", strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
+	if err := s.Priorities.Validate(); err != nil {
+		invalid("priorities", "%s", strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
+	if err := s.Subtasks.Validate(); err != nil {
+		invalid("subtasks", "%s", err)
+	}
+
+	switch s.Attachments.Storage {
+	case "local":
+		if s.Attachments.Dir == "" {
+			invalid("attachments.dir", "obrigatório no armazenamento local")
+		}
+	case "s3":
+		if err := s.Attachments.S3.Validate(); err != nil {
+			invalid("attachments.s3", "%s", strings.ReplaceAll(err.Error(), "\n", "; "))
+		}
+	default:
+		invalid("attachments.storage", "armazenamento não suportado %q", s.Attachments.Storage)
+	}
+	if err := s.Attachments.AttachmentPolicy.Validate(); err != nil {
+		invalid("attachments", "%s", strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
+
+	if s.Trash.RetentionDays < 0 {
+		invalid("trash.retentionDays", "não pode ser negativo")
+	}
+	if s.Trash.RetentionDays > 0 && s.Trash.PurgeInterval <= 0 {
+		invalid("trash.purgeInterval", "precisa ser positivo")
+	}
+
+	return errors.Join(errs...)
+}
+
+// LogLevel retorna o nível de log configurado. Settings validadas nunca falham aqui.
+func (s Settings) LogLevel() zapcore.Level {
+	level, err := zapcore.ParseLevel(s.Log.Level)
+	if err != nil {
+		return zapcore.InfoLevel
+	}
+	return level
+}
+
+func parseInt(value string, target *int) error {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("número inválido %q", value)
+	}
+	*target = n
+	return nil
+}
+
+func parseInt64(value string, target *int64) error {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return fmt.Errorf("número inválido %q", value)
+	}
+	*target = n
+	return nil
+}
+
+func parseBool(value string, target *bool) error {
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("booleano inválido %q", value)
+	}
+	*target = b
+	return nil
+}
+
+func parseDuration(value string, target *time.Duration) error {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("duração inválida %q", value)
+	}
+	*target = d
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+// multipartOverhead é a folga, além do tamanho máximo do arquivo, para os cabeçalhos e
+// delimitadores do corpo multipart.
+const multipartOverhead = 64 << 10
+
+// LimitUpload recusa corpos maiores que um arquivo de maxSize bytes, antes que sejam
+// lidos por inteiro.
+func LimitUpload(maxSize int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+multipartOverhead)
+		ctx.Next()
+	}
+}
+
+// AddAttachment recebe o arquivo no campo "file" de um formulário multipart.
+func (c TaskController) AddAttachment(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.abortWithError(ctx, fmt.Errorf("%w: o corpo passou de %d bytes", service.ErrTooLarge, tooLarge.Limit))
+			return
+		}
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "envie o arquivo no campo file"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := c.service(ctx).AddAttachment(taskID, service.AttachmentUpload{Name: header.Filename, Size: header.Size, Content: file})
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, attachment)
+}
+
+func (c TaskController) ListAttachments(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	attachments, err := c.service(ctx).ListAttachments(taskID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attachments)
+}
+
+// DownloadAttachment envia o conteúdo do anexo com o tipo detectado no envio. O navegador
+// sempre baixa o arquivo, em vez de exibi-lo, e não tenta adivinhar outro tipo.
+func (c TaskController) DownloadAttachment(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	attachmentID, _ := strconv.Atoi(ctx.Param("attachmentID"))
+
+	attachment, content, err := c.service(ctx).OpenAttachment(taskID, attachmentID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+	defer content.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	ctx.Header("Content-Disposition", disposition)
+	ctx.Header("Content-Type", attachment.ContentType)
+	ctx.Header("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Status(http.StatusOK)
+
+	if _, err := io.Copy(ctx.Writer, content); err != nil {
+		// Os cabeçalhos já foram enviados; resta registrar a falha
+		c.log.Error(fmt.Sprintf("Erro ao enviar o anexo %d: %v", attachmentID, err))
+	}
+}
+
+func (c TaskController) DeleteAttachment(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	attachmentID, _ := strconv.Atoi(ctx.Param("attachmentID"))
+
+	err := c.service(ctx).DeleteAttachment(taskID, attachmentID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+// Chaves usadas para guardar a identidade do chamador no gin.Context.
+const (
+	CurrentUserKey = "currentUser"
+	SessionIDKey   = "sessionID"
+)
+
+type loginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// CurrentUser retorna o usuário autenticado da requisição, definido pelo RequireAuth.
+func CurrentUser(ctx *gin.Context) (service.User, bool) {
+	value, ok := ctx.Get(CurrentUserKey)
+	if !ok {
+		return service.User{}, false
+	}
+
+	user, ok := value.(service.User)
+	return user, ok
+}
+
+// service retorna o serviço agindo em nome do usuário autenticado, para que as
+// permissões dele sejam aplicadas.
+func (c TaskController) service(ctx *gin.Context) service.Service {
+	if user, ok := CurrentUser(ctx); ok {
+		return c.svc.AsUser(user)
+	}
+
+	return c.svc
+}
+
+// RequireAuth é o middleware que exige um access token válido no cabeçalho
+// Authorization e coloca o usuário autenticado no contexto da requisição.
+func (c TaskController) RequireAuth(ctx *gin.Context) {
+	scheme, token, found := strings.Cut(ctx.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		c.abortWithError(ctx, service.ErrUnauthorized)
+		return
+	}
+
+	user, sessionID, err := c.svc.Authenticate(token)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Set(CurrentUserKey, user)
+	ctx.Set(SessionIDKey, sessionID)
+	ctx.Next()
+}
+
+func (c TaskController) Login(ctx *gin.Context) {
+	var request loginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "e-mail e senha são obrigatórios"})
+		return
+	}
+
+	tokens, err := c.svc.Login(request.Email, request.Password)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+func (c TaskController) RefreshToken(ctx *gin.Context) {
+	var request refreshRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "refresh token é obrigatório"})
+		return
+	}
+
+	tokens, err := c.svc.RefreshSession(request.RefreshToken)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+func (c TaskController) Logout(ctx *gin.Context) {
+	err := c.svc.Logout(ctx.GetString(SessionIDKey))
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+type commentRequest struct {
+	Text     string `json:"text" binding:"required"`
+	ParentID int    `json:"parentId"`
+}
+
+func (c TaskController) CreateComment(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	var request commentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "texto do comentário é obrigatório"})
+		return
+	}
+
+	comment, err := c.service(ctx).CreateComment(taskID, service.Comment{Text: request.Text, ParentID: request.ParentID})
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+func (c TaskController) ListComments(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	offset, _ := strconv.Atoi(ctx.Query("offset"))
+
+	page, err := c.service(ctx).ListComments(taskID, limit, offset)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+func (c TaskController) EditComment(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	commentID, _ := strconv.Atoi(ctx.Param("commentID"))
+
+	var request commentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "texto do comentário é obrigatório"})
+		return
+	}
+
+	comment, err := c.service(ctx).EditComment(taskID, commentID, request.Text)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, comment)
+}
+
+func (c TaskController) DeleteComment(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	commentID, _ := strconv.Atoi(ctx.Param("commentID"))
+
+	err := c.service(ctx).DeleteComment(taskID, commentID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -15,12 +16,78 @@ type Controller interface {
 	GetVisibleTasksForUser(ctx *gin.Context)
 	FilterTasksByStatusAndPriority(ctx *gin.Context)
 	AssignMemberToTask(ctx *gin.Context)
+	UnassignMemberFromTask(ctx *gin.Context)
 	DeleteTask(ctx *gin.Context)
 	EditTask(ctx *gin.Context)
+	PatchTask(ctx *gin.Context)
+	TransitionTask(ctx *gin.Context)
+	GetTaskWorkflow(ctx *gin.Context)
 	GetAllTasks(ctx *gin.Context)
+	GetOverdueTasks(ctx *gin.Context)
+	GetUpcomingTasksForUser(ctx *gin.Context)
+	ListPriorities(ctx *gin.Context)
 	GetUserByID(ctx *gin.Context)
 	DeleteUser(ctx *gin.Context)
 	GetTaskByID(ctx *gin.Context)
+	ChangeUserRole(ctx *gin.Context)
+	GetTaskChildren(ctx *gin.Context)
+	GetTaskTree(ctx *gin.Context)
+	SetTaskParent(ctx *gin.Context)
+	GetTaskLinks(ctx *gin.Context)
+	AddTaskLink(ctx *gin.Context)
+	RemoveTaskLink(ctx *gin.Context)
+	GetTaskHistory(ctx *gin.Context)
+	AddAttachment(ctx *gin.Context)
+	ListAttachments(ctx *gin.Context)
+	DownloadAttachment(ctx *gin.Context)
+	DeleteAttachment(ctx *gin.Context)
+
+	ListTrashedTasks(ctx *gin.Context)
+	RestoreTask(ctx *gin.Context)
+	ListTrashedUsers(ctx *gin.Context)
+	RestoreUser(ctx *gin.Context)
+
+	CreateTeam(ctx *gin.Context)
+	GetTeam(ctx *gin.Context)
+	GetAllTeams(ctx *gin.Context)
+	RenameTeam(ctx *gin.Context)
+	ArchiveTeam(ctx *gin.Context)
+	UnarchiveTeam(ctx *gin.Context)
+	AddTeamMember(ctx *gin.Context)
+	RemoveTeamMember(ctx *gin.Context)
+	GetTeamTasks(ctx *gin.Context)
+
+	ListTeamLabels(ctx *gin.Context)
+	CreateLabel(ctx *gin.Context)
+	UpdateLabel(ctx *gin.Context)
+	DeleteLabel(ctx *gin.Context)
+	AddTaskLabel(ctx *gin.Context)
+	RemoveTaskLabel(ctx *gin.Context)
+
+	CreateComment(ctx *gin.Context)
+	ListComments(ctx *gin.Context)
+	EditComment(ctx *gin.Context)
+	DeleteComment(ctx *gin.Context)
+
+	ListWorkflows(ctx *gin.Context)
+	GetWorkflow(ctx *gin.Context)
+	CreateWorkflow(ctx *gin.Context)
+	UpdateWorkflow(ctx *gin.Context)
+	DeleteWorkflow(ctx *gin.Context)
+	ListWorkflowAssignments(ctx *gin.Context)
+	AssignWorkflow(ctx *gin.Context)
+	UnassignWorkflow(ctx *gin.Context)
+
+	ListNotifications(ctx *gin.Context)
+	MarkNotificationRead(ctx *gin.Context)
+	MarkAllNotificationsRead(ctx *gin.Context)
+	GetNotificationPreferences(ctx *gin.Context)
+	UpdateNotificationPreferences(ctx *gin.Context)
+
+	Login(ctx *gin.Context)
+	RefreshToken(ctx *gin.Context)
+	Logout(ctx *gin.Context)
+	RequireAuth(ctx *gin.Context)
 }
 
 type TaskController struct {
@@ -32,6 +99,8 @@ func (c TaskController) CreateTaskData(ctx *gin.Context) {
 	var request service.Task
 	if err := ctx.ShouldBindJSON(&request); err != nil {
 		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+		return
 	}
 
 	input := service.Task{
@@ -40,13 +109,18 @@ func (c TaskController) CreateTaskData(ctx *gin.Context) {
 		Description:   request.Description,
 		Priority:      request.Priority,
 		Status:        request.Status,
+		Type:          request.Type,
 		AssignedUsers: request.AssignedUsers,
+		TeamID:        request.TeamID,
+		StartDate:     request.StartDate,
+		DueDate:       request.DueDate,
+		ParentID:      request.ParentID,
 	}
 
-	task_id, err := c.svc.CreateTask(input)
+	task_id, err := c.service(ctx).CreateTask(input)
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
+		c.abortWithError(ctx, err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, task_id)
@@ -64,14 +138,15 @@ func (c TaskController) RegisterNewUser(ctx *gin.Context) {
 		Name:     request.Name,
 		Email:    request.Email,
 		Password: request.Password,
+		Role:     request.Role,
 	}
 
 	c.log.Info("CONTROLLER: " + input.Name)
 
-	user_id, err := c.svc.RegisterNewUser(input)
+	user_id, err := c.service(ctx).RegisterNewUser(input)
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
+		c.abortWithError(ctx, err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, user_id)
@@ -80,26 +155,38 @@ func (c TaskController) RegisterNewUser(ctx *gin.Context) {
 func (c TaskController) GetVisibleTasksForUser(ctx *gin.Context) {
 	userId, _ := strconv.Atoi(ctx.Param("userID"))
 
-	tasks, err := c.svc.GetVisibleTasksForUser(userId)
+	tasks, err := c.service(ctx).GetVisibleTasksForUser(userId)
 
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
+		c.abortWithError(ctx, err)
+		return
 	}
 
+	c.sortTasks(ctx, tasks)
 	ctx.JSON(http.StatusOK, tasks)
 }
 
+// FilterTasksByStatusAndPriority filtra as tarefas pelo status e pela prioridade do
+// caminho e, opcionalmente, pelas etiquetas de ?labels=1,2&labelMatch=all.
 func (c TaskController) FilterTasksByStatusAndPriority(ctx *gin.Context) {
-	status := ctx.Param("status")
-	priority := ctx.Param("priority")
+	labelIDs, matchAll, err := labelFilter(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	tasks, err := c.svc.FilterTasksByStatusAndPriority(status, priority)
+	tasks, err := c.service(ctx).FilterTasks(service.TaskFilter{
+		Status:         ctx.Param("status"),
+		Priority:       ctx.Param("priority"),
+		Labels:         labelIDs,
+		MatchAllLabels: matchAll,
+	})
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
+		c.abortWithError(ctx, err)
+		return
 	}
 
+	c.sortTasks(ctx, tasks)
 	ctx.JSON(http.StatusOK, tasks)
 }
 
@@ -107,20 +194,51 @@ func (c TaskController) AssignMemberToTask(ctx *gin.Context) {
 	userID, _ := strconv.Atoi(ctx.Param("userID"))
 	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
 
-	err := c.svc.AssignMemberToTask(taskID, userID)
+	svc, err := c.versionedService(ctx)
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	err = svc.AssignMemberToTask(taskID, userID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+}
+
+// UnassignMemberFromTask retira o usuário dos responsáveis pela tarefa.
+func (c TaskController) UnassignMemberFromTask(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	userID, _ := strconv.Atoi(ctx.Param("userID"))
+
+	svc, err := c.versionedService(ctx)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	if err := svc.UnassignMemberFromTask(taskID, userID); err != nil {
+		c.abortWithError(ctx, err)
+		return
 	}
+
+	ctx.Status(http.StatusNoContent)
 }
 
 func (c TaskController) DeleteTask(ctx *gin.Context) {
 	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
 
-	err := c.svc.DeleteTask(taskID)
+	svc, err := c.versionedService(ctx)
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	err = svc.DeleteTask(taskID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
 	}
 }
 
@@ -129,8 +247,9 @@ func (c TaskController) EditTask(ctx *gin.Context) {
 
 	var request service.Task
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		//TODO
 		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+		return
 	}
 
 	input := service.Task{
@@ -138,39 +257,163 @@ func (c TaskController) EditTask(ctx *gin.Context) {
 		Description:   request.Description,
 		Priority:      request.Priority,
 		Status:        request.Status,
+		Type:          request.Type,
+		Resolution:    request.Resolution,
 		AssignedUsers: request.AssignedUsers,
+		TeamID:        request.TeamID,
+		StartDate:     request.StartDate,
+		DueDate:       request.DueDate,
 	}
 
-	err := c.svc.EditTask(taskID, input)
+	svc, err := c.versionedService(ctx)
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
+		c.abortWithError(ctx, err)
+		return
 	}
 
+	task, err := svc.EditTask(taskID, input)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	setTaskETag(ctx, task.Version)
+	ctx.JSON(http.StatusOK, task)
 }
 
-func (c TaskController) GetAllTasks(ctx *gin.Context) {
-	tasks, err := c.svc.GetAllTasks()
+// PatchTask altera parte da tarefa. O corpo é um JSON Merge Patch
+// (application/merge-patch+json, ou application/json) ou um JSON Patch
+// (application/json-patch+json); a resposta é a tarefa atualizada, com a nova ETag.
+func (c TaskController) PatchTask(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	body, err := ctx.GetRawData()
 	if err != nil {
 		c.log.Error(err.Error())
-		ctx.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+		return
+	}
+
+	var patch service.TaskPatch
+	switch ctx.ContentType() {
+	case "application/merge-patch+json", "application/json", "":
+		patch, err = service.MergePatch(body)
+	case "application/json-patch+json":
+		patch, err = service.JSONPatch(body)
+	default:
+		err = fmt.Errorf("%w: use application/merge-patch+json ou application/json-patch+json", service.ErrUnsupportedType)
+	}
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
 	}
+
+	svc, err := c.versionedService(ctx)
 	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	task, err := svc.PatchTask(taskID, patch)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	setTaskETag(ctx, task.Version)
+	ctx.JSON(http.StatusOK, task)
+}
+
+// TransitionTask muda o status da tarefa pelo fluxo de trabalho e retorna a tarefa atualizada.
+func (c TaskController) TransitionTask(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	var request service.TaskTransition
+	if err := ctx.ShouldBindJSON(&request); err != nil {
 		c.log.Error(err.Error())
-		ctx.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+		return
+	}
+
+	task, err := c.service(ctx).TransitionTask(taskID, request)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	setTaskETag(ctx, task.Version)
+	ctx.JSON(http.StatusOK, task)
+}
+
+// GetAllTasks lista todas as tarefas; com ?labels=1,2 apenas as que têm uma das
+// etiquetas, ou todas elas com labelMatch=all.
+func (c TaskController) GetAllTasks(ctx *gin.Context) {
+	labelIDs, matchAll, err := labelFilter(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var tasks []service.Task
+	if len(labelIDs) > 0 {
+		tasks, err = c.service(ctx).FilterTasks(service.TaskFilter{Labels: labelIDs, MatchAllLabels: matchAll})
+	} else {
+		tasks, err = c.service(ctx).GetAllTasks()
+	}
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	c.sortTasks(ctx, tasks)
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+func (c TaskController) GetOverdueTasks(ctx *gin.Context) {
+	tasks, err := c.service(ctx).GetOverdueTasks()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// GetUpcomingTasksForUser lista as tarefas do usuário com prazo nos próximos dias
+// (parâmetro days, padrão 7).
+func (c TaskController) GetUpcomingTasksForUser(ctx *gin.Context) {
+	userID, _ := strconv.Atoi(ctx.Param("userID"))
+	days, _ := strconv.Atoi(ctx.DefaultQuery("days", "7"))
+
+	tasks, err := c.service(ctx).GetUpcomingTasksForUser(userID, days)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, tasks)
 }
 
+// ListPriorities lista as prioridades aceitas, da mais à menos urgente.
+func (c TaskController) ListPriorities(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service(ctx).ListPriorities())
+}
+
+// sortTasks ordena as tarefas da resposta da mais à menos urgente quando a requisição
+// pede ?sort=priority; sem o parâmetro a ordem é a do serviço.
+func (c TaskController) sortTasks(ctx *gin.Context, tasks []service.Task) {
+	if ctx.Query("sort") == "priority" {
+		service.SortTasksByPriority(tasks, c.service(ctx).ListPriorities())
+	}
+}
+
 func (c TaskController) GetUserByID(ctx *gin.Context) {
 	userId, _ := strconv.Atoi(ctx.Param("userID"))
 
-	user, err := c.svc.GetUserByID(userId)
+	user, err := c.service(ctx).GetUserByID(userId)
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
-
+		c.abortWithError(ctx, err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, user)
@@ -179,23 +422,44 @@ func (c TaskController) GetUserByID(ctx *gin.Context) {
 func (c TaskController) DeleteUser(ctx *gin.Context) {
 	userId, _ := strconv.Atoi(ctx.Param("userID"))
 
-	err := c.svc.DeleteUser(userId)
+	err := c.service(ctx).DeleteUser(userId)
 	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+}
+
+func (c TaskController) ChangeUserRole(ctx *gin.Context) {
+	userId, _ := strconv.Atoi(ctx.Param("userID"))
+
+	var request struct {
+		Role service.Role `json:"role" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
 		c.log.Error(err.Error())
-		ctx.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "papel é obrigatório"})
+		return
+	}
 
+	err := c.service(ctx).ChangeUserRole(userId, request.Role)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
 	}
+
+	ctx.Status(http.StatusNoContent)
 }
 
 func (c TaskController) GetTaskByID(ctx *gin.Context) {
 	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
 
-	task, err := c.svc.GetTaskByID(taskID)
+	task, err := c.service(ctx).GetTaskByID(taskID)
 	if err != nil {
-		c.log.Error(err.Error())
-		ctx.Error(err)
+		c.abortWithError(ctx, err)
+		return
 	}
 
+	setTaskETag(ctx, task.Version)
 	ctx.JSON(http.StatusOK, task)
 }
 
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+// errorStatus traduz os erros conhecidos do serviço para o status HTTP correspondente.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials), errors.Is(err, service.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, service.ErrConflict), errors.Is(err, service.ErrInvalidTransition):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	}
+
+	return http.StatusInternalServerError
+}
+
+// abortWithError registra o erro e encerra a requisição com o status adequado. Num
+// conflito de versão a resposta traz a versão atual da tarefa, também como ETag.
+func (c TaskController) abortWithError(ctx *gin.Context, err error) {
+	c.log.Error(err.Error())
+	_ = ctx.Error(err)
+
+	body := gin.H{"error": err.Error()}
+	var stale *service.VersionError
+	if errors.As(err, &stale) {
+		setTaskETag(ctx, stale.Current)
+		body["version"] = stale.Current
+	}
+	ctx.AbortWithStatusJSON(errorStatus(err), body)
+}
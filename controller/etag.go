@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+// taskETag é a ETag forte de uma tarefa: a sua versão entre aspas.
+func taskETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// setTaskETag informa a versão da tarefa no cabeçalho ETag da resposta.
+func setTaskETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", taskETag(version))
+}
+
+// versionedService retorna o serviço do usuário atual restrito à versão do cabeçalho
+// If-Match. Sem o cabeçalho, ou com "*", qualquer versão é aceita.
+func (c TaskController) versionedService(ctx *gin.Context) (service.Service, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return c.service(ctx), nil
+	}
+
+	value, quoted := strings.CutPrefix(header, `"`)
+	value, closed := strings.CutSuffix(value, `"`)
+	version, err := strconv.Atoi(value)
+	if !quoted || !closed || err != nil || version < 1 {
+		return nil, fmt.Errorf("%w: If-Match deve ser uma ETag de tarefa, como \"3\"", service.ErrValidation)
+	}
+
+	return c.service(ctx).IfMatch(version), nil
+}
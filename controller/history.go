@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTaskHistory retorna a linha do tempo da tarefa, do evento mais antigo ao mais novo.
+func (c TaskController) GetTaskHistory(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	events, err := c.service(ctx).GetTaskHistory(taskID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+type labelRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+func (c TaskController) ListTeamLabels(ctx *gin.Context) {
+	teamID, _ := strconv.Atoi(ctx.Param("teamID"))
+
+	labels, err := c.service(ctx).ListTeamLabels(teamID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, labels)
+}
+
+func (c TaskController) CreateLabel(ctx *gin.Context) {
+	teamID, _ := strconv.Atoi(ctx.Param("teamID"))
+
+	var request labelRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "nome da etiqueta é obrigatório"})
+		return
+	}
+
+	labelID, err := c.service(ctx).CreateLabel(service.Label{TeamID: teamID, Name: request.Name, Color: request.Color})
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, labelID)
+}
+
+func (c TaskController) UpdateLabel(ctx *gin.Context) {
+	labelID, _ := strconv.Atoi(ctx.Param("labelID"))
+
+	var request labelRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "nome da etiqueta é obrigatório"})
+		return
+	}
+
+	err := c.service(ctx).UpdateLabel(labelID, service.Label{Name: request.Name, Color: request.Color})
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) DeleteLabel(ctx *gin.Context) {
+	labelID, _ := strconv.Atoi(ctx.Param("labelID"))
+
+	err := c.service(ctx).DeleteLabel(labelID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) AddTaskLabel(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	labelID, _ := strconv.Atoi(ctx.Param("labelID"))
+
+	err := c.service(ctx).AddTaskLabel(taskID, labelID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) RemoveTaskLabel(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	labelID, _ := strconv.Atoi(ctx.Param("labelID"))
+
+	err := c.service(ctx).RemoveTaskLabel(taskID, labelID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// labelFilter lê os parâmetros labels (IDs separados por vírgula) e labelMatch (any, o
+// padrão, ou all) das listagens de tarefas.
+func labelFilter(ctx *gin.Context) ([]int, bool, error) {
+	var labelIDs []int
+	if raw := ctx.Query("labels"); raw != "" {
+		for _, field := range strings.Split(raw, ",") {
+			labelID, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return nil, false, errors.New("labels deve ser uma lista de IDs separados por vírgula")
+			}
+			labelIDs = append(labelIDs, labelID)
+		}
+	}
+
+	switch ctx.DefaultQuery("labelMatch", "any") {
+	case "any":
+		return labelIDs, false, nil
+	case "all":
+		return labelIDs, true, nil
+	}
+
+	return nil, false, errors.New("labelMatch deve ser any ou all")
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c TaskController) GetTaskLinks(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	links, err := c.service(ctx).GetTaskLinks(taskID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, links)
+}
+
+// AddTaskLink registra que a tarefa da rota bloqueia a tarefa blockedID.
+func (c TaskController) AddTaskLink(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	blockedID, _ := strconv.Atoi(ctx.Param("blockedID"))
+
+	err := c.service(ctx).AddTaskLink(taskID, blockedID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) RemoveTaskLink(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+	blockedID, _ := strconv.Atoi(ctx.Param("blockedID"))
+
+	err := c.service(ctx).RemoveTaskLink(taskID, blockedID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
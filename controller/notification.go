@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+func (c TaskController) ListNotifications(ctx *gin.Context) {
+	unreadOnly, _ := strconv.ParseBool(ctx.Query("unread"))
+
+	notifications, err := c.service(ctx).ListNotifications(unreadOnly)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+func (c TaskController) MarkNotificationRead(ctx *gin.Context) {
+	notificationID, _ := strconv.Atoi(ctx.Param("notificationID"))
+
+	err := c.service(ctx).MarkNotificationRead(notificationID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) MarkAllNotificationsRead(ctx *gin.Context) {
+	err := c.service(ctx).MarkAllNotificationsRead()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) GetNotificationPreferences(ctx *gin.Context) {
+	prefs, err := c.service(ctx).GetNotificationPreferences()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, prefs)
+}
+
+func (c TaskController) UpdateNotificationPreferences(ctx *gin.Context) {
+	var request map[service.NotificationType]bool
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "preferências inválidas"})
+		return
+	}
+
+	err := c.service(ctx).UpdateNotificationPreferences(request)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	c.GetNotificationPreferences(ctx)
+}
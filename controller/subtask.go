@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type parentRequest struct {
+	ParentID *int `json:"parentId" binding:"required"` // zero move a tarefa para o primeiro nível
+}
+
+func (c TaskController) GetTaskChildren(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	children, err := c.service(ctx).GetTaskChildren(taskID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, children)
+}
+
+func (c TaskController) GetTaskTree(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	tree, err := c.service(ctx).GetTaskTree(taskID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tree)
+}
+
+func (c TaskController) SetTaskParent(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	var request parentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "parentId é obrigatório"})
+		return
+	}
+
+	svc, err := c.versionedService(ctx)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	task, err := svc.SetTaskParent(taskID, *request.ParentID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	setTaskETag(ctx, task.Version)
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+type teamNameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type teamMemberRequest struct {
+	Role service.TeamRole `json:"role"`
+}
+
+func (c TaskController) CreateTeam(ctx *gin.Context) {
+	var request teamNameRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "nome da equipe é obrigatório"})
+		return
+	}
+
+	teamID, err := c.service(ctx).CreateTeam(service.Team{Name: request.Name})
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, teamID)
+}
+
+func (c TaskController) GetTeam(ctx *gin.Context) {
+	teamID, _ := strconv.Atoi(ctx.Param("teamID"))
+
+	team, err := c.service(ctx).GetTeam(teamID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, team)
+}
+
+func (c TaskController) GetAllTeams(ctx *gin.Context) {
+	teams, err := c.service(ctx).GetAllTeams()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, teams)
+}
+
+func (c TaskController) RenameTeam(ctx *gin.Context) {
+	teamID, _ := strconv.Atoi(ctx.Param("teamID"))
+
+	var request teamNameRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "nome da equipe é obrigatório"})
+		return
+	}
+
+	err := c.service(ctx).RenameTeam(teamID, request.Name)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) ArchiveTeam(ctx *gin.Context) {
+	c.setTeamArchived(ctx, true)
+}
+
+func (c TaskController) UnarchiveTeam(ctx *gin.Context) {
+	c.setTeamArchived(ctx, false)
+}
+
+func (c TaskController) setTeamArchived(ctx *gin.Context, archived bool) {
+	teamID, _ := strconv.Atoi(ctx.Param("teamID"))
+
+	err := c.service(ctx).SetTeamArchived(teamID, archived)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) AddTeamMember(ctx *gin.Context) {
+	teamID, _ := strconv.Atoi(ctx.Param("teamID"))
+	userID, _ := strconv.Atoi(ctx.Param("userID"))
+
+	// O corpo é opcional; sem ele o usuário entra como membro comum
+	var request teamMemberRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			c.log.Error(err.Error())
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+			return
+		}
+	}
+
+	err := c.service(ctx).AddTeamMember(teamID, userID, request.Role)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) RemoveTeamMember(ctx *gin.Context) {
+	teamID, _ := strconv.Atoi(ctx.Param("teamID"))
+	userID, _ := strconv.Atoi(ctx.Param("userID"))
+
+	err := c.service(ctx).RemoveTeamMember(teamID, userID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) GetTeamTasks(ctx *gin.Context) {
+	teamID, _ := strconv.Atoi(ctx.Param("teamID"))
+
+	tasks, err := c.service(ctx).GetTasksForTeam(teamID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	c.sortTasks(ctx, tasks)
+	ctx.JSON(http.StatusOK, tasks)
+}
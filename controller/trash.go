@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTrashedTasks lista as tarefas da lixeira, das excluídas há mais tempo às mais recentes.
+func (c TaskController) ListTrashedTasks(ctx *gin.Context) {
+	tasks, err := c.service(ctx).ListTrashedTasks()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// RestoreTask tira a tarefa da lixeira.
+func (c TaskController) RestoreTask(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	if err := c.service(ctx).RestoreTask(taskID); err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListTrashedUsers lista os usuários da lixeira, dos excluídos há mais tempo aos mais recentes.
+func (c TaskController) ListTrashedUsers(ctx *gin.Context) {
+	users, err := c.service(ctx).ListTrashedUsers()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, users)
+}
+
+// RestoreUser tira o usuário da lixeira.
+func (c TaskController) RestoreUser(ctx *gin.Context) {
+	userID, _ := strconv.Atoi(ctx.Param("userID"))
+
+	if err := c.service(ctx).RestoreUser(userID); err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
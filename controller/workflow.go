@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mclcavalcante/teamTask/services"
+)
+
+// workflowRequest é o corpo de PUT /workflow/:workflowID. StatusMapping informa o novo
+// status das tarefas que estão em status renomeados ou removidos.
+type workflowRequest struct {
+	service.Workflow
+	StatusMapping map[string]string `json:"statusMapping"`
+}
+
+type workflowAssignmentRequest struct {
+	service.WorkflowAssignment
+	StatusMapping map[string]string `json:"statusMapping"`
+}
+
+type statusMappingRequest struct {
+	StatusMapping map[string]string `json:"statusMapping"`
+}
+
+func (c TaskController) ListWorkflows(ctx *gin.Context) {
+	workflows, err := c.service(ctx).ListWorkflows()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workflows)
+}
+
+func (c TaskController) GetWorkflow(ctx *gin.Context) {
+	workflowID, _ := strconv.Atoi(ctx.Param("workflowID"))
+
+	workflow, err := c.service(ctx).GetWorkflow(workflowID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workflow)
+}
+
+func (c TaskController) CreateWorkflow(ctx *gin.Context) {
+	var request service.Workflow
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+		return
+	}
+
+	workflowID, err := c.service(ctx).CreateWorkflow(request)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workflowID)
+}
+
+func (c TaskController) UpdateWorkflow(ctx *gin.Context) {
+	workflowID, _ := strconv.Atoi(ctx.Param("workflowID"))
+
+	var request workflowRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+		return
+	}
+
+	err := c.service(ctx).UpdateWorkflow(workflowID, request.Workflow, request.StatusMapping)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) DeleteWorkflow(ctx *gin.Context) {
+	workflowID, _ := strconv.Atoi(ctx.Param("workflowID"))
+
+	err := c.service(ctx).DeleteWorkflow(workflowID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) ListWorkflowAssignments(ctx *gin.Context) {
+	assignments, err := c.service(ctx).ListWorkflowAssignments()
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assignments)
+}
+
+func (c TaskController) AssignWorkflow(ctx *gin.Context) {
+	var request workflowAssignmentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.log.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+		return
+	}
+
+	err := c.service(ctx).AssignWorkflow(request.WorkflowAssignment, request.StatusMapping)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnassignWorkflow remove a atribuição indicada pelos parâmetros teamId e taskType. O
+// corpo, opcional, traz o statusMapping das tarefas que mudam de fluxo.
+func (c TaskController) UnassignWorkflow(ctx *gin.Context) {
+	teamID, _ := strconv.Atoi(ctx.Query("teamId"))
+	taskType := ctx.Query("taskType")
+
+	var request statusMappingRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			c.log.Error(err.Error())
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "corpo da requisição inválido"})
+			return
+		}
+	}
+
+	err := c.service(ctx).UnassignWorkflow(teamID, taskType, request.StatusMapping)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c TaskController) GetTaskWorkflow(ctx *gin.Context) {
+	taskID, _ := strconv.Atoi(ctx.Param("taskID"))
+
+	workflow, err := c.service(ctx).GetTaskWorkflow(taskID)
+	if err != nil {
+		c.abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workflow)
+}
@@ -2,14 +2,18 @@ package main
 
 import (
 	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"time"
 
-	// "os"
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/mclcavalcante/teamTask/config"
 	"github.com/mclcavalcante/teamTask/controller"
+	"github.com/mclcavalcante/teamTask/repository"
 	"github.com/mclcavalcante/teamTask/router"
 	"github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/storage"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -19,10 +23,20 @@ func init() {
 }
 
 func main() {
+	settings, args, err := config.LoadSettings(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "uso: teamtask [flags] [migrate up|status|down]\n\n%s", config.SettingsUsage())
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
 	core := zapcore.NewCore(
 		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
 		os.Stdout,
-		zap.NewAtomicLevel(),
+		zap.NewAtomicLevelAt(settings.LogLevel()),
 	)
 
 	logger := zap.New(core, zap.AddCaller())
@@ -30,32 +44,104 @@ func main() {
 		_ = logger.Sync()
 	}(logger)
 
-	db := ConnectDB(logger)
+	dialect := repository.Dialect(settings.Database.Driver)
+	db := ConnectDB(dialect, settings.Database, logger)
+
+	// teamtask migrate up|status|down
+	if len(args) > 0 && args[0] == "migrate" {
+		os.Exit(runMigrate(db, dialect, logger, args[1:]))
+	}
+
+	if settings.Database.AutoMigrate {
+		if err := autoMigrate(db, dialect, logger); err != nil {
+			logger.Fatal("Falha ao migrar o banco de dados", zap.Error(err))
+		}
+	}
 
-	repo := NewRepository(db, logger)
-	svc := service.NewService(repo, logger)
+	repo := repository.NewRepository(db, dialect, logger)
+	opts := []service.Option{
+		service.WithTokenTTL(settings.Auth.AccessTokenTTL, settings.Auth.RefreshTokenTTL),
+		service.WithWorkflow(settings.Workflow),
+		service.WithPriorities(settings.Priorities),
+		service.WithSubtaskPolicy(settings.Subtasks),
+		service.WithLinkPolicy(settings.Dependencies),
+		service.WithAttachmentStore(newBlobStore(settings.Attachments, logger)),
+		service.WithAttachmentPolicy(settings.Attachments.AttachmentPolicy),
+	}
+	if settings.Auth.TokenSecret != "" {
+		opts = append(opts, service.WithTokenSecret([]byte(settings.Auth.TokenSecret)))
+	}
+	svc := service.NewService(repo, logger, opts...)
 	controller := controller.ControllerInit(svc, logger)
 
-	app := config.NewInitialization(repo, svc, controller)
+	app := config.NewInitialization(repo, svc, controller, settings)
 
 	router := router.Init(app)
 
+	go purgeTrash(svc, settings.Trash, logger)
+
 	// router.Static("/", "./ui")
 
-	router.Run(":8000")
+	if err := router.Run(settings.Server.Address); err != nil {
+		logger.Fatal("Falha ao iniciar o servidor", zap.Error(err))
+	}
+}
 
+// purgeTrash apaga, a cada intervalo configurado, o que está na lixeira há mais tempo
+// que o período de retenção.
+func purgeTrash(svc service.Service, settings config.TrashSettings, logger *zap.Logger) {
+	if settings.RetentionDays == 0 {
+		return
+	}
+	retention := time.Duration(settings.RetentionDays) * 24 * time.Hour
+
+	ticker := time.NewTicker(settings.PurgeInterval)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		tasks, users, err := svc.PurgeTrash(time.Now().UTC().Add(-retention))
+		if err != nil {
+			logger.Error("Falha ao limpar a lixeira", zap.Error(err))
+		}
+		if tasks > 0 || users > 0 {
+			logger.Info("Lixeira limpa", zap.Int("tasks", tasks), zap.Int("users", users))
+		}
+	}
 }
 
-func ConnectDB(logger *zap.Logger) (db *sql.DB) {
+// newBlobStore cria o armazenamento de anexos configurado.
+func newBlobStore(settings config.AttachmentSettings, logger *zap.Logger) service.BlobStore {
+	if settings.Storage == "s3" {
+		store, err := storage.NewS3(settings.S3)
+		if err != nil {
+			logger.Fatal("Falha ao configurar o armazenamento S3", zap.Error(err))
+		}
+		return store
+	}
+
+	store, err := storage.NewLocal(settings.Dir)
+	if err != nil {
+		logger.Fatal("Falha ao criar o diretório de anexos", zap.Error(err))
+	}
+	return store
+}
+
+func ConnectDB(dialect repository.Dialect, settings config.DatabaseSettings, logger *zap.Logger) (db *sql.DB) {
 	// Configure the database connection (always check errors)
-	db, err := sql.Open("mysql", "root:M@roca2002@(127.0.0.1:3306)/teamtask?parseTime=true")
+	db, err := repository.Open(dialect, settings.DSN)
 	if err != nil {
-		logger.Error("Failed to connect to mysql", zap.Error(err))
+		logger.Fatal("Failed to connect to "+string(dialect), zap.Error(err))
+	}
+
+	// O SQLite trabalha com uma única conexão, definida em repository.Open
+	if dialect != repository.SQLite {
+		db.SetMaxOpenConns(settings.MaxOpenConns)
 	}
+	db.SetMaxIdleConns(settings.MaxIdleConns)
+	db.SetConnMaxLifetime(settings.ConnMaxLifetime)
 
 	pingErr := db.Ping()
 	if pingErr != nil {
-		logger.Error("Failed to ping to mysql", zap.Error(err))
+		logger.Fatal("Failed to ping "+string(dialect), zap.Error(pingErr))
 	}
 	logger.Info("DB Connected!")
 
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/mclcavalcante/teamTask/migrations"
+	"github.com/mclcavalcante/teamTask/repository"
+	"go.uber.org/zap"
+)
+
+const migrateUsage = "uso: teamtask migrate up|status|down"
+
+// runMigrate executa o subcomando "migrate" e retorna o código de saída do processo.
+func runMigrate(db *sql.DB, dialect repository.Dialect, logger *zap.Logger, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, err := migrations.New(db, string(dialect), logger)
+	if err != nil {
+		logger.Error("Falha ao carregar as migrações", zap.Error(err))
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			fmt.Printf("aplicada %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			logger.Error("Falha ao aplicar as migrações", zap.Error(err))
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("esquema já está atualizado")
+		}
+
+	case "down":
+		reverted, err := migrator.Down()
+		if err != nil {
+			logger.Error("Falha ao desfazer a migração", zap.Error(err))
+			return 1
+		}
+		fmt.Printf("desfeita %04d_%s\n", reverted.Version, reverted.Name)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			logger.Error("Falha ao consultar as migrações", zap.Error(err))
+			return 1
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSÃO\tNOME\tAPLICADA EM")
+		for _, s := range statuses {
+			appliedAt := "pendente"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		_ = w.Flush()
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
+
+// autoMigrate aplica as migrações pendentes na inicialização do servidor.
+func autoMigrate(db *sql.DB, dialect repository.Dialect, logger *zap.Logger) error {
+	migrator, err := migrations.New(db, string(dialect), logger)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		return err
+	}
+	logger.Info("Migrações aplicadas", zap.Int("count", len(applied)))
+
+	return nil
+}
@@ -0,0 +1,335 @@
+// Package migrations aplica as migrações versionadas do esquema, embutidas no binário.
+//
+// Cada migração é um par de arquivos NNNN_nome.up.sql e NNNN_nome.down.sql, em um
+// diretório por banco de dados suportado, com as mesmas versões em todos eles. As
+// migrações aplicadas ficam registradas na tabela schema_migrations.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//go:embed mysql/*.sql sqlite/*.sql postgres/*.sql
+var files embed.FS
+
+// Migration é uma versão do esquema.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string // vazio quando a migração não pode ser desfeita
+}
+
+// Status descreve uma migração e se ela já foi aplicada.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator aplica e desfaz migrações em um banco de dados.
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	log        *zap.Logger
+	migrations []Migration
+}
+
+// New cria um Migrator com as migrações embutidas no binário para o banco informado
+// ("mysql", "sqlite" ou "postgres").
+func New(db *sql.DB, dialect string, logger *zap.Logger) (*Migrator, error) {
+	migrations, err := Load(files, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("migrações para %q: %w", dialect, err)
+	}
+
+	return &Migrator{db: db, dialect: dialect, log: logger, migrations: migrations}, nil
+}
+
+// Load lê as migrações do diretório informado, ordenadas pela versão.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("nome de migração inválido: %s", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("versão de migração inválida: %s", name)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("versão %d usada por duas migrações: %s e %s", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migração %04d_%s sem arquivo .up.sql", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Up aplica, em ordem, todas as migrações pendentes e retorna as que foram aplicadas.
+// Um banco criado pelo antigo sql_scripts/ddl.sql, com as tabelas e sem nenhuma migração
+// registrada, é adotado na versão 1, o esquema daquele script, e segue pelas seguintes.
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	if len(applied) == 0 && len(m.migrations) > 0 && m.migrations[0].Version == 1 && m.hasTasksTable() {
+		baseline := m.migrations[0]
+		m.log.Info("Adotando banco criado pelo sql_scripts/ddl.sql", zap.Int("version", baseline.Version), zap.String("name", baseline.Name))
+		now := time.Now().UTC()
+		if _, err := m.db.Exec(m.bind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"), baseline.Version, baseline.Name, now); err != nil {
+			return nil, fmt.Errorf("migração %04d_%s: %w", baseline.Version, baseline.Name, err)
+		}
+		applied[baseline.Version] = now
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		m.log.Info("Aplicando migração", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+		err := m.run(migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec(m.bind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"), migration.Version, migration.Name, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migração %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down desfaz a última migração aplicada e a retorna.
+func (m *Migrator) Down() (Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return Migration{}, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return Migration{}, fmt.Errorf("migração %04d_%s não pode ser desfeita", migration.Version, migration.Name)
+		}
+
+		m.log.Info("Desfazendo migração", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+		err := m.run(migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec(m.bind("DELETE FROM schema_migrations WHERE version = ?"), migration.Version)
+			return err
+		})
+		if err != nil {
+			return Migration{}, fmt.Errorf("migração %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		return migration, nil
+	}
+
+	return Migration{}, errors.New("nenhuma migração aplicada")
+}
+
+// Status lista todas as migrações conhecidas e quando cada uma foi aplicada.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if at, ok := applied[migration.Version]; ok {
+			at := at
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// applied cria a tabela de controle, se preciso, e retorna as versões já aplicadas.
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	timestampType := "DATETIME"
+	if m.dialect == "postgres" {
+		timestampType = "TIMESTAMPTZ"
+	}
+	_, err := m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at ` + timestampType + ` NOT NULL
+)`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// hasTasksTable informa se a tabela de tarefas já existe. Basta a consulta falhar para
+// saber que não existe, sem depender do catálogo de cada banco.
+func (m *Migrator) hasTasksTable() bool {
+	rows, err := m.db.Query("SELECT id FROM Tasks WHERE 1 = 0")
+	if err != nil {
+		return false
+	}
+	_ = rows.Close()
+	return true
+}
+
+// run executa o script comando a comando e registra o resultado em uma transação. No
+// MySQL comandos DDL confirmam a transação implicitamente, por isso cada migração deve
+// ser pequena o bastante para ser corrigida à mão se falhar no meio; no SQLite a
+// migração inteira é desfeita.
+func (m *Migrator) run(script string, record func(tx *sql.Tx) error) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range SplitStatements(script) {
+		if _, err := tx.Exec(statement); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if err := record(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// bind adapta os placeholders "?" ao Postgres, que usa $1, $2...
+func (m *Migrator) bind(query string) string {
+	if m.dialect != "postgres" {
+		return query
+	}
+
+	for n := 1; strings.Contains(query, "?"); n++ {
+		query = strings.Replace(query, "?", "$"+strconv.Itoa(n), 1)
+	}
+	return query
+}
+
+// SplitStatements divide um script SQL nos comandos separados por ponto e vírgula,
+// ignorando comentários de linha e pontos e vírgulas dentro de strings.
+func SplitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote rune
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if quote != 0 {
+			current.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			current.WriteRune(r)
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+		case r == ';':
+			if statement := strings.TrimSpace(current.String()); statement != "" {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS Task_user_associations;
+DROP TABLE IF EXISTS Notificacao;
+DROP TABLE IF EXISTS Comentario;
+DROP TABLE IF EXISTS Equipe;
+DROP TABLE IF EXISTS Tasks;
+DROP TABLE IF EXISTS User;
@@ -1,9 +1,5 @@
-DROP TABLE IF EXISTS Equipe;
-DROP TABLE IF EXISTS Comentario;
-DROP TABLE IF EXISTS Notificacao;
-DROP TABLE IF EXISTS Task_user_associations;
-DROP TABLE IF EXISTS User;
-DROP TABLE IF EXISTS Tasks;
+-- Esquema inicial, o mesmo do antigo sql_scripts/ddl.sql sem os DROP TABLE. Bancos
+-- criados por aquele script são adotados nesta versão e seguem pelas migrações seguintes.
 
 -- Tabela Usuário
 CREATE TABLE User (
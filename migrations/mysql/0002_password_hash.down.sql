@@ -0,0 +1 @@
+ALTER TABLE User MODIFY password VARCHAR(100);
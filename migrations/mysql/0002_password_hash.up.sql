@@ -0,0 +1,3 @@
+-- As senhas passam a ser gravadas como hash no formato do algoritmo, ex.:
+-- $argon2id$v=19$m=...,t=...,p=...$salt$hash, mais longo que os 100 caracteres de antes.
+ALTER TABLE User MODIFY password VARCHAR(255);
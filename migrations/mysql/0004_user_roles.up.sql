@@ -0,0 +1,2 @@
+-- Papel global do usuário: admin, manager, member ou viewer
+ALTER TABLE User ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'member';
@@ -0,0 +1,20 @@
+-- Equipes com nome e arquivamento, seus membros e a equipe de cada tarefa. Equipes que
+-- já existiam ficam sem nome até serem renomeadas.
+ALTER TABLE Equipe
+    ADD COLUMN nome VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN arquivada BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Tabela de associação entre Equipe e Usuário, com o papel do usuário na equipe
+CREATE TABLE Equipe_membros (
+    equipe_id INT,
+    user_id INT,
+    -- papel na equipe: lead ou member
+    papel VARCHAR(20) NOT NULL DEFAULT 'member',
+    PRIMARY KEY (equipe_id, user_id),
+    FOREIGN KEY (equipe_id) REFERENCES Equipe(equipe_id),
+    FOREIGN KEY (user_id) REFERENCES User(id)
+);
+
+ALTER TABLE Tasks
+    ADD COLUMN team_id INT NULL,
+    ADD CONSTRAINT tasks_team FOREIGN KEY (team_id) REFERENCES Equipe(equipe_id);
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS Comentario_mencoes;
+
+ALTER TABLE Comentario
+    DROP FOREIGN KEY comentario_parent,
+    DROP FOREIGN KEY comentario_autor,
+    DROP FOREIGN KEY comentario_tarefa;
+
+ALTER TABLE Comentario
+    DROP COLUMN editado_em,
+    DROP COLUMN criado_em,
+    DROP COLUMN parent_id,
+    DROP COLUMN autor_id;
+
+ALTER TABLE Comentario ADD CONSTRAINT Comentario_ibfk_1 FOREIGN KEY (tarefa_id) REFERENCES Tasks(id);
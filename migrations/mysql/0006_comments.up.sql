@@ -0,0 +1,27 @@
+-- Comentários com autor, datas e respostas, que apontam para o comentário raiz em
+-- parent_id. Os comentários passam a ser apagados com a tarefa; os que já existiam
+-- ficam sem autor e com a data desta migração.
+ALTER TABLE Comentario
+    ADD COLUMN autor_id INT NULL,
+    ADD COLUMN parent_id INT NULL,
+    ADD COLUMN criado_em DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN editado_em DATETIME NULL;
+
+ALTER TABLE Comentario ALTER COLUMN criado_em DROP DEFAULT;
+
+-- Comentario_ibfk_1 é o nome dado pelo MySQL à chave sem nome do esquema inicial
+ALTER TABLE Comentario DROP FOREIGN KEY Comentario_ibfk_1;
+
+ALTER TABLE Comentario
+    ADD CONSTRAINT comentario_tarefa FOREIGN KEY (tarefa_id) REFERENCES Tasks(id) ON DELETE CASCADE,
+    ADD CONSTRAINT comentario_autor FOREIGN KEY (autor_id) REFERENCES User(id) ON DELETE SET NULL,
+    ADD CONSTRAINT comentario_parent FOREIGN KEY (parent_id) REFERENCES Comentario(comentario_id) ON DELETE CASCADE;
+
+-- Usuários mencionados em cada comentário
+CREATE TABLE Comentario_mencoes (
+    comentario_id INT,
+    user_id INT,
+    PRIMARY KEY (comentario_id, user_id),
+    FOREIGN KEY (comentario_id) REFERENCES Comentario(comentario_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES User(id) ON DELETE CASCADE
+);
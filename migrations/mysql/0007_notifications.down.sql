@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS Notificacao_preferencias;
+
+ALTER TABLE Notificacao
+    DROP FOREIGN KEY notificacao_tarefa,
+    DROP FOREIGN KEY notificacao_destinatario;
+
+ALTER TABLE Notificacao
+    DROP COLUMN criada_em,
+    DROP COLUMN lida,
+    DROP COLUMN tarefa_id;
+
+ALTER TABLE Notificacao ADD CONSTRAINT Notificacao_ibfk_1 FOREIGN KEY (destinatario_id) REFERENCES User(id);
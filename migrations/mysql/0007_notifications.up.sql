@@ -0,0 +1,25 @@
+-- Notificações com categoria (assignment, status_change, comment ou mention), tarefa,
+-- leitura e data, apagadas com o destinatário ou a tarefa. As que já existiam ficam com
+-- a data desta migração.
+ALTER TABLE Notificacao
+    ADD COLUMN tarefa_id INT NULL,
+    ADD COLUMN lida BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN criada_em DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE Notificacao ALTER COLUMN criada_em DROP DEFAULT;
+
+-- Notificacao_ibfk_1 é o nome dado pelo MySQL à chave sem nome do esquema inicial
+ALTER TABLE Notificacao DROP FOREIGN KEY Notificacao_ibfk_1;
+
+ALTER TABLE Notificacao
+    ADD CONSTRAINT notificacao_destinatario FOREIGN KEY (destinatario_id) REFERENCES User(id) ON DELETE CASCADE,
+    ADD CONSTRAINT notificacao_tarefa FOREIGN KEY (tarefa_id) REFERENCES Tasks(id) ON DELETE CASCADE;
+
+-- Categorias de notificação que cada usuário ativou ou desativou
+CREATE TABLE Notificacao_preferencias (
+    user_id INT,
+    tipo VARCHAR(50),
+    habilitado BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, tipo),
+    FOREIGN KEY (user_id) REFERENCES User(id) ON DELETE CASCADE
+);
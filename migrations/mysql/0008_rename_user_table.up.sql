@@ -0,0 +1,3 @@
+-- "user" é palavra reservada no Postgres; todos os bancos passam a usar "users".
+-- As chaves estrangeiras das outras tabelas acompanham a renomeação.
+RENAME TABLE User TO users;
@@ -0,0 +1,8 @@
+DROP INDEX tasks_due_date ON Tasks;
+
+ALTER TABLE Tasks
+    DROP COLUMN start_date,
+    DROP COLUMN due_date,
+    DROP COLUMN created_at,
+    DROP COLUMN updated_at,
+    DROP COLUMN completed_at;
@@ -0,0 +1,10 @@
+-- Datas das tarefas. Tarefas já existentes recebem a data da migração como criação e
+-- última alteração.
+ALTER TABLE Tasks
+    ADD COLUMN start_date DATETIME NULL,
+    ADD COLUMN due_date DATETIME NULL,
+    ADD COLUMN created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN completed_at DATETIME NULL;
+
+CREATE INDEX tasks_due_date ON Tasks (due_date);
@@ -0,0 +1,4 @@
+ALTER TABLE Tasks DROP COLUMN task_type;
+
+DROP TABLE Workflow_assignments;
+DROP TABLE Workflows;
@@ -0,0 +1,19 @@
+-- Fluxos de trabalho cadastrados pela API. A definição (estados e transições) é gravada
+-- em JSON; sem atribuição, as tarefas seguem o fluxo da configuração.
+CREATE TABLE Workflows (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    definition TEXT NOT NULL
+);
+
+-- Fluxo usado por uma equipe, por um tipo de tarefa ou pelos dois. team_id NULL vale para
+-- todas as equipes e task_type vazio para todos os tipos.
+CREATE TABLE Workflow_assignments (
+    workflow_id INT NOT NULL,
+    team_id INT NULL,
+    task_type VARCHAR(50) NOT NULL DEFAULT '',
+    FOREIGN KEY (workflow_id) REFERENCES Workflows(id),
+    FOREIGN KEY (team_id) REFERENCES Equipe(equipe_id)
+);
+
+ALTER TABLE Tasks ADD COLUMN task_type VARCHAR(50) NOT NULL DEFAULT '';
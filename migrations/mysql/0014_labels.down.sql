@@ -0,0 +1,2 @@
+DROP TABLE Task_labels;
+DROP TABLE Labels;
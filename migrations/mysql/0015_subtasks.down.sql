@@ -0,0 +1,2 @@
+ALTER TABLE Tasks DROP FOREIGN KEY tasks_parent;
+ALTER TABLE Tasks DROP COLUMN parent_id;
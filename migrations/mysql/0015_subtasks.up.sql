@@ -0,0 +1,5 @@
+-- Tarefa pai de cada subtarefa. Ao excluir a tarefa pai as subtarefas passam a ser
+-- tarefas de primeiro nível.
+ALTER TABLE Tasks
+    ADD COLUMN parent_id INT NULL,
+    ADD CONSTRAINT tasks_parent FOREIGN KEY (parent_id) REFERENCES Tasks(id) ON DELETE SET NULL;
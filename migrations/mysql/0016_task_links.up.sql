@@ -0,0 +1,10 @@
+-- Dependências entre tarefas: blocker_id bloqueia blocked_id. Ciclos e dependências de
+-- uma tarefa com ela mesma são recusados pelo serviço; o MySQL não aceita CHECK em
+-- colunas de chaves estrangeiras com ON DELETE CASCADE.
+CREATE TABLE Task_links (
+    blocker_id INT NOT NULL,
+    blocked_id INT NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES Tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES Tasks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+DROP INDEX users_deleted_at ON users;
+DROP INDEX tasks_deleted_at ON Tasks;
+
+ALTER TABLE users DROP COLUMN deleted_at;
+ALTER TABLE Tasks DROP COLUMN deleted_at;
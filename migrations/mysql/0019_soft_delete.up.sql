@@ -0,0 +1,7 @@
+-- Lixeira: tarefas e usuários excluídos recebem deleted_at e saem das consultas, mas
+-- continuam no banco até serem restaurados ou apagados de vez pela limpeza da lixeira.
+ALTER TABLE Tasks ADD COLUMN deleted_at DATETIME NULL;
+ALTER TABLE users ADD COLUMN deleted_at DATETIME NULL;
+
+CREATE INDEX tasks_deleted_at ON Tasks (deleted_at);
+CREATE INDEX users_deleted_at ON users (deleted_at);
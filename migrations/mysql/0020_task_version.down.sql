@@ -0,0 +1 @@
+ALTER TABLE Tasks DROP COLUMN version;
@@ -0,0 +1,50 @@
+-- Esquema inicial, o mesmo de mysql/0001_init.up.sql com os tipos do Postgres. A tabela
+-- de usuários já nasce como "users", nome que os outros bancos recebem na migração 0008.
+
+-- Tabela Usuário
+CREATE TABLE users (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(255),
+    email VARCHAR(100),
+    password VARCHAR(100)
+);
+
+-- Tabela Tarefa
+CREATE TABLE Tasks (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    title VARCHAR(255),
+    description TEXT,
+    status VARCHAR(50),
+    priority VARCHAR(50)
+);
+
+-- Tabela Equipe
+CREATE TABLE Equipe (
+    equipe_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY
+);
+
+-- Tabela Comentário
+CREATE TABLE Comentario (
+    comentario_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    texto TEXT,
+    tarefa_id INT,
+    FOREIGN KEY (tarefa_id) REFERENCES Tasks(id)
+);
+
+-- Tabela Notificação
+CREATE TABLE Notificacao (
+    notificacao_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tipo VARCHAR(50),
+    conteudo TEXT,
+    destinatario_id INT,
+    FOREIGN KEY (destinatario_id) REFERENCES users(id)
+);
+
+-- Tabela de associação entre Usuário e Tarefa (muitos para muitos)
+CREATE TABLE Task_user_associations (
+    user_id INT,
+    task_id INT,
+    PRIMARY KEY (user_id, task_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (task_id) REFERENCES Tasks(id)
+);
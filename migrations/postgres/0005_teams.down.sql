@@ -0,0 +1,5 @@
+ALTER TABLE Tasks DROP COLUMN team_id;
+
+DROP TABLE IF EXISTS Equipe_membros;
+
+ALTER TABLE Equipe DROP COLUMN arquivada, DROP COLUMN nome;
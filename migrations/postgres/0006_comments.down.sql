@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS Comentario_mencoes;
+
+ALTER TABLE Comentario
+    DROP CONSTRAINT comentario_tarefa_id_fkey,
+    ADD CONSTRAINT comentario_tarefa_id_fkey FOREIGN KEY (tarefa_id) REFERENCES Tasks(id);
+
+ALTER TABLE Comentario
+    DROP COLUMN editado_em,
+    DROP COLUMN criado_em,
+    DROP COLUMN parent_id,
+    DROP COLUMN autor_id;
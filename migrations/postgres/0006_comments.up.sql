@@ -0,0 +1,24 @@
+-- Comentários com autor, datas e respostas, que apontam para o comentário raiz em
+-- parent_id. Os comentários passam a ser apagados com a tarefa; os que já existiam
+-- ficam sem autor e com a data desta migração.
+ALTER TABLE Comentario
+    ADD COLUMN autor_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN parent_id INT NULL REFERENCES Comentario(comentario_id) ON DELETE CASCADE,
+    ADD COLUMN criado_em TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN editado_em TIMESTAMPTZ NULL;
+
+ALTER TABLE Comentario ALTER COLUMN criado_em DROP DEFAULT;
+
+-- comentario_tarefa_id_fkey é o nome dado pelo Postgres à chave sem nome do esquema inicial
+ALTER TABLE Comentario
+    DROP CONSTRAINT comentario_tarefa_id_fkey,
+    ADD CONSTRAINT comentario_tarefa_id_fkey FOREIGN KEY (tarefa_id) REFERENCES Tasks(id) ON DELETE CASCADE;
+
+-- Usuários mencionados em cada comentário
+CREATE TABLE Comentario_mencoes (
+    comentario_id INT,
+    user_id INT,
+    PRIMARY KEY (comentario_id, user_id),
+    FOREIGN KEY (comentario_id) REFERENCES Comentario(comentario_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
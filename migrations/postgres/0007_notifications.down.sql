@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS Notificacao_preferencias;
+
+ALTER TABLE Notificacao
+    DROP CONSTRAINT notificacao_destinatario_id_fkey,
+    ADD CONSTRAINT notificacao_destinatario_id_fkey FOREIGN KEY (destinatario_id) REFERENCES users(id);
+
+ALTER TABLE Notificacao
+    DROP COLUMN criada_em,
+    DROP COLUMN lida,
+    DROP COLUMN tarefa_id;
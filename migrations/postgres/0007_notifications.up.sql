@@ -0,0 +1,24 @@
+-- Notificações com categoria (assignment, status_change, comment ou mention), tarefa,
+-- leitura e data, apagadas com o destinatário ou a tarefa. As que já existiam ficam com
+-- a data desta migração.
+ALTER TABLE Notificacao
+    ADD COLUMN tarefa_id INT NULL REFERENCES Tasks(id) ON DELETE CASCADE,
+    ADD COLUMN lida BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN criada_em TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE Notificacao ALTER COLUMN criada_em DROP DEFAULT;
+
+-- notificacao_destinatario_id_fkey é o nome dado pelo Postgres à chave sem nome do
+-- esquema inicial
+ALTER TABLE Notificacao
+    DROP CONSTRAINT notificacao_destinatario_id_fkey,
+    ADD CONSTRAINT notificacao_destinatario_id_fkey FOREIGN KEY (destinatario_id) REFERENCES users(id) ON DELETE CASCADE;
+
+-- Categorias de notificação que cada usuário ativou ou desativou
+CREATE TABLE Notificacao_preferencias (
+    user_id INT,
+    tipo VARCHAR(50),
+    habilitado BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, tipo),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
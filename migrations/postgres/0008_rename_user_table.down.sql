@@ -0,0 +1,2 @@
+-- No Postgres a tabela já nasce como "users" (0001); esta versão existe apenas para
+-- manter a numeração igual em todos os bancos.
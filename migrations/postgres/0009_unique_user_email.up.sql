@@ -0,0 +1,3 @@
+-- Um e-mail identifica um único usuário. Bancos com e-mails repetidos precisam ser
+-- corrigidos à mão antes desta migração.
+CREATE UNIQUE INDEX users_email_unique ON users (email);
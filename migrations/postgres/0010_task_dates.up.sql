@@ -0,0 +1,10 @@
+-- Datas das tarefas. Tarefas já existentes recebem a data da migração como criação e
+-- última alteração.
+ALTER TABLE Tasks
+    ADD COLUMN start_date TIMESTAMPTZ NULL,
+    ADD COLUMN due_date TIMESTAMPTZ NULL,
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN completed_at TIMESTAMPTZ NULL;
+
+CREATE INDEX tasks_due_date ON Tasks (due_date);
@@ -0,0 +1,2 @@
+-- Resolução informada ao concluir uma tarefa pelo fluxo de trabalho.
+ALTER TABLE Tasks ADD COLUMN resolution VARCHAR(255) NOT NULL DEFAULT '';
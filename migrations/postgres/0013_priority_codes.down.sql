@@ -0,0 +1,3 @@
+UPDATE Tasks SET priority = 'Alta' WHERE priority = 'High';
+UPDATE Tasks SET priority = 'Média' WHERE priority = 'Medium';
+UPDATE Tasks SET priority = 'Baixa' WHERE priority = 'Low';
@@ -0,0 +1,4 @@
+-- Prioridades passam a ser gravadas pelo código estável em vez do nome exibido.
+UPDATE Tasks SET priority = 'High' WHERE priority IN ('Alta', 'alta', 'ALTA', 'high', 'HIGH');
+UPDATE Tasks SET priority = 'Medium' WHERE priority IN ('Média', 'média', 'MÉDIA', 'Media', 'media', 'medium', 'MEDIUM');
+UPDATE Tasks SET priority = 'Low' WHERE priority IN ('Baixa', 'baixa', 'BAIXA', 'low', 'LOW');
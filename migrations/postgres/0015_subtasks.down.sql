@@ -0,0 +1,3 @@
+DROP INDEX tasks_parent_id;
+
+ALTER TABLE Tasks DROP COLUMN parent_id;
@@ -0,0 +1,5 @@
+-- Tarefa pai de cada subtarefa. Ao excluir a tarefa pai as subtarefas passam a ser
+-- tarefas de primeiro nível.
+ALTER TABLE Tasks ADD COLUMN parent_id INT NULL REFERENCES Tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX tasks_parent_id ON Tasks (parent_id);
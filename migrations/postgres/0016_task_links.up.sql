@@ -0,0 +1,12 @@
+-- Dependências entre tarefas: blocker_id bloqueia blocked_id. Ciclos são recusados pelo
+-- serviço.
+CREATE TABLE Task_links (
+    blocker_id INT NOT NULL,
+    blocked_id INT NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES Tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES Tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX task_links_blocked_id ON Task_links (blocked_id);
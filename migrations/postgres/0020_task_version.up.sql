@@ -0,0 +1,3 @@
+-- Versão de cada tarefa, incrementada a cada alteração. Uma gravação só acontece se a
+-- tarefa ainda estiver na versão lida, para que edições simultâneas não se sobrescrevam.
+ALTER TABLE Tasks ADD COLUMN version INT NOT NULL DEFAULT 1;
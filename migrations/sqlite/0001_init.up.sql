@@ -0,0 +1,49 @@
+-- Esquema inicial, o mesmo de mysql/0001_init.up.sql com os tipos do SQLite.
+
+-- Tabela Usuário
+CREATE TABLE User (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255),
+    email VARCHAR(100),
+    password VARCHAR(100)
+);
+
+-- Tabela Tarefa
+CREATE TABLE Tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255),
+    description TEXT,
+    status VARCHAR(50),
+    priority VARCHAR(50)
+);
+
+-- Tabela Equipe
+CREATE TABLE Equipe (
+    equipe_id INTEGER PRIMARY KEY AUTOINCREMENT
+);
+
+-- Tabela Comentário
+CREATE TABLE Comentario (
+    comentario_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    texto TEXT,
+    tarefa_id INT,
+    FOREIGN KEY (tarefa_id) REFERENCES Tasks(id)
+);
+
+-- Tabela Notificação
+CREATE TABLE Notificacao (
+    notificacao_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tipo VARCHAR(50),
+    conteudo TEXT,
+    destinatario_id INT,
+    FOREIGN KEY (destinatario_id) REFERENCES User(id)
+);
+
+-- Tabela de associação entre Usuário e Tarefa (muitos para muitos)
+CREATE TABLE Task_user_associations (
+    user_id INT,
+    task_id INT,
+    PRIMARY KEY (user_id, task_id),
+    FOREIGN KEY (user_id) REFERENCES User(id),
+    FOREIGN KEY (task_id) REFERENCES Tasks(id)
+);
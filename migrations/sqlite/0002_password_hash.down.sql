@@ -0,0 +1,2 @@
+-- As senhas passam a ser gravadas como hash. O SQLite não limita o tamanho de VARCHAR;
+-- esta versão existe apenas para manter a numeração igual em todos os bancos.
@@ -0,0 +1 @@
+ALTER TABLE User DROP COLUMN role;
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS Comentario_mencoes;
+
+CREATE TABLE Comentario_antigo (
+    comentario_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    texto TEXT,
+    tarefa_id INT,
+    FOREIGN KEY (tarefa_id) REFERENCES Tasks(id)
+);
+
+INSERT INTO Comentario_antigo (comentario_id, texto, tarefa_id)
+SELECT comentario_id, texto, tarefa_id FROM Comentario;
+
+DROP TABLE Comentario;
+ALTER TABLE Comentario_antigo RENAME TO Comentario;
@@ -0,0 +1,31 @@
+-- Comentários com autor, datas e respostas, que apontam para o comentário raiz em
+-- parent_id. Os comentários passam a ser apagados com a tarefa; os que já existiam
+-- ficam sem autor e com a data desta migração. O SQLite não altera chaves estrangeiras,
+-- por isso a tabela é recriada.
+CREATE TABLE Comentario_novo (
+    comentario_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    texto TEXT,
+    tarefa_id INT,
+    autor_id INT NULL,
+    parent_id INT NULL,
+    criado_em DATETIME NOT NULL,
+    editado_em DATETIME NULL,
+    FOREIGN KEY (tarefa_id) REFERENCES Tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (autor_id) REFERENCES User(id) ON DELETE SET NULL,
+    FOREIGN KEY (parent_id) REFERENCES Comentario(comentario_id) ON DELETE CASCADE
+);
+
+INSERT INTO Comentario_novo (comentario_id, texto, tarefa_id, criado_em)
+SELECT comentario_id, texto, tarefa_id, CURRENT_TIMESTAMP FROM Comentario;
+
+DROP TABLE Comentario;
+ALTER TABLE Comentario_novo RENAME TO Comentario;
+
+-- Usuários mencionados em cada comentário
+CREATE TABLE Comentario_mencoes (
+    comentario_id INT,
+    user_id INT,
+    PRIMARY KEY (comentario_id, user_id),
+    FOREIGN KEY (comentario_id) REFERENCES Comentario(comentario_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES User(id) ON DELETE CASCADE
+);
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS Notificacao_preferencias;
+
+CREATE TABLE Notificacao_antiga (
+    notificacao_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tipo VARCHAR(50),
+    conteudo TEXT,
+    destinatario_id INT,
+    FOREIGN KEY (destinatario_id) REFERENCES User(id)
+);
+
+INSERT INTO Notificacao_antiga (notificacao_id, tipo, conteudo, destinatario_id)
+SELECT notificacao_id, tipo, conteudo, destinatario_id FROM Notificacao;
+
+DROP TABLE Notificacao;
+ALTER TABLE Notificacao_antiga RENAME TO Notificacao;
@@ -0,0 +1,30 @@
+-- Notificações com categoria (assignment, status_change, comment ou mention), tarefa,
+-- leitura e data, apagadas com o destinatário ou a tarefa. As que já existiam ficam com
+-- a data desta migração. O SQLite não altera chaves estrangeiras, por isso a tabela é
+-- recriada.
+CREATE TABLE Notificacao_nova (
+    notificacao_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tipo VARCHAR(50),
+    conteudo TEXT,
+    destinatario_id INT,
+    tarefa_id INT NULL,
+    lida BOOLEAN NOT NULL DEFAULT FALSE,
+    criada_em DATETIME NOT NULL,
+    FOREIGN KEY (destinatario_id) REFERENCES User(id) ON DELETE CASCADE,
+    FOREIGN KEY (tarefa_id) REFERENCES Tasks(id) ON DELETE CASCADE
+);
+
+INSERT INTO Notificacao_nova (notificacao_id, tipo, conteudo, destinatario_id, criada_em)
+SELECT notificacao_id, tipo, conteudo, destinatario_id, CURRENT_TIMESTAMP FROM Notificacao;
+
+DROP TABLE Notificacao;
+ALTER TABLE Notificacao_nova RENAME TO Notificacao;
+
+-- Categorias de notificação que cada usuário ativou ou desativou
+CREATE TABLE Notificacao_preferencias (
+    user_id INT,
+    tipo VARCHAR(50),
+    habilitado BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, tipo),
+    FOREIGN KEY (user_id) REFERENCES User(id) ON DELETE CASCADE
+);
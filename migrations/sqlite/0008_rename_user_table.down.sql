@@ -0,0 +1 @@
+ALTER TABLE users RENAME TO User;
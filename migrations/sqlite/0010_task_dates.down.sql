@@ -0,0 +1,7 @@
+DROP INDEX tasks_due_date;
+
+ALTER TABLE Tasks DROP COLUMN start_date;
+ALTER TABLE Tasks DROP COLUMN due_date;
+ALTER TABLE Tasks DROP COLUMN created_at;
+ALTER TABLE Tasks DROP COLUMN updated_at;
+ALTER TABLE Tasks DROP COLUMN completed_at;
@@ -0,0 +1,10 @@
+-- Datas das tarefas. O SQLite não aceita CURRENT_TIMESTAMP como padrão em ADD COLUMN,
+-- então as tarefas já existentes recebem a data da migração por UPDATE.
+ALTER TABLE Tasks ADD COLUMN start_date DATETIME NULL;
+ALTER TABLE Tasks ADD COLUMN due_date DATETIME NULL;
+ALTER TABLE Tasks ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE Tasks ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE Tasks ADD COLUMN completed_at DATETIME NULL;
+UPDATE Tasks SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
+
+CREATE INDEX tasks_due_date ON Tasks (due_date);
@@ -0,0 +1,19 @@
+-- Etiquetas de cada equipe. O nome é único dentro da equipe e a cor é gravada como
+-- #rrggbb.
+CREATE TABLE Labels (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7) NOT NULL,
+    UNIQUE (team_id, name),
+    FOREIGN KEY (team_id) REFERENCES Equipe(equipe_id)
+);
+
+-- Etiquetas aplicadas às tarefas (muitos para muitos)
+CREATE TABLE Task_labels (
+    task_id INT NOT NULL,
+    label_id INT NOT NULL,
+    PRIMARY KEY (task_id, label_id),
+    FOREIGN KEY (task_id) REFERENCES Tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES Labels(id) ON DELETE CASCADE
+);
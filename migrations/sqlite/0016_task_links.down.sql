@@ -0,0 +1 @@
+DROP TABLE Task_links;
@@ -0,0 +1,16 @@
+-- Arquivos anexados às tarefas. O conteúdo fica no armazenamento configurado, sob
+-- storage_key; aqui ficam apenas os dados do arquivo.
+CREATE TABLE Attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INT NOT NULL,
+    uploader_id INT NULL,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (task_id) REFERENCES Tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploader_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX attachments_task_id ON Attachments (task_id);
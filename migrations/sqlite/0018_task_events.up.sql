@@ -0,0 +1,13 @@
+-- Histórico das tarefas. Os eventos nunca são alterados e não têm chaves estrangeiras:
+-- continuam gravados depois que a tarefa ou o autor são excluídos. changes guarda, em
+-- JSON, os campos alterados com os valores antes e depois.
+CREATE TABLE Task_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INT NOT NULL,
+    actor_id INT NULL,
+    kind VARCHAR(20) NOT NULL,
+    changes TEXT NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX task_events_task_id ON Task_events (task_id);
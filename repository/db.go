@@ -0,0 +1,662 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"go.uber.org/zap"
+)
+
+// querier é satisfeito por *sql.DB e *sql.Tx, para que os mesmos métodos rodem
+// dentro ou fora de uma transação.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// Database representa a camada de acesso ao banco de dados.
+type Database struct {
+	db      *sql.DB
+	q       querier // db, ou a transação em andamento
+	tx      *sql.Tx
+	dialect Dialect
+	log     *zap.Logger
+}
+
+// NewDatabase cria uma nova instância da camada de acesso ao banco de dados.
+func NewDatabase(db *sql.DB, dialect Dialect) *Database {
+	return &Database{db: db, q: boundQuerier{db, dialect}, dialect: dialect, log: zap.NewNop()}
+}
+
+// WithinTransaction executa fn com um repositório cujas operações fazem parte de uma
+// única transação: ela é confirmada se fn retornar nil e desfeita caso contrário.
+// Chamadas aninhadas participam da transação já aberta.
+func (d *Database) WithinTransaction(fn func(repo service.Repository) error) error {
+	return d.inTx(func(tx *Database) error {
+		return fn(tx)
+	})
+}
+
+// inTx é a versão interna de WithinTransaction, usada pelos métodos com vários comandos.
+func (d *Database) inTx(fn func(tx *Database) error) (err error) {
+	if d.tx != nil {
+		return fn(d)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(&Database{db: d.db, q: boundQuerier{tx, d.dialect}, tx: tx, dialect: d.dialect, log: d.log})
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			d.log.Error("Erro ao desfazer transação: " + rbErr.Error())
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insert executa um INSERT e retorna o ID gerado na coluna idColumn. O Postgres não
+// implementa LastInsertId, então nele o ID vem de RETURNING.
+func (d *Database) insert(idColumn, query string, args ...any) (int, error) {
+	if d.dialect == Postgres {
+		var id int
+		err := d.q.QueryRow(query+" RETURNING "+idColumn, args...).Scan(&id)
+		return id, err
+	}
+
+	result, err := d.q.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// found executa uma consulta "SELECT 1 ..." e informa se ela retornou alguma linha.
+func (d *Database) found(query string, args ...any) (bool, error) {
+	var one int
+	err := d.q.QueryRow(query, args...).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// require retorna service.ErrNotFound, com a descrição informada, se a consulta
+// "SELECT 1 ..." não retornar nenhuma linha.
+func (d *Database) require(what, query string, args ...any) error {
+	ok, err := d.found(query, args...)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s", service.ErrNotFound, what)
+	}
+
+	return nil
+}
+
+// affected confirma que um UPDATE ou DELETE encontrou a linha que deveria alterar,
+// retornando service.ErrNotFound caso contrário. O MySQL conta as linhas modificadas, e
+// não as encontradas, por isso zero linhas afetadas é conferido com a consulta check.
+func (d *Database) affected(result sql.Result, check string, args ...any) error {
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	ok, err := d.found(check, args...)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return service.ErrNotFound
+	}
+
+	return nil
+}
+
+// requireTeam verifica a equipe de uma tarefa; zero significa tarefa sem equipe.
+func (d *Database) requireTeam(teamID int) error {
+	if teamID == 0 {
+		return nil
+	}
+	return d.require("equipe não encontrada", "SELECT 1 FROM Equipe WHERE equipe_id = ?", teamID)
+}
+
+// requireParent verifica a tarefa pai de uma subtarefa; zero significa tarefa de
+// primeiro nível.
+func (d *Database) requireParent(parentID int) error {
+	if parentID == 0 {
+		return nil
+	}
+	return d.require("tarefa pai não encontrada", "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NULL", parentID)
+}
+
+// GetUserByEmail busca um usuário no banco de dados pelo seu e-mail.
+func (d *Database) GetUserByEmail(email string) (service.User, error) {
+	query := "SELECT id, name, email, password, role FROM users WHERE email = ? AND deleted_at IS NULL"
+	row := d.q.QueryRow(query, email)
+
+	var user service.User
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.User{}, service.ErrNotFound
+		}
+		return service.User{}, err
+	}
+
+	return user, nil
+}
+
+// AddUser adiciona um novo usuário ao banco de dados.
+func (d *Database) AddUser(user service.User) (int, error) {
+	query := "INSERT INTO users (name, email, password, role) VALUES (?, ?, ?, ?)"
+	id, err := d.insert("id", query, user.Name, user.Email, user.Password, user.Role)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return 0, service.ErrConflict
+		}
+		return 0, err
+	}
+	d.log.Info(strconv.Itoa(id))
+
+	return id, nil
+}
+
+// UpdateUserPassword substitui o hash da senha de um usuário.
+func (d *Database) UpdateUserPassword(userID int, passwordHash string) error {
+	result, err := d.q.Exec("UPDATE users SET password = ? WHERE id = ? AND deleted_at IS NULL", passwordHash, userID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL", userID)
+}
+
+// UpdateUserRole altera o papel de um usuário.
+func (d *Database) UpdateUserRole(userID int, role service.Role) error {
+	result, err := d.q.Exec("UPDATE users SET role = ? WHERE id = ? AND deleted_at IS NULL", role, userID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL", userID)
+}
+
+// CountUsers retorna o número de usuários cadastrados.
+func (d *Database) CountUsers() (int, error) {
+	var count int
+	err := d.q.QueryRow("SELECT COUNT(*) FROM users").Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetUserById busca um usuário no banco de dados pelo seu ID.
+func (d *Database) GetUserById(id int) (service.User, error) {
+	query := "SELECT name, email, password, role FROM users WHERE id = ? AND deleted_at IS NULL"
+	row := d.q.QueryRow(query, id)
+
+	var user service.User
+	err := row.Scan(&user.Name, &user.Email, &user.Password, &user.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.User{}, service.ErrNotFound
+		}
+		return service.User{}, err
+	}
+
+	user.ID = id
+	return user, nil
+}
+
+// taskColumns lista as colunas lidas por scanTask, na mesma ordem.
+const taskColumns = "id, title, description, status, priority, team_id, start_date, due_date, created_at, updated_at, completed_at, resolution, task_type, parent_id, deleted_at, version"
+
+// rowScanner é satisfeito por *sql.Row e *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask lê uma tarefa selecionada com taskColumns.
+func scanTask(row rowScanner) (service.Task, error) {
+	var task service.Task
+	var teamID, parentID sql.NullInt64
+	var startDate, dueDate, completedAt, deletedAt sql.NullTime
+	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority, &teamID,
+		&startDate, &dueDate, &task.CreatedAt, &task.UpdatedAt, &completedAt, &task.Resolution, &task.Type, &parentID, &deletedAt, &task.Version)
+	if err != nil {
+		return service.Task{}, err
+	}
+	task.TeamID = int(teamID.Int64)
+	task.ParentID = int(parentID.Int64)
+	task.StartDate = timeOrNil(startDate)
+	task.DueDate = timeOrNil(dueDate)
+	task.CompletedAt = timeOrNil(completedAt)
+	task.DeletedAt = timeOrNil(deletedAt)
+
+	return task, nil
+}
+
+// scanTasks lê todas as tarefas de uma consulta que seleciona taskColumns.
+func scanTasks(rows *sql.Rows) ([]service.Task, error) {
+	defer rows.Close()
+
+	var tasks []service.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// timeOrNil converte uma data opcional lida do banco.
+func timeOrNil(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// nullableID grava zero como NULL nas colunas de chave estrangeira opcionais.
+func nullableID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// CreateTask cria uma nova tarefa no banco de dados e retorna o ID da tarefa criada.
+func (d *Database) CreateTask(task service.Task) (int, error) {
+	// A tarefa e suas atribuições são gravadas juntas: se uma atribuição falhar, nada é gravado
+	var taskID int
+	err := d.inTx(func(tx *Database) error {
+		if err := tx.requireTeam(task.TeamID); err != nil {
+			return err
+		}
+		if err := tx.requireParent(task.ParentID); err != nil {
+			return err
+		}
+
+		var err error
+		query := "INSERT INTO Tasks (title, description, status, priority, team_id, start_date, due_date, created_at, updated_at, completed_at, resolution, task_type, parent_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		taskID, err = tx.insert("id", query, task.Title, task.Description, task.Status, task.Priority, nullableID(task.TeamID),
+			task.StartDate, task.DueDate, task.CreatedAt, task.UpdatedAt, task.CompletedAt, task.Resolution, task.Type, nullableID(task.ParentID))
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		// Atribuir a tarefa aos usuários associados
+		for _, userID := range task.AssignedUsers {
+			d.log.Info("assigned: " + strconv.Itoa(userID))
+			err := tx.AssignTaskToUser(taskID, userID)
+			if err != nil {
+				d.log.Error(err.Error())
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return taskID, nil
+}
+
+// AssignTaskToUser atribui uma tarefa a um usuário no banco de dados.
+func (d *Database) AssignTaskToUser(taskID int, userID int) error {
+	// Implementação para associar uma tarefa a um usuário no banco de dados
+
+	err := d.require("tarefa não encontrada", "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NULL", taskID)
+	if err != nil {
+		return err
+	}
+
+	user, err := d.GetUserById(userID)
+	if err != nil {
+		return fmt.Errorf("membro da equipe não encontrado: %w", err)
+	}
+
+	d.log.Info("usr: " + user.Name)
+	_, err = d.q.Exec("INSERT INTO Task_user_associations (task_id, user_id) VALUES (?, ?)", taskID, userID)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return fmt.Errorf("%w: membro ja está associado", service.ErrConflict)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetTaskByID retorna os detalhes de uma tarefa com base no ID da tarefa fornecido.
+func (d *Database) GetTaskByID(taskID int) (service.Task, error) {
+	task, err := scanTask(d.q.QueryRow("SELECT "+taskColumns+" FROM Tasks WHERE id = ? AND deleted_at IS NULL", taskID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Task{}, service.ErrNotFound
+		}
+		return service.Task{}, err
+	}
+
+	return task, nil
+}
+
+// GetUserByID busca um usuário no banco de dados pelo seu ID.
+func (d *Database) GetUserByID(id int) (service.User, error) {
+	query := "SELECT name, email, password, role FROM users WHERE id = ? AND deleted_at IS NULL"
+	row := d.q.QueryRow(query, id)
+
+	var user service.User
+	err := row.Scan(&user.Name, &user.Email, &user.Password, &user.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.User{}, service.ErrNotFound
+		}
+		return service.User{}, err
+	}
+
+	user.ID = id
+	return user, nil
+}
+
+// GetTasksForUser retorna todas as tarefas atribuídas ao usuário especificado.
+func (d *Database) GetTasksForUser(userID int) ([]service.Task, error) {
+	query := "SELECT " + taskColumns + " FROM Tasks WHERE id IN (SELECT task_id FROM Task_user_associations WHERE user_id = ?) AND deleted_at IS NULL ORDER BY id"
+	rows, err := d.q.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTasks(rows)
+}
+
+// GetTaskAssignees retorna os IDs dos usuários atribuídos à tarefa.
+func (d *Database) GetTaskAssignees(taskID int) ([]int, error) {
+	rows, err := d.q.Query("SELECT user_id FROM Task_user_associations WHERE task_id = ? AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL) ORDER BY user_id", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// GetAssigneesForTasks retorna os IDs dos usuários atribuídos a cada tarefa informada,
+// em ordem de ID.
+func (d *Database) GetAssigneesForTasks(taskIDs []int) (map[int][]int, error) {
+	assignees := make(map[int][]int)
+	for start := 0; start < len(taskIDs); start += labelBatch {
+		batch := taskIDs[start:min(start+labelBatch, len(taskIDs))]
+
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		query := "SELECT task_id, user_id FROM Task_user_associations WHERE task_id IN (?" + strings.Repeat(", ?", len(batch)-1) + ")" +
+			" AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL) ORDER BY task_id, user_id"
+
+		rows, err := d.q.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var taskID, userID int
+			if err := rows.Scan(&taskID, &userID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			assignees[taskID] = append(assignees[taskID], userID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return assignees, nil
+}
+
+// UnassignTaskFromUser retira o usuário dos responsáveis pela tarefa.
+func (d *Database) UnassignTaskFromUser(taskID, userID int) error {
+	result, err := d.q.Exec("DELETE FROM Task_user_associations WHERE task_id = ? AND user_id = ? AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)", taskID, userID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: o usuário não está atribuído à tarefa", service.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetAllTasks retorna todas as tarefas armazenadas no banco de dados.
+func (d *Database) GetAllTasks() ([]service.Task, error) {
+	query := "SELECT " + taskColumns + " FROM Tasks WHERE deleted_at IS NULL ORDER BY id"
+	rows, err := d.q.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTasks(rows)
+}
+
+// GetTaskChildren retorna as subtarefas diretas da tarefa, em ordem de ID.
+func (d *Database) GetTaskChildren(parentID int) ([]service.Task, error) {
+	query := "SELECT " + taskColumns + " FROM Tasks WHERE parent_id = ? AND deleted_at IS NULL ORDER BY id"
+	rows, err := d.q.Query(query, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTasks(rows)
+}
+
+// GetOverdueTasks retorna as tarefas não concluídas cujo prazo terminou antes de now,
+// da mais atrasada à menos atrasada.
+func (d *Database) GetOverdueTasks(now time.Time) ([]service.Task, error) {
+	query := "SELECT " + taskColumns + " FROM Tasks WHERE due_date < ? AND completed_at IS NULL AND deleted_at IS NULL ORDER BY due_date, id"
+	rows, err := d.q.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTasks(rows)
+}
+
+// DeleteTask apaga de vez uma tarefa do banco de dados com o ID especificado.
+func (d *Database) DeleteTask(taskID int) error {
+	// As atribuições e a tarefa são excluídas juntas
+	return d.inTx(func(tx *Database) error {
+		// Preparar a declaração SQL para excluir as atribuições da tarefa
+		_, err := tx.q.Exec("DELETE FROM Task_user_associations WHERE task_id = ?", taskID)
+		if err != nil {
+			d.log.Info(err.Error())
+			return err
+		}
+
+		// Preparar a declaração SQL para excluir a tarefa
+		result, err := tx.q.Exec("DELETE FROM Tasks WHERE id = ?", taskID)
+		if err != nil {
+			d.log.Info(err.Error())
+			return err
+		}
+
+		return tx.affected(result, "SELECT 1 FROM Tasks WHERE id = ?", taskID)
+	})
+}
+
+// UpdateTask atualiza uma tarefa existente no banco de dados.
+func (d *Database) UpdateTask(taskID int, updatedTask service.Task) error {
+	if err := d.requireTeam(updatedTask.TeamID); err != nil {
+		return err
+	}
+	if err := d.requireParent(updatedTask.ParentID); err != nil {
+		return err
+	}
+
+	// Preparar a declaração SQL para atualizar a tarefa
+	// A data de criação não muda; a versão só avança se ainda for a lida pelo chamador
+	query := "UPDATE Tasks SET title = ?, description = ?, status = ?, priority = ?, team_id = ?, start_date = ?, due_date = ?, updated_at = ?, completed_at = ?, resolution = ?, task_type = ?, parent_id = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL"
+	// Executar a declaração SQL para atualizar a tarefa
+	result, err := d.q.Exec(query, updatedTask.Title, updatedTask.Description, updatedTask.Status, updatedTask.Priority, nullableID(updatedTask.TeamID),
+		updatedTask.StartDate, updatedTask.DueDate, updatedTask.UpdatedAt, updatedTask.CompletedAt, updatedTask.Resolution, updatedTask.Type, nullableID(updatedTask.ParentID), taskID, updatedTask.Version)
+	if err != nil {
+		d.log.Info(err.Error())
+		return err
+	}
+	// Como a versão sempre muda, zero linhas afetadas significa tarefa inexistente ou
+	// alterada por outra pessoa
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	var current int
+	err = d.q.QueryRow("SELECT version FROM Tasks WHERE id = ? AND deleted_at IS NULL", taskID).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return &service.VersionError{Current: current}
+}
+
+// RemoveUser apaga de vez um usuário existente do banco de dados.
+func (d *Database) RemoveUser(userID int) error {
+	// As atribuições e participações em equipes saem junto com o usuário
+	return d.inTx(func(tx *Database) error {
+		for _, query := range []string{
+			"DELETE FROM Task_user_associations WHERE user_id = ?",
+			"DELETE FROM Equipe_membros WHERE user_id = ?",
+		} {
+			_, err := tx.q.Exec(query, userID)
+			if err != nil {
+				d.log.Error(err.Error())
+				return err
+			}
+		}
+
+		result, err := tx.q.Exec("DELETE FROM users WHERE id = ?", userID)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		return tx.affected(result, "SELECT 1 FROM users WHERE id = ?", userID)
+	})
+}
+
+// CreateSession grava uma nova sessão de login.
+func (d *Database) CreateSession(session service.Session) error {
+	query := "INSERT INTO Sessions (id, user_id, refresh_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?)"
+	_, err := d.q.Exec(query, session.ID, session.UserID, session.RefreshHash, session.CreatedAt, session.ExpiresAt)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return service.ErrConflict
+		}
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetSession busca uma sessão pelo seu ID.
+func (d *Database) GetSession(sessionID string) (service.Session, error) {
+	query := "SELECT id, user_id, refresh_hash, created_at, expires_at, revoked_at FROM Sessions WHERE id = ?"
+	row := d.q.QueryRow(query, sessionID)
+
+	var session service.Session
+	var revokedAt sql.NullTime
+	err := row.Scan(&session.ID, &session.UserID, &session.RefreshHash, &session.CreatedAt, &session.ExpiresAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Session{}, service.ErrNotFound
+		}
+		return service.Session{}, err
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return session, nil
+}
+
+// UpdateSessionRefresh grava o hash do novo refresh token da sessão e sua nova validade.
+func (d *Database) UpdateSessionRefresh(sessionID, refreshHash string, expiresAt time.Time) error {
+	query := "UPDATE Sessions SET refresh_hash = ?, expires_at = ? WHERE id = ?"
+	result, err := d.q.Exec(query, refreshHash, expiresAt, sessionID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Sessions WHERE id = ?", sessionID)
+}
+
+// RevokeSession marca a sessão como revogada. Uma sessão já revogada mantém a data
+// da primeira revogação.
+func (d *Database) RevokeSession(sessionID string, revokedAt time.Time) error {
+	query := "UPDATE Sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL"
+	result, err := d.q.Exec(query, revokedAt, sessionID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Sessions WHERE id = ?", sessionID)
+}
+
+// NewRepository cria o repositório sobre uma conexão aberta com Open.
+func NewRepository(db *sql.DB, dialect Dialect, logger *zap.Logger) service.Repository {
+	return &Database{
+		db:      db,
+		q:       boundQuerier{db, dialect},
+		dialect: dialect,
+		log:     logger,
+	}
+}
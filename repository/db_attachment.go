@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+const attachmentColumns = "id, task_id, uploader_id, name, content_type, size, storage_key, created_at"
+
+func scanAttachment(row rowScanner) (service.Attachment, error) {
+	var attachment service.Attachment
+	var uploaderID sql.NullInt64
+	err := row.Scan(&attachment.ID, &attachment.TaskID, &uploaderID, &attachment.Name, &attachment.ContentType,
+		&attachment.Size, &attachment.StorageKey, &attachment.CreatedAt)
+	if err != nil {
+		return service.Attachment{}, err
+	}
+
+	attachment.UploaderID = int(uploaderID.Int64)
+	return attachment, nil
+}
+
+// CreateAttachment registra um anexo e retorna seu ID.
+func (d *Database) CreateAttachment(attachment service.Attachment) (int, error) {
+	err := d.require("tarefa não encontrada", "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NULL", attachment.TaskID)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "INSERT INTO Attachments (task_id, uploader_id, name, content_type, size, storage_key, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	id, err := d.insert("id", query, attachment.TaskID, nullableID(attachment.UploaderID), attachment.Name, attachment.ContentType,
+		attachment.Size, attachment.StorageKey, attachment.CreatedAt)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return 0, service.ErrConflict
+		}
+		d.log.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetAttachment busca um anexo pelo ID.
+func (d *Database) GetAttachment(attachmentID int) (service.Attachment, error) {
+	attachment, err := scanAttachment(d.q.QueryRow("SELECT "+attachmentColumns+" FROM Attachments WHERE id = ?", attachmentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Attachment{}, service.ErrNotFound
+		}
+		return service.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments retorna os anexos da tarefa em ordem de ID.
+func (d *Database) ListAttachments(taskID int) ([]service.Attachment, error) {
+	rows, err := d.q.Query("SELECT "+attachmentColumns+" FROM Attachments WHERE task_id = ? ORDER BY id", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []service.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// DeleteAttachment exclui o registro do anexo; o conteúdo é apagado pelo serviço.
+func (d *Database) DeleteAttachment(attachmentID int) error {
+	result, err := d.q.Exec("DELETE FROM Attachments WHERE id = ?", attachmentID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Attachments WHERE id = ?", attachmentID)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+const commentColumns = "comentario_id, tarefa_id, autor_id, parent_id, texto, criado_em, editado_em"
+
+// CreateComment grava um comentário e suas menções e retorna o ID do comentário.
+func (d *Database) CreateComment(comment service.Comment) (int, error) {
+	var id int
+	err := d.inTx(func(tx *Database) error {
+		err := tx.require("tarefa não encontrada", "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NULL", comment.TaskID)
+		if err != nil {
+			return err
+		}
+
+		query := "INSERT INTO Comentario (tarefa_id, autor_id, parent_id, texto, criado_em) VALUES (?, ?, ?, ?, ?)"
+		id, err = tx.insert("comentario_id", query, comment.TaskID, nullableID(comment.AuthorID), nullableID(comment.ParentID), comment.Text, comment.CreatedAt)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		return tx.insertMentions(id, comment.Mentions)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetComment busca um comentário pelo ID, com as menções.
+func (d *Database) GetComment(commentID int) (service.Comment, error) {
+	comment, err := scanComment(d.q.QueryRow("SELECT "+commentColumns+" FROM Comentario WHERE comentario_id = ?", commentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Comment{}, service.ErrNotFound
+		}
+		return service.Comment{}, err
+	}
+
+	comment.Mentions, err = d.getMentions(commentID)
+	if err != nil {
+		return service.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// UpdateComment grava o novo texto, a data de edição e as menções do comentário.
+func (d *Database) UpdateComment(comment service.Comment) error {
+	return d.inTx(func(tx *Database) error {
+		result, err := tx.q.Exec("UPDATE Comentario SET texto = ?, editado_em = ? WHERE comentario_id = ?", comment.Text, comment.EditedAt, comment.ID)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+		if err := tx.affected(result, "SELECT 1 FROM Comentario WHERE comentario_id = ?", comment.ID); err != nil {
+			return err
+		}
+
+		_, err = tx.q.Exec("DELETE FROM Comentario_mencoes WHERE comentario_id = ?", comment.ID)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		return tx.insertMentions(comment.ID, comment.Mentions)
+	})
+}
+
+// DeleteComment exclui o comentário; respostas e menções são removidas em cascata.
+func (d *Database) DeleteComment(commentID int) error {
+	result, err := d.q.Exec("DELETE FROM Comentario WHERE comentario_id = ?", commentID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Comentario WHERE comentario_id = ?", commentID)
+}
+
+// ListComments retorna uma página dos comentários da tarefa e o total de comentários.
+func (d *Database) ListComments(taskID, limit, offset int) ([]service.Comment, int, error) {
+	var total int
+	err := d.q.QueryRow("SELECT COUNT(*) FROM Comentario WHERE tarefa_id = ?", taskID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + commentColumns + " FROM Comentario WHERE tarefa_id = ? ORDER BY comentario_id LIMIT ? OFFSET ?"
+	rows, err := d.q.Query(query, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var comments []service.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	for i := range comments {
+		comments[i].Mentions, err = d.getMentions(comments[i].ID)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return comments, total, nil
+}
+
+func (d *Database) insertMentions(commentID int, mentions []int) error {
+	for _, userID := range mentions {
+		_, err := d.q.Exec("INSERT INTO Comentario_mencoes (comentario_id, user_id) VALUES (?, ?)", commentID, userID)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Database) getMentions(commentID int) ([]int, error) {
+	rows, err := d.q.Query("SELECT user_id FROM Comentario_mencoes WHERE comentario_id = ? ORDER BY user_id", commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mentions []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, userID)
+	}
+
+	return mentions, rows.Err()
+}
+
+func scanComment(row rowScanner) (service.Comment, error) {
+	var comment service.Comment
+	var authorID, parentID sql.NullInt64
+	var editedAt sql.NullTime
+	err := row.Scan(&comment.ID, &comment.TaskID, &authorID, &parentID, &comment.Text, &comment.CreatedAt, &editedAt)
+	if err != nil {
+		return service.Comment{}, err
+	}
+
+	comment.AuthorID = int(authorID.Int64)
+	comment.ParentID = int(parentID.Int64)
+	if editedAt.Valid {
+		comment.EditedAt = &editedAt.Time
+	}
+
+	return comment, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// CreateTaskEvent grava um evento no histórico da tarefa e retorna seu ID. A tarefa não
+// precisa existir: a exclusão também é registrada.
+func (d *Database) CreateTaskEvent(event service.TaskEvent) (int, error) {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "INSERT INTO Task_events (task_id, actor_id, kind, changes, created_at) VALUES (?, ?, ?, ?, ?)"
+	id, err := d.insert("id", query, event.TaskID, nullableID(event.ActorID), event.Kind, string(changes), event.CreatedAt)
+	if err != nil {
+		d.log.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// ListTaskEvents retorna os eventos da tarefa em ordem de ID.
+func (d *Database) ListTaskEvents(taskID int) ([]service.TaskEvent, error) {
+	rows, err := d.q.Query("SELECT id, task_id, actor_id, kind, changes, created_at FROM Task_events WHERE task_id = ? ORDER BY id", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []service.TaskEvent
+	for rows.Next() {
+		var event service.TaskEvent
+		var actorID sql.NullInt64
+		var changes string
+		if err := rows.Scan(&event.ID, &event.TaskID, &actorID, &event.Kind, &changes, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(changes), &event.Changes); err != nil {
+			return nil, err
+		}
+		event.ActorID = int(actorID.Int64)
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
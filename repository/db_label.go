@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// labelBatch limita os IDs por consulta em GetLabelsForTasks, GetOpenBlockers e
+// GetAssigneesForTasks, abaixo do máximo de parâmetros dos três bancos.
+const labelBatch = 500
+
+func scanLabel(row rowScanner) (service.Label, error) {
+	var label service.Label
+	err := row.Scan(&label.ID, &label.TeamID, &label.Name, &label.Color)
+	return label, err
+}
+
+// CreateLabel cria uma etiqueta e retorna seu ID. Um nome repetido na equipe retorna
+// service.ErrConflict.
+func (d *Database) CreateLabel(label service.Label) (int, error) {
+	err := d.require("equipe não encontrada", "SELECT 1 FROM Equipe WHERE equipe_id = ?", label.TeamID)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := d.insert("id", "INSERT INTO Labels (team_id, name, color) VALUES (?, ?, ?)", label.TeamID, label.Name, label.Color)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return 0, service.ErrConflict
+		}
+		d.log.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetLabel busca uma etiqueta pelo ID.
+func (d *Database) GetLabel(labelID int) (service.Label, error) {
+	label, err := scanLabel(d.q.QueryRow("SELECT id, team_id, name, color FROM Labels WHERE id = ?", labelID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Label{}, service.ErrNotFound
+		}
+		return service.Label{}, err
+	}
+
+	return label, nil
+}
+
+// ListLabels retorna as etiquetas da equipe em ordem de nome, sem diferenciar maiúsculas
+// de minúsculas.
+func (d *Database) ListLabels(teamID int) ([]service.Label, error) {
+	rows, err := d.q.Query("SELECT id, team_id, name, color FROM Labels WHERE team_id = ? ORDER BY LOWER(name), id", teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []service.Label
+	for rows.Next() {
+		label, err := scanLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, rows.Err()
+}
+
+// UpdateLabel altera o nome e a cor de uma etiqueta.
+func (d *Database) UpdateLabel(label service.Label) error {
+	result, err := d.q.Exec("UPDATE Labels SET name = ?, color = ? WHERE id = ?", label.Name, label.Color, label.ID)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return service.ErrConflict
+		}
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Labels WHERE id = ?", label.ID)
+}
+
+// DeleteLabel exclui a etiqueta; ela sai das tarefas em cascata.
+func (d *Database) DeleteLabel(labelID int) error {
+	result, err := d.q.Exec("DELETE FROM Labels WHERE id = ?", labelID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Labels WHERE id = ?", labelID)
+}
+
+// AddTaskLabel aplica a etiqueta à tarefa. Uma etiqueta já aplicada retorna
+// service.ErrConflict.
+func (d *Database) AddTaskLabel(taskID, labelID int) error {
+	err := d.require("tarefa não encontrada", "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NULL", taskID)
+	if err != nil {
+		return err
+	}
+	err = d.require("etiqueta não encontrada", "SELECT 1 FROM Labels WHERE id = ?", labelID)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.q.Exec("INSERT INTO Task_labels (task_id, label_id) VALUES (?, ?)", taskID, labelID)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return service.ErrConflict
+		}
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// RemoveTaskLabel retira a etiqueta da tarefa.
+func (d *Database) RemoveTaskLabel(taskID, labelID int) error {
+	result, err := d.q.Exec("DELETE FROM Task_labels WHERE task_id = ? AND label_id = ?", taskID, labelID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Task_labels WHERE task_id = ? AND label_id = ?", taskID, labelID)
+}
+
+// GetLabelsForTasks retorna as etiquetas de cada tarefa informada, em ordem de nome.
+func (d *Database) GetLabelsForTasks(taskIDs []int) (map[int][]service.Label, error) {
+	labels := make(map[int][]service.Label)
+	for start := 0; start < len(taskIDs); start += labelBatch {
+		batch := taskIDs[start:min(start+labelBatch, len(taskIDs))]
+
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		query := "SELECT tl.task_id, l.id, l.team_id, l.name, l.color FROM Task_labels tl JOIN Labels l ON l.id = tl.label_id" +
+			" WHERE tl.task_id IN (?" + strings.Repeat(", ?", len(batch)-1) + ") ORDER BY tl.task_id, LOWER(l.name), l.id"
+
+		if err := d.scanTaskLabels(labels, query, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return labels, nil
+}
+
+func (d *Database) scanTaskLabels(labels map[int][]service.Label, query string, args ...any) error {
+	rows, err := d.q.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var label service.Label
+		if err := rows.Scan(&taskID, &label.ID, &label.TeamID, &label.Name, &label.Color); err != nil {
+			return err
+		}
+		labels[taskID] = append(labels[taskID], label)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"strings"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// AddTaskLink grava a dependência entre as tarefas. Uma dependência já existente retorna
+// service.ErrConflict.
+func (d *Database) AddTaskLink(link service.TaskLink) error {
+	err := d.require("tarefa bloqueadora não encontrada", "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NULL", link.BlockerID)
+	if err != nil {
+		return err
+	}
+	err = d.require("tarefa bloqueada não encontrada", "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NULL", link.BlockedID)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.q.Exec("INSERT INTO Task_links (blocker_id, blocked_id) VALUES (?, ?)", link.BlockerID, link.BlockedID)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return service.ErrConflict
+		}
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// RemoveTaskLink exclui a dependência entre as tarefas.
+func (d *Database) RemoveTaskLink(link service.TaskLink) error {
+	result, err := d.q.Exec("DELETE FROM Task_links WHERE blocker_id = ? AND blocked_id = ?", link.BlockerID, link.BlockedID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Task_links WHERE blocker_id = ? AND blocked_id = ?", link.BlockerID, link.BlockedID)
+}
+
+// GetTaskLinks retorna as dependências em que a tarefa bloqueia ou é bloqueada, inclusive
+// as com tarefas da lixeira, que voltam a valer quando elas são restauradas.
+func (d *Database) GetTaskLinks(taskID int) ([]service.TaskLink, error) {
+	rows, err := d.q.Query("SELECT blocker_id, blocked_id FROM Task_links WHERE blocker_id = ? OR blocked_id = ? ORDER BY blocker_id, blocked_id", taskID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []service.TaskLink
+	for rows.Next() {
+		var link service.TaskLink
+		if err := rows.Scan(&link.BlockerID, &link.BlockedID); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// GetOpenBlockers retorna as tarefas não concluídas que bloqueiam cada tarefa informada.
+func (d *Database) GetOpenBlockers(taskIDs []int) (map[int][]int, error) {
+	blockers := make(map[int][]int)
+	for start := 0; start < len(taskIDs); start += labelBatch {
+		batch := taskIDs[start:min(start+labelBatch, len(taskIDs))]
+
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		query := "SELECT l.blocked_id, l.blocker_id FROM Task_links l JOIN Tasks t ON t.id = l.blocker_id" +
+			" WHERE t.completed_at IS NULL AND t.deleted_at IS NULL AND l.blocked_id IN (?" + strings.Repeat(", ?", len(batch)-1) + ") ORDER BY l.blocked_id, l.blocker_id"
+
+		if err := d.scanBlockers(blockers, query, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return blockers, nil
+}
+
+func (d *Database) scanBlockers(blockers map[int][]int, query string, args ...any) error {
+	rows, err := d.q.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var blockedID, blockerID int
+		if err := rows.Scan(&blockedID, &blockerID); err != nil {
+			return err
+		}
+		blockers[blockedID] = append(blockers[blockedID], blockerID)
+	}
+
+	return rows.Err()
+}
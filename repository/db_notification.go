@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+const notificationColumns = "notificacao_id, destinatario_id, tipo, conteudo, tarefa_id, lida, criada_em"
+
+// CreateNotification grava uma notificação e retorna seu ID.
+func (d *Database) CreateNotification(notification service.Notification) (int, error) {
+	query := "INSERT INTO Notificacao (destinatario_id, tipo, conteudo, tarefa_id, lida, criada_em) VALUES (?, ?, ?, ?, ?, ?)"
+	id, err := d.insert("notificacao_id", query, notification.RecipientID, notification.Type, notification.Content, nullableID(notification.TaskID), notification.Read, notification.CreatedAt)
+	if err != nil {
+		d.log.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetNotification busca uma notificação pelo ID.
+func (d *Database) GetNotification(notificationID int) (service.Notification, error) {
+	notification, err := scanNotification(d.q.QueryRow("SELECT "+notificationColumns+" FROM Notificacao WHERE notificacao_id = ?", notificationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Notification{}, service.ErrNotFound
+		}
+		return service.Notification{}, err
+	}
+
+	return notification, nil
+}
+
+// ListNotifications retorna as notificações do usuário, da mais recente à mais antiga.
+func (d *Database) ListNotifications(userID int, unreadOnly bool) ([]service.Notification, error) {
+	query := "SELECT " + notificationColumns + " FROM Notificacao WHERE destinatario_id = ?"
+	if unreadOnly {
+		query += " AND lida = FALSE"
+	}
+	query += " ORDER BY notificacao_id DESC"
+
+	rows, err := d.q.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []service.Notification
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// MarkNotificationRead marca uma notificação como lida.
+func (d *Database) MarkNotificationRead(notificationID int) error {
+	result, err := d.q.Exec("UPDATE Notificacao SET lida = TRUE WHERE notificacao_id = ?", notificationID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Notificacao WHERE notificacao_id = ?", notificationID)
+}
+
+// MarkAllNotificationsRead marca todas as notificações do usuário como lidas.
+func (d *Database) MarkAllNotificationsRead(userID int) error {
+	_, err := d.q.Exec("UPDATE Notificacao SET lida = TRUE WHERE destinatario_id = ? AND lida = FALSE", userID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetNotificationPreferences retorna as preferências gravadas pelo usuário. Categorias
+// sem preferência gravada não aparecem no mapa.
+func (d *Database) GetNotificationPreferences(userID int) (map[service.NotificationType]bool, error) {
+	rows, err := d.q.Query("SELECT tipo, habilitado FROM Notificacao_preferencias WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := make(map[service.NotificationType]bool)
+	for rows.Next() {
+		var notificationType service.NotificationType
+		var enabled bool
+		if err := rows.Scan(&notificationType, &enabled); err != nil {
+			return nil, err
+		}
+		prefs[notificationType] = enabled
+	}
+
+	return prefs, rows.Err()
+}
+
+// SetNotificationPreference grava se o usuário recebe a categoria de notificação.
+func (d *Database) SetNotificationPreference(userID int, notificationType service.NotificationType, enabled bool) error {
+	return d.inTx(func(tx *Database) error {
+		_, err := tx.q.Exec("DELETE FROM Notificacao_preferencias WHERE user_id = ? AND tipo = ?", userID, notificationType)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		_, err = tx.q.Exec("INSERT INTO Notificacao_preferencias (user_id, tipo, habilitado) VALUES (?, ?, ?)", userID, notificationType, enabled)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		return nil
+	})
+}
+
+func scanNotification(row rowScanner) (service.Notification, error) {
+	var notification service.Notification
+	var taskID sql.NullInt64
+	err := row.Scan(&notification.ID, &notification.RecipientID, &notification.Type, &notification.Content, &taskID, &notification.Read, &notification.CreatedAt)
+	if err != nil {
+		return service.Notification{}, err
+	}
+	notification.TaskID = int(taskID.Int64)
+
+	return notification, nil
+}
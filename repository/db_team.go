@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// CreateTeam cria uma nova equipe e retorna seu ID.
+func (d *Database) CreateTeam(team service.Team) (int, error) {
+	id, err := d.insert("equipe_id", "INSERT INTO Equipe (nome, arquivada) VALUES (?, ?)", team.Name, team.Archived)
+	if err != nil {
+		d.log.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetTeamByID busca uma equipe pelo ID, sem os membros.
+func (d *Database) GetTeamByID(teamID int) (service.Team, error) {
+	var team service.Team
+	err := d.q.QueryRow("SELECT equipe_id, nome, arquivada FROM Equipe WHERE equipe_id = ?", teamID).Scan(&team.ID, &team.Name, &team.Archived)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Team{}, service.ErrNotFound
+		}
+		return service.Team{}, err
+	}
+
+	return team, nil
+}
+
+// GetAllTeams retorna todas as equipes, inclusive as arquivadas.
+func (d *Database) GetAllTeams() ([]service.Team, error) {
+	rows, err := d.q.Query("SELECT equipe_id, nome, arquivada FROM Equipe ORDER BY equipe_id")
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTeams(rows)
+}
+
+// GetTeamsForUser retorna as equipes das quais o usuário é membro.
+func (d *Database) GetTeamsForUser(userID int) ([]service.Team, error) {
+	query := "SELECT e.equipe_id, e.nome, e.arquivada FROM Equipe e JOIN Equipe_membros m ON m.equipe_id = e.equipe_id WHERE m.user_id = ? ORDER BY e.equipe_id"
+	rows, err := d.q.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTeams(rows)
+}
+
+// UpdateTeam atualiza o nome e o estado de arquivamento de uma equipe.
+func (d *Database) UpdateTeam(team service.Team) error {
+	result, err := d.q.Exec("UPDATE Equipe SET nome = ?, arquivada = ? WHERE equipe_id = ?", team.Name, team.Archived, team.ID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Equipe WHERE equipe_id = ?", team.ID)
+}
+
+// AddTeamMember adiciona um usuário à equipe com o papel informado.
+func (d *Database) AddTeamMember(teamID, userID int, role service.TeamRole) error {
+	err := d.require("equipe não encontrada", "SELECT 1 FROM Equipe WHERE equipe_id = ?", teamID)
+	if err != nil {
+		return err
+	}
+	err = d.require("usuário não encontrado", "SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.q.Exec("INSERT INTO Equipe_membros (equipe_id, user_id, papel) VALUES (?, ?, ?)", teamID, userID, role)
+	if err != nil {
+		if d.dialect.isDuplicate(err) {
+			return service.ErrConflict
+		}
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// UpdateTeamMemberRole altera o papel de um membro da equipe.
+func (d *Database) UpdateTeamMemberRole(teamID, userID int, role service.TeamRole) error {
+	result, err := d.q.Exec("UPDATE Equipe_membros SET papel = ? WHERE equipe_id = ? AND user_id = ?", role, teamID, userID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Equipe_membros WHERE equipe_id = ? AND user_id = ?", teamID, userID)
+}
+
+// RemoveTeamMember remove um usuário da equipe.
+func (d *Database) RemoveTeamMember(teamID, userID int) error {
+	result, err := d.q.Exec("DELETE FROM Equipe_membros WHERE equipe_id = ? AND user_id = ?", teamID, userID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Equipe_membros WHERE equipe_id = ? AND user_id = ?", teamID, userID)
+}
+
+// GetTeamMembers retorna os membros da equipe e seus papéis.
+func (d *Database) GetTeamMembers(teamID int) ([]service.TeamMember, error) {
+	rows, err := d.q.Query("SELECT user_id, papel FROM Equipe_membros WHERE equipe_id = ? AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL) ORDER BY user_id", teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []service.TeamMember
+	for rows.Next() {
+		var member service.TeamMember
+		if err := rows.Scan(&member.UserID, &member.Role); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// GetTasksForTeam retorna as tarefas vinculadas à equipe.
+func (d *Database) GetTasksForTeam(teamID int) ([]service.Task, error) {
+	rows, err := d.q.Query("SELECT "+taskColumns+" FROM Tasks WHERE team_id = ? AND deleted_at IS NULL ORDER BY id", teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTasks(rows)
+}
+
+func scanTeams(rows *sql.Rows) ([]service.Team, error) {
+	defer rows.Close()
+
+	var teams []service.Team
+	for rows.Next() {
+		var team service.Team
+		if err := rows.Scan(&team.ID, &team.Name, &team.Archived); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, rows.Err()
+}
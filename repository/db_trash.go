@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// TrashTask move a tarefa para a lixeira. As atribuições, etiquetas, dependências,
+// comentários e anexos continuam gravados e voltam junto com a tarefa.
+func (d *Database) TrashTask(taskID int, at time.Time) error {
+	result, err := d.q.Exec("UPDATE Tasks SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", at, taskID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NULL", taskID)
+}
+
+// RestoreTask tira a tarefa da lixeira.
+func (d *Database) RestoreTask(taskID int) error {
+	result, err := d.q.Exec("UPDATE Tasks SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", taskID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Tasks WHERE id = ? AND deleted_at IS NOT NULL", taskID)
+}
+
+// ListTrashedTasks retorna as tarefas da lixeira, das excluídas há mais tempo às mais
+// recentes.
+func (d *Database) ListTrashedTasks() ([]service.Task, error) {
+	rows, err := d.q.Query("SELECT " + taskColumns + " FROM Tasks WHERE deleted_at IS NOT NULL ORDER BY deleted_at, id")
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTasks(rows)
+}
+
+// TrashUser move o usuário para a lixeira. As atribuições e participações em equipes
+// continuam gravadas, mas deixam de aparecer enquanto ele estiver lá.
+func (d *Database) TrashUser(userID int, at time.Time) error {
+	result, err := d.q.Exec("UPDATE users SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", at, userID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL", userID)
+}
+
+// RestoreUser tira o usuário da lixeira.
+func (d *Database) RestoreUser(userID int) error {
+	result, err := d.q.Exec("UPDATE users SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", userID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM users WHERE id = ? AND deleted_at IS NOT NULL", userID)
+}
+
+// ListTrashedUsers retorna os usuários da lixeira, dos excluídos há mais tempo aos mais
+// recentes.
+func (d *Database) ListTrashedUsers() ([]service.User, error) {
+	rows, err := d.q.Query("SELECT id, name, email, password, role, deleted_at FROM users WHERE deleted_at IS NOT NULL ORDER BY deleted_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []service.User
+	for rows.Next() {
+		var user service.User
+		var deletedAt sql.NullTime
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &deletedAt); err != nil {
+			return nil, err
+		}
+		user.DeletedAt = timeOrNil(deletedAt)
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
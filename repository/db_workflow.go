@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// workflowDefinition é o conteúdo da coluna definition da tabela Workflows.
+type workflowDefinition struct {
+	Initial     string                       `json:"initial"`
+	States      []service.WorkflowState      `json:"states"`
+	Transitions []service.WorkflowTransition `json:"transitions"`
+}
+
+func encodeWorkflow(workflow service.Workflow) (string, error) {
+	definition, err := json.Marshal(workflowDefinition{Initial: workflow.Initial, States: workflow.States, Transitions: workflow.Transitions})
+	if err != nil {
+		return "", err
+	}
+
+	return string(definition), nil
+}
+
+// scanWorkflow lê um fluxo selecionado com id, name e definition.
+func scanWorkflow(row rowScanner) (service.Workflow, error) {
+	var workflow service.Workflow
+	var definition string
+	if err := row.Scan(&workflow.ID, &workflow.Name, &definition); err != nil {
+		return service.Workflow{}, err
+	}
+
+	var decoded workflowDefinition
+	if err := json.Unmarshal([]byte(definition), &decoded); err != nil {
+		return service.Workflow{}, err
+	}
+	workflow.Initial, workflow.States, workflow.Transitions = decoded.Initial, decoded.States, decoded.Transitions
+
+	return workflow, nil
+}
+
+// CreateWorkflow cadastra um fluxo de trabalho e retorna seu ID.
+func (d *Database) CreateWorkflow(workflow service.Workflow) (int, error) {
+	definition, err := encodeWorkflow(workflow)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := d.insert("id", "INSERT INTO Workflows (name, definition) VALUES (?, ?)", workflow.Name, definition)
+	if err != nil {
+		d.log.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetWorkflow busca um fluxo de trabalho pelo ID.
+func (d *Database) GetWorkflow(workflowID int) (service.Workflow, error) {
+	workflow, err := scanWorkflow(d.q.QueryRow("SELECT id, name, definition FROM Workflows WHERE id = ?", workflowID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Workflow{}, service.ErrNotFound
+		}
+		return service.Workflow{}, err
+	}
+
+	return workflow, nil
+}
+
+// ListWorkflows retorna todos os fluxos de trabalho cadastrados.
+func (d *Database) ListWorkflows() ([]service.Workflow, error) {
+	rows, err := d.q.Query("SELECT id, name, definition FROM Workflows ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workflows []service.Workflow
+	for rows.Next() {
+		workflow, err := scanWorkflow(rows)
+		if err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, rows.Err()
+}
+
+// UpdateWorkflow substitui o nome e a definição de um fluxo de trabalho.
+func (d *Database) UpdateWorkflow(workflow service.Workflow) error {
+	definition, err := encodeWorkflow(workflow)
+	if err != nil {
+		return err
+	}
+
+	result, err := d.q.Exec("UPDATE Workflows SET name = ?, definition = ? WHERE id = ?", workflow.Name, definition, workflow.ID)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Workflows WHERE id = ?", workflow.ID)
+}
+
+// DeleteWorkflow exclui um fluxo de trabalho e suas atribuições.
+func (d *Database) DeleteWorkflow(workflowID int) error {
+	return d.inTx(func(tx *Database) error {
+		if _, err := tx.q.Exec("DELETE FROM Workflow_assignments WHERE workflow_id = ?", workflowID); err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		result, err := tx.q.Exec("DELETE FROM Workflows WHERE id = ?", workflowID)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		return tx.affected(result, "SELECT 1 FROM Workflows WHERE id = ?", workflowID)
+	})
+}
+
+// ListWorkflowAssignments retorna as atribuições de fluxos de trabalho.
+func (d *Database) ListWorkflowAssignments() ([]service.WorkflowAssignment, error) {
+	rows, err := d.q.Query("SELECT workflow_id, team_id, task_type FROM Workflow_assignments ORDER BY workflow_id, COALESCE(team_id, 0), task_type")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []service.WorkflowAssignment
+	for rows.Next() {
+		var assignment service.WorkflowAssignment
+		var teamID sql.NullInt64
+		if err := rows.Scan(&assignment.WorkflowID, &teamID, &assignment.TaskType); err != nil {
+			return nil, err
+		}
+		assignment.TeamID = int(teamID.Int64)
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, rows.Err()
+}
+
+// SetWorkflowAssignment grava a atribuição, substituindo a que existir para a mesma
+// equipe e tipo de tarefa.
+func (d *Database) SetWorkflowAssignment(assignment service.WorkflowAssignment) error {
+	return d.inTx(func(tx *Database) error {
+		err := tx.require("fluxo de trabalho não encontrado", "SELECT 1 FROM Workflows WHERE id = ?", assignment.WorkflowID)
+		if err != nil {
+			return err
+		}
+		if err := tx.requireTeam(assignment.TeamID); err != nil {
+			return err
+		}
+
+		scope, args := assignmentScope(assignment.TeamID, assignment.TaskType)
+		if _, err := tx.q.Exec("DELETE FROM Workflow_assignments WHERE "+scope, args...); err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		_, err = tx.q.Exec("INSERT INTO Workflow_assignments (workflow_id, team_id, task_type) VALUES (?, ?, ?)",
+			assignment.WorkflowID, nullableID(assignment.TeamID), assignment.TaskType)
+		if err != nil {
+			d.log.Error(err.Error())
+			return err
+		}
+
+		return nil
+	})
+}
+
+// RemoveWorkflowAssignment remove a atribuição da equipe e do tipo de tarefa informados.
+func (d *Database) RemoveWorkflowAssignment(teamID int, taskType string) error {
+	scope, args := assignmentScope(teamID, taskType)
+	result, err := d.q.Exec("DELETE FROM Workflow_assignments WHERE "+scope, args...)
+	if err != nil {
+		d.log.Error(err.Error())
+		return err
+	}
+
+	return d.affected(result, "SELECT 1 FROM Workflow_assignments WHERE "+scope, args...)
+}
+
+// assignmentScope filtra as atribuições de uma equipe e tipo de tarefa; equipe zero é
+// gravada como NULL e não pode ser comparada com "=".
+func assignmentScope(teamID int, taskType string) (string, []any) {
+	if teamID == 0 {
+		return "team_id IS NULL AND task_type = ?", []any{taskType}
+	}
+
+	return "team_id = ? AND task_type = ?", []any{teamID, taskType}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect identifica o banco de dados usado pelo repositório.
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// ParseDialect valida o nome de um dialeto.
+func ParseDialect(name string) (Dialect, error) {
+	switch dialect := Dialect(name); dialect {
+	case MySQL, SQLite, Postgres:
+		return dialect, nil
+	}
+
+	return "", fmt.Errorf("banco de dados não suportado %q", name)
+}
+
+// Open abre a conexão com o banco do dialeto informado. No SQLite o DSN é o caminho do
+// arquivo; as chaves estrangeiras são ativadas, as datas são gravadas em um formato que
+// pode ser comparado como texto e o pool fica com uma única conexão, já que o SQLite
+// aceita apenas um escritor por vez.
+func Open(dialect Dialect, dsn string) (*sql.DB, error) {
+	switch dialect {
+	case MySQL:
+		return sql.Open("mysql", dsn)
+
+	case Postgres:
+		return sql.Open("pgx", dsn)
+
+	case SQLite:
+		db, err := sql.Open("sqlite", sqliteDSN(dsn))
+		if err != nil {
+			return nil, err
+		}
+		db.SetMaxOpenConns(1)
+		return db, nil
+	}
+
+	return nil, fmt.Errorf("banco de dados não suportado %q", dialect)
+}
+
+func sqliteDSN(dsn string) string {
+	pragmas := []string{"_pragma=foreign_keys(1)", "_pragma=busy_timeout(5000)", "_time_format=sqlite"}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + strings.Join(pragmas, "&")
+	}
+	return dsn + "?" + strings.Join(pragmas, "&")
+}
+
+// isDuplicate informa se o erro é uma violação de chave primária ou única.
+func (d Dialect) isDuplicate(err error) bool {
+	switch d {
+	case MySQL:
+		var mysqlErr *mysql.MySQLError
+		return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+
+	case Postgres:
+		var pgErr *pgconn.PgError
+		return errors.As(err, &pgErr) && pgErr.Code == "23505" // unique_violation
+
+	case SQLite:
+		var sqliteErr *sqlite.Error
+		if errors.As(err, &sqliteErr) {
+			code := sqliteErr.Code()
+			return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+		}
+	}
+
+	return false
+}
+
+// rebind troca os placeholders "?" pelos do dialeto. As consultas do repositório são
+// escritas com "?", e o Postgres espera $1, $2...
+func (d Dialect) rebind(query string) string {
+	if d != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	inString := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			inString = !inString
+		case r == '?' && !inString:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// boundQuerier aplica rebind a cada consulta antes de repassá-la ao banco.
+type boundQuerier struct {
+	q       querier
+	dialect Dialect
+}
+
+func (b boundQuerier) Exec(query string, args ...any) (sql.Result, error) {
+	return b.q.Exec(b.dialect.rebind(query), args...)
+}
+
+func (b boundQuerier) Query(query string, args ...any) (*sql.Rows, error) {
+	return b.q.Query(b.dialect.rebind(query), args...)
+}
+
+func (b boundQuerier) QueryRow(query string, args ...any) *sql.Row {
+	return b.q.QueryRow(b.dialect.rebind(query), args...)
+}
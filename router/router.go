@@ -1,9 +1,12 @@
 package router
 
 import (
+	"slices"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/mclcavalcante/teamTask/config"
+	"github.com/mclcavalcante/teamTask/controller"
 )
 
 func Init(init *config.Initialization) *gin.Engine {
@@ -12,24 +15,118 @@ func Init(init *config.Initialization) *gin.Engine {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
-	router.Use(cors.Default())
+	// O cabeçalho Authorization precisa ser liberado para o front-end enviar o token
+	corsConfig := cors.DefaultConfig()
+	if slices.Contains(init.Settings.Server.CORSOrigins, "*") {
+		corsConfig.AllowAllOrigins = true
+	} else {
+		corsConfig.AllowOrigins = init.Settings.Server.CORSOrigins
+	}
+	corsConfig.AddAllowHeaders("Authorization", "If-Match")
+	corsConfig.AddExposeHeaders("ETag")
+	router.Use(cors.New(corsConfig))
+
+	auth := router.Group("/auth")
+	{
+		auth.POST("/login", init.Controller.Login)
+		auth.POST("/refresh", init.Controller.RefreshToken)
+		auth.POST("/logout", init.Controller.RequireAuth, init.Controller.Logout)
+	}
 
-	api := router.Group("/task")
+	api := router.Group("/task", init.Controller.RequireAuth)
 	{
 		api.POST("/", init.Controller.CreateTaskData)
 		api.GET("/:taskID", init.Controller.GetTaskByID)
 		api.DELETE("/:taskID", init.Controller.DeleteTask)
 		api.PUT("/:taskID", init.Controller.EditTask)
+		api.PATCH("/:taskID", init.Controller.PatchTask)
+		api.DELETE("/:taskID/assignees/:userID", init.Controller.UnassignMemberFromTask)
+		api.POST("/:taskID/transition", init.Controller.TransitionTask)
+		api.GET("/:taskID/workflow", init.Controller.GetTaskWorkflow)
+		api.GET("/:taskID/children", init.Controller.GetTaskChildren)
+		api.GET("/:taskID/tree", init.Controller.GetTaskTree)
+		api.PUT("/:taskID/parent", init.Controller.SetTaskParent)
+		api.GET("/:taskID/links", init.Controller.GetTaskLinks)
+		api.PUT("/:taskID/blocks/:blockedID", init.Controller.AddTaskLink)
+		api.DELETE("/:taskID/blocks/:blockedID", init.Controller.RemoveTaskLink)
+		api.GET("/:taskID/history", init.Controller.GetTaskHistory)
+		api.GET("/:taskID/attachments", init.Controller.ListAttachments)
+		api.POST("/:taskID/attachments", controller.LimitUpload(init.Settings.Attachments.MaxSize), init.Controller.AddAttachment)
+		api.GET("/:taskID/attachments/:attachmentID", init.Controller.DownloadAttachment)
+		api.DELETE("/:taskID/attachments/:attachmentID", init.Controller.DeleteAttachment)
 		api.GET("/all/:userID", init.Controller.GetVisibleTasksForUser)
 		api.GET("/all", init.Controller.GetAllTasks)
+		api.GET("/overdue", init.Controller.GetOverdueTasks)
+		api.GET("/upcoming/:userID", init.Controller.GetUpcomingTasksForUser)
+		api.GET("/priorities", init.Controller.ListPriorities)
+
+		api.GET("/:taskID/comments", init.Controller.ListComments)
+		api.POST("/:taskID/comments", init.Controller.CreateComment)
+		api.PUT("/:taskID/comments/:commentID", init.Controller.EditComment)
+		api.DELETE("/:taskID/comments/:commentID", init.Controller.DeleteComment)
+
+		api.PUT("/:taskID/labels/:labelID", init.Controller.AddTaskLabel)
+		api.DELETE("/:taskID/labels/:labelID", init.Controller.RemoveTaskLabel)
+	}
+
+	team := router.Group("/team", init.Controller.RequireAuth)
+	{
+		team.POST("/", init.Controller.CreateTeam)
+		team.GET("/all", init.Controller.GetAllTeams)
+		team.GET("/:teamID", init.Controller.GetTeam)
+		team.PUT("/:teamID", init.Controller.RenameTeam)
+		team.POST("/:teamID/archive", init.Controller.ArchiveTeam)
+		team.POST("/:teamID/unarchive", init.Controller.UnarchiveTeam)
+		team.PUT("/:teamID/members/:userID", init.Controller.AddTeamMember)
+		team.DELETE("/:teamID/members/:userID", init.Controller.RemoveTeamMember)
+		team.GET("/:teamID/tasks", init.Controller.GetTeamTasks)
+		team.GET("/:teamID/labels", init.Controller.ListTeamLabels)
+		team.POST("/:teamID/labels", init.Controller.CreateLabel)
+	}
+
+	labels := router.Group("/label", init.Controller.RequireAuth)
+	{
+		labels.PUT("/:labelID", init.Controller.UpdateLabel)
+		labels.DELETE("/:labelID", init.Controller.DeleteLabel)
+	}
+
+	workflow := router.Group("/workflow", init.Controller.RequireAuth)
+	{
+		workflow.GET("/", init.Controller.ListWorkflows)
+		workflow.POST("/", init.Controller.CreateWorkflow)
+		workflow.GET("/assignments", init.Controller.ListWorkflowAssignments)
+		workflow.PUT("/assignments", init.Controller.AssignWorkflow)
+		workflow.DELETE("/assignments", init.Controller.UnassignWorkflow)
+		workflow.GET("/:workflowID", init.Controller.GetWorkflow)
+		workflow.PUT("/:workflowID", init.Controller.UpdateWorkflow)
+		workflow.DELETE("/:workflowID", init.Controller.DeleteWorkflow)
+	}
+
+	notifications := router.Group("/notifications", init.Controller.RequireAuth)
+	{
+		notifications.GET("/", init.Controller.ListNotifications)
+		notifications.POST("/:notificationID/read", init.Controller.MarkNotificationRead)
+		notifications.POST("/read-all", init.Controller.MarkAllNotificationsRead)
+		notifications.GET("/preferences", init.Controller.GetNotificationPreferences)
+		notifications.PUT("/preferences", init.Controller.UpdateNotificationPreferences)
+	}
+
+	trash := router.Group("/trash", init.Controller.RequireAuth)
+	{
+		trash.GET("/tasks", init.Controller.ListTrashedTasks)
+		trash.POST("/tasks/:taskID/restore", init.Controller.RestoreTask)
+		trash.GET("/users", init.Controller.ListTrashedUsers)
+		trash.POST("/users/:userID/restore", init.Controller.RestoreUser)
 	}
 
-	router.GET("/filter/:status/:priority", init.Controller.FilterTasksByStatusAndPriority)
-	router.POST("/:userID/:taskID", init.Controller.AssignMemberToTask)
+	router.GET("/filter/:status/:priority", init.Controller.RequireAuth, init.Controller.FilterTasksByStatusAndPriority)
+	router.POST("/:userID/:taskID", init.Controller.RequireAuth, init.Controller.AssignMemberToTask)
 
-	router.GET("/user/:userID", init.Controller.GetUserByID)
+	// O cadastro continua aberto para que novos usuários possam se registrar
 	router.POST("/user", init.Controller.RegisterNewUser)
-	router.DELETE("/user/:userID", init.Controller.DeleteUser)
+	router.GET("/user/:userID", init.Controller.RequireAuth, init.Controller.GetUserByID)
+	router.DELETE("/user/:userID", init.Controller.RequireAuth, init.Controller.DeleteUser)
+	router.PUT("/user/:userID/role", init.Controller.RequireAuth, init.Controller.ChangeUserRole)
 
 	return router
 }
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Eventos registrados no histórico quando um arquivo é anexado ou retirado da tarefa.
+const (
+	TaskAttached TaskEventKind = "attached"
+	TaskDetached TaskEventKind = "detached"
+)
+
+// BlobStore guarda o conteúdo dos anexos. As chaves são geradas pelo serviço.
+type BlobStore interface {
+	// Put grava exatamente size bytes de content sob a chave.
+	Put(key string, content io.Reader, size int64, contentType string) error
+	// Get abre o conteúdo da chave; uma chave inexistente retorna ErrNotFound.
+	Get(key string) (io.ReadCloser, error)
+	// Delete apaga o conteúdo da chave; uma chave inexistente não é erro.
+	Delete(key string) error
+}
+
+// AttachmentUpload é um arquivo enviado para ser anexado a uma tarefa. O tipo do
+// arquivo é detectado pelo conteúdo e pela extensão do nome.
+type AttachmentUpload struct {
+	Name    string
+	Size    int64
+	Content io.Reader
+}
+
+// AttachmentPolicy limita os arquivos aceitos como anexo.
+type AttachmentPolicy struct {
+	MaxSize      int64    `yaml:"maxSize"`      // em bytes
+	AllowedTypes []string `yaml:"allowedTypes"` // tipos MIME; image/* aceita qualquer imagem
+}
+
+// DefaultAttachmentPolicy retorna os limites usados quando nenhum é configurado.
+func DefaultAttachmentPolicy() AttachmentPolicy {
+	return AttachmentPolicy{
+		MaxSize: 10 << 20,
+		AllowedTypes: []string{
+			"image/png", "image/jpeg", "image/gif", "image/webp",
+			"application/pdf", "application/zip", "application/json",
+			"text/plain", "text/csv",
+		},
+	}
+}
+
+// Validate confere se os limites são utilizáveis.
+func (p AttachmentPolicy) Validate() error {
+	var errs []error
+	if p.MaxSize <= 0 {
+		errs = append(errs, errors.New("maxSize precisa ser positivo"))
+	}
+	if len(p.AllowedTypes) == 0 {
+		errs = append(errs, errors.New("informe ao menos um tipo em allowedTypes"))
+	}
+	for _, allowed := range p.AllowedTypes {
+		if _, _, err := mime.ParseMediaType(allowed); err != nil || !strings.Contains(allowed, "/") {
+			errs = append(errs, fmt.Errorf("tipo inválido %q", allowed))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Allows informa se o tipo MIME, sem parâmetros, está entre os permitidos.
+func (p AttachmentPolicy) Allows(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	family, _, _ := strings.Cut(mediaType, "/")
+
+	for _, allowed := range p.AllowedTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == mediaType || allowed == family+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// WithAttachmentStore define onde o conteúdo dos anexos é gravado.
+func WithAttachmentStore(store BlobStore) Option {
+	return func(s *teamTaskService) {
+		s.blobs = store
+	}
+}
+
+// WithAttachmentPolicy define os limites dos anexos. A política deve ter sido validada.
+func WithAttachmentPolicy(policy AttachmentPolicy) Option {
+	return func(s *teamTaskService) {
+		s.attachments = policy
+	}
+}
+
+// AddAttachment grava o arquivo e o anexa à tarefa. Quem edita a tarefa anexa arquivos.
+func (service teamTaskService) AddAttachment(taskID int, upload AttachmentUpload) (Attachment, error) {
+	if err := service.requireBlobs(); err != nil {
+		return Attachment{}, err
+	}
+	if _, err := service.db.GetTaskByID(taskID); err != nil {
+		return Attachment{}, errors.Join(err, errors.New("tarefa não encontrada"))
+	}
+	if err := service.authorizeTaskEdit(taskID); err != nil {
+		return Attachment{}, err
+	}
+
+	name, err := attachmentName(upload.Name)
+	if err != nil {
+		return Attachment{}, err
+	}
+	if upload.Size <= 0 {
+		return Attachment{}, fmt.Errorf("%w: arquivo vazio", ErrValidation)
+	}
+	if upload.Size > service.attachments.MaxSize {
+		return Attachment{}, fmt.Errorf("%w: o limite é de %d bytes", ErrTooLarge, service.attachments.MaxSize)
+	}
+
+	// O tipo vem do conteúdo, não do que o cliente declarou
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return Attachment{}, errors.Join(err, errors.New("erro ao ler o arquivo"))
+	}
+	head = head[:n]
+	contentType := detectContentType(name, head)
+	if !service.attachments.Allows(contentType) {
+		return Attachment{}, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	key, err := attachmentKey(taskID)
+	if err != nil {
+		return Attachment{}, err
+	}
+	content := io.MultiReader(bytes.NewReader(head), upload.Content)
+	if err := service.blobs.Put(key, content, upload.Size, contentType); err != nil {
+		return Attachment{}, errors.Join(err, errors.New("erro ao gravar o anexo"))
+	}
+
+	attachment := Attachment{
+		TaskID:      taskID,
+		Name:        name,
+		ContentType: contentType,
+		Size:        upload.Size,
+		StorageKey:  key,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+	if service.actor != nil {
+		attachment.UploaderID = service.actor.ID
+	}
+
+	// O registro e o histórico vão juntos; se falharem, o conteúdo gravado é apagado
+	err = service.inTransaction(func(tx teamTaskService) error {
+		var err error
+		attachment.ID, err = tx.db.CreateAttachment(attachment)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao registrar o anexo"))
+		}
+		return tx.recordEvent(taskID, TaskAttached, []FieldChange{{Field: "attachment", After: name}})
+	})
+	if err != nil {
+		service.removeBlobs(key)
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments retorna os anexos da tarefa, do mais antigo ao mais novo.
+func (service teamTaskService) ListAttachments(taskID int) ([]Attachment, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+	if _, err := service.db.GetTaskByID(taskID); err != nil {
+		return nil, errors.Join(err, errors.New("tarefa não encontrada"))
+	}
+
+	attachments, err := service.db.ListAttachments(taskID)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter os anexos da tarefa"))
+	}
+
+	return attachments, nil
+}
+
+// OpenAttachment retorna o anexo da tarefa e seu conteúdo.
+func (service teamTaskService) OpenAttachment(taskID, attachmentID int) (Attachment, io.ReadCloser, error) {
+	if err := service.requireBlobs(); err != nil {
+		return Attachment{}, nil, err
+	}
+	if err := service.authorize(PermViewTasks); err != nil {
+		return Attachment{}, nil, err
+	}
+
+	attachment, err := service.taskAttachment(taskID, attachmentID)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+
+	content, err := service.blobs.Get(attachment.StorageKey)
+	if err != nil {
+		return Attachment{}, nil, errors.Join(err, errors.New("erro ao ler o anexo"))
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteAttachment retira o anexo da tarefa e apaga seu conteúdo.
+func (service teamTaskService) DeleteAttachment(taskID, attachmentID int) error {
+	if err := service.requireBlobs(); err != nil {
+		return err
+	}
+
+	attachment, err := service.taskAttachment(taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := service.authorizeTaskEdit(taskID); err != nil {
+		return err
+	}
+
+	err = service.inTransaction(func(tx teamTaskService) error {
+		if err := tx.db.DeleteAttachment(attachmentID); err != nil {
+			return errors.Join(err, errors.New("erro ao excluir o anexo"))
+		}
+		return tx.recordEvent(taskID, TaskDetached, []FieldChange{{Field: "attachment", Before: attachment.Name}})
+	})
+	if err != nil {
+		return err
+	}
+	// O conteúdo só é apagado depois que a exclusão foi confirmada
+	service.removeBlobs(attachment.StorageKey)
+
+	return nil
+}
+
+// taskAttachment busca o anexo e confere se ele pertence à tarefa.
+func (service teamTaskService) taskAttachment(taskID, attachmentID int) (Attachment, error) {
+	attachment, err := service.db.GetAttachment(attachmentID)
+	if err != nil {
+		return Attachment{}, errors.Join(err, errors.New("anexo não encontrado"))
+	}
+	if attachment.TaskID != taskID {
+		return Attachment{}, fmt.Errorf("%w: anexo não encontrado na tarefa", ErrNotFound)
+	}
+
+	return attachment, nil
+}
+
+// removeBlobs apaga o conteúdo de anexos cujos registros já saíram do banco. Uma falha
+// deixa apenas um arquivo órfão, por isso é registrada no log e não interrompe a operação.
+func (service teamTaskService) removeBlobs(keys ...string) {
+	if service.blobs == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := service.blobs.Delete(key); err != nil {
+			service.log.Error(fmt.Sprintf("Erro ao apagar o conteúdo do anexo %s: %v", key, err))
+		}
+	}
+}
+
+func (service teamTaskService) requireBlobs() error {
+	if service.blobs == nil {
+		return fmt.Errorf("%w: nenhum armazenamento de anexos configurado", ErrValidation)
+	}
+	return nil
+}
+
+// attachmentName limpa o nome enviado: fica só o nome do arquivo, sem caracteres de
+// controle e com no máximo 255 caracteres.
+func attachmentName(name string) (string, error) {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "", fmt.Errorf("%w: nome do arquivo é obrigatório", ErrValidation)
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		return "", fmt.Errorf("%w: nome do arquivo com mais de 255 caracteres", ErrValidation)
+	}
+
+	return name, nil
+}
+
+// detectContentType identifica o tipo pelo conteúdo. Quando o conteúdo só diz que é
+// texto ou binário genérico, a extensão do nome refina o tipo (ex.: text/csv).
+func detectContentType(name string, head []byte) string {
+	detected := http.DetectContentType(head)
+	generic := strings.HasPrefix(detected, "text/plain") || detected == "application/octet-stream"
+	if !generic {
+		return detected
+	}
+
+	byExtension := mime.TypeByExtension(strings.ToLower(path.Ext(name)))
+	if byExtension == "" {
+		return detected
+	}
+	// Um texto só ganha o tipo da extensão se ela também for de texto
+	if strings.HasPrefix(detected, "text/plain") && !strings.HasPrefix(byExtension, "text/") && !strings.HasPrefix(byExtension, "application/json") {
+		return detected
+	}
+
+	return byExtension
+}
+
+// attachmentKey gera uma chave única para o conteúdo do anexo, agrupada pela tarefa.
+func attachmentKey(taskID int) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", errors.Join(err, errors.New("erro ao gerar a chave do anexo"))
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(random)), nil
+}
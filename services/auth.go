@@ -0,0 +1,229 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const tokenIssuer = "teamtask"
+
+// TokenPair é o par de tokens entregue ao cliente após login ou renovação.
+type TokenPair struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int    `json:"expiresIn"` // validade do access token em segundos
+}
+
+// Session representa uma sessão de login. O refresh token entregue ao cliente
+// tem a forma "<ID>.<segredo>" e apenas o hash SHA-256 do segredo é armazenado.
+type Session struct {
+	ID          string
+	UserID      int
+	RefreshHash string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	RevokedAt   *time.Time
+}
+
+// accessClaims são as claims do access token (JWT assinado com HS256).
+type accessClaims struct {
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// WithTokenSecret define a chave usada para assinar os access tokens.
+func WithTokenSecret(secret []byte) Option {
+	return func(s *teamTaskService) {
+		s.tokenSecret = secret
+	}
+}
+
+// WithTokenTTL define a validade dos access tokens e dos refresh tokens.
+func WithTokenTTL(access, refresh time.Duration) Option {
+	return func(s *teamTaskService) {
+		s.accessTTL = access
+		s.refreshTTL = refresh
+	}
+}
+
+// Login verifica as credenciais e abre uma nova sessão para o usuário.
+func (service teamTaskService) Login(email, password string) (TokenPair, error) {
+	user, err := service.VerifyCredentials(email, password)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	sessionID, err := randomToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	secret, err := randomToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	now := time.Now()
+	session := Session{
+		ID:          sessionID,
+		UserID:      user.ID,
+		RefreshHash: hashRefreshSecret(secret),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(service.refreshTTL),
+	}
+	if err := service.db.CreateSession(session); err != nil {
+		return TokenPair{}, errors.Join(err, errors.New("erro ao criar sessão"))
+	}
+
+	return service.issueTokens(session, secret)
+}
+
+// RefreshSession troca um refresh token válido por um novo par de tokens. O refresh
+// token é rotacionado a cada uso; reapresentar um token já usado revoga a sessão.
+func (service teamTaskService) RefreshSession(refreshToken string) (TokenPair, error) {
+	sessionID, secret, found := strings.Cut(refreshToken, ".")
+	if !found || sessionID == "" || secret == "" {
+		return TokenPair{}, ErrUnauthorized
+	}
+
+	session, err := service.db.GetSession(sessionID)
+	if errors.Is(err, ErrNotFound) {
+		return TokenPair{}, ErrUnauthorized
+	}
+	if err != nil {
+		return TokenPair{}, errors.Join(err, errors.New("erro ao buscar sessão"))
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return TokenPair{}, ErrUnauthorized
+	}
+	// Usuários na lixeira não renovam a sessão
+	if _, err := service.db.GetUserByID(session.UserID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return TokenPair{}, ErrUnauthorized
+		}
+		return TokenPair{}, errors.Join(err, errors.New("erro ao buscar usuário"))
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashRefreshSecret(secret)), []byte(session.RefreshHash)) != 1 {
+		// Token antigo reapresentado: provável vazamento, encerrar a sessão inteira
+		service.log.Warn("Refresh token reutilizado, revogando sessão " + session.ID)
+		if err := service.db.RevokeSession(session.ID, now); err != nil {
+			service.log.Error("Erro ao revogar sessão: " + err.Error())
+		}
+		return TokenPair{}, ErrUnauthorized
+	}
+
+	newSecret, err := randomToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	session.RefreshHash = hashRefreshSecret(newSecret)
+	session.ExpiresAt = now.Add(service.refreshTTL)
+	if err := service.db.UpdateSessionRefresh(session.ID, session.RefreshHash, session.ExpiresAt); err != nil {
+		return TokenPair{}, errors.Join(err, errors.New("erro ao renovar sessão"))
+	}
+
+	return service.issueTokens(session, newSecret)
+}
+
+// Logout revoga a sessão, invalidando o refresh token e os access tokens emitidos para ela.
+func (service teamTaskService) Logout(sessionID string) error {
+	err := service.db.RevokeSession(sessionID, time.Now())
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao encerrar sessão"))
+	}
+
+	return nil
+}
+
+// Authenticate valida um access token e retorna o usuário dono da sessão e o ID da sessão.
+func (service teamTaskService) Authenticate(accessToken string) (User, string, error) {
+	claims := &accessClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return service.tokenSecret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return User{}, "", ErrUnauthorized
+	}
+
+	// A sessão precisa continuar ativa: o logout invalida os access tokens imediatamente
+	session, err := service.db.GetSession(claims.SessionID)
+	if err != nil || session.RevokedAt != nil {
+		return User{}, "", ErrUnauthorized
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID != session.UserID {
+		return User{}, "", ErrUnauthorized
+	}
+
+	user, err := service.db.GetUserByID(userID)
+	if err != nil {
+		return User{}, "", ErrUnauthorized
+	}
+
+	user.Password = ""
+	return user, session.ID, nil
+}
+
+// issueTokens assina um novo access token para a sessão e monta o par de tokens.
+func (service teamTaskService) issueTokens(session Session, refreshSecret string) (TokenPair, error) {
+	jti, err := randomToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	now := time.Now()
+	claims := accessClaims{
+		SessionID: session.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.Itoa(session.UserID),
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(service.accessTTL)),
+		},
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(service.tokenSecret)
+	if err != nil {
+		return TokenPair{}, errors.Join(err, errors.New("erro ao assinar token"))
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: session.ID + "." + refreshSecret,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(service.accessTTL.Seconds()),
+	}, nil
+}
+
+// randomToken gera 32 bytes aleatórios codificados em base64 sem padding.
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashRefreshSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
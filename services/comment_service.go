@@ -0,0 +1,225 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Limites da paginação de comentários.
+const (
+	DefaultCommentPageSize = 20
+	MaxCommentPageSize     = 100
+)
+
+// mentionPattern reconhece menções no formato @e-mail, por exemplo "@maria@empresa.com".
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.%+\-]+@[\w\-]+(?:\.[\w\-]+)+)`)
+
+// CreateComment publica um comentário na tarefa em nome do usuário atual. Respostas
+// informam ParentID e ficam sempre presas ao comentário raiz da conversa.
+func (service teamTaskService) CreateComment(taskID int, comment Comment) (Comment, error) {
+	if err := service.authorize(PermComment); err != nil {
+		return Comment{}, err
+	}
+	if service.actor == nil {
+		return Comment{}, fmt.Errorf("%w: comentários precisam de um autor", ErrUnauthorized)
+	}
+
+	comment.Text = strings.TrimSpace(comment.Text)
+	if comment.Text == "" {
+		return Comment{}, fmt.Errorf("%w: texto do comentário é obrigatório", ErrValidation)
+	}
+
+	task, err := service.db.GetTaskByID(taskID)
+	if err != nil {
+		return Comment{}, errors.Join(err, errors.New("tarefa não encontrada"))
+	}
+
+	if comment.ParentID != 0 {
+		parent, err := service.db.GetComment(comment.ParentID)
+		if err != nil {
+			return Comment{}, errors.Join(err, errors.New("comentário respondido não encontrado"))
+		}
+		if parent.TaskID != taskID {
+			return Comment{}, fmt.Errorf("%w: o comentário respondido é de outra tarefa", ErrValidation)
+		}
+		if parent.ParentID != 0 {
+			comment.ParentID = parent.ParentID
+		}
+	}
+
+	comment.ID = 0
+	comment.TaskID = taskID
+	comment.AuthorID = service.actor.ID
+	comment.CreatedAt = time.Now()
+	comment.EditedAt = nil
+
+	comment.Mentions, err = service.resolveMentions(comment.Text)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	comment.ID, err = service.db.CreateComment(comment)
+	if err != nil {
+		return Comment{}, errors.Join(err, errors.New("erro ao salvar o comentário"))
+	}
+
+	// Quem foi mencionado recebe só a menção, não também o aviso de comentário
+	service.notify(NotificationMention, taskID, fmt.Sprintf("%s mencionou você na tarefa #%d: %s", service.actor.Name, taskID, task.Title), comment.Mentions...)
+	service.notify(NotificationComment, taskID, fmt.Sprintf("%s comentou na tarefa #%d: %s", service.actor.Name, taskID, task.Title), excludeIDs(service.taskAssignees(taskID), comment.Mentions)...)
+
+	return comment, nil
+}
+
+// EditComment altera o texto de um comentário. Apenas o autor pode editá-lo.
+func (service teamTaskService) EditComment(taskID, commentID int, text string) (Comment, error) {
+	comment, err := service.commentOfTask(taskID, commentID)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	if service.actor != nil && service.actor.ID != comment.AuthorID {
+		return Comment{}, fmt.Errorf("%w: apenas o autor edita o comentário", ErrForbidden)
+	}
+
+	comment.Text = strings.TrimSpace(text)
+	if comment.Text == "" {
+		return Comment{}, fmt.Errorf("%w: texto do comentário é obrigatório", ErrValidation)
+	}
+
+	editedAt := time.Now()
+	comment.EditedAt = &editedAt
+	previousMentions := comment.Mentions
+
+	comment.Mentions, err = service.resolveMentions(comment.Text)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	err = service.db.UpdateComment(comment)
+	if err != nil {
+		return Comment{}, errors.Join(err, errors.New("erro ao editar o comentário"))
+	}
+
+	// Notificar apenas quem passou a ser mencionado nesta edição
+	if newMentions := excludeIDs(comment.Mentions, previousMentions); len(newMentions) > 0 {
+		author := "Um usuário"
+		if service.actor != nil {
+			author = service.actor.Name
+		}
+		service.notify(NotificationMention, taskID, fmt.Sprintf("%s mencionou você na tarefa #%d", author, taskID), newMentions...)
+	}
+
+	return comment, nil
+}
+
+// DeleteComment remove um comentário e, se ele for raiz, as respostas da conversa.
+// O autor e quem modera tarefas podem excluí-lo.
+func (service teamTaskService) DeleteComment(taskID, commentID int) error {
+	comment, err := service.commentOfTask(taskID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if service.actor != nil && service.actor.ID != comment.AuthorID {
+		if err := service.authorize(PermModerateComments); err != nil {
+			return err
+		}
+	}
+
+	err = service.db.DeleteComment(commentID)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao excluir o comentário"))
+	}
+
+	return nil
+}
+
+// ListComments retorna uma página dos comentários da tarefa, do mais antigo ao mais recente.
+func (service teamTaskService) ListComments(taskID, limit, offset int) (CommentPage, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return CommentPage{}, err
+	}
+
+	_, err := service.db.GetTaskByID(taskID)
+	if err != nil {
+		return CommentPage{}, errors.Join(err, errors.New("tarefa não encontrada"))
+	}
+
+	if limit <= 0 {
+		limit = DefaultCommentPageSize
+	}
+	if limit > MaxCommentPageSize {
+		limit = MaxCommentPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	comments, total, err := service.db.ListComments(taskID, limit, offset)
+	if err != nil {
+		return CommentPage{}, errors.Join(err, errors.New("erro ao listar os comentários"))
+	}
+	if comments == nil {
+		comments = []Comment{}
+	}
+
+	return CommentPage{Comments: comments, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// commentOfTask busca o comentário e confere se ele pertence à tarefa.
+func (service teamTaskService) commentOfTask(taskID, commentID int) (Comment, error) {
+	comment, err := service.db.GetComment(commentID)
+	if err != nil {
+		return Comment{}, errors.Join(err, errors.New("comentário não encontrado"))
+	}
+	if comment.TaskID != taskID {
+		return Comment{}, fmt.Errorf("%w: comentário não pertence à tarefa", ErrNotFound)
+	}
+
+	return comment, nil
+}
+
+// resolveMentions extrai as menções do texto e retorna os IDs dos usuários
+// mencionados, sem repetições. Menções a e-mails não cadastrados são ignoradas.
+func (service teamTaskService) resolveMentions(text string) ([]int, error) {
+	var mentions []int
+	seen := make(map[int]bool)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		email := strings.TrimRight(match[1], ".")
+
+		user, err := service.db.GetUserByEmail(email)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Join(err, errors.New("erro ao buscar usuário mencionado"))
+		}
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			mentions = append(mentions, user.ID)
+		}
+	}
+
+	return mentions, nil
+}
+
+// excludeIDs retorna os IDs de ids que não estão em excluded.
+func excludeIDs(ids, excluded []int) []int {
+	skip := make(map[int]bool, len(excluded))
+	for _, id := range excluded {
+		skip[id] = true
+	}
+
+	var result []int
+	for _, id := range ids {
+		if !skip[id] {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Erros conhecidos retornados pela camada de serviço. Use errors.Is para identificá-los.
+var (
+	ErrInvalidCredentials = errors.New("e-mail ou senha inválidos")
+	ErrUnauthorized       = errors.New("não autenticado")
+	ErrNotFound           = errors.New("registro não encontrado")
+	ErrForbidden          = errors.New("permissão negada")
+	ErrValidation         = errors.New("dados inválidos")
+	ErrConflict           = errors.New("registro já existe")
+	ErrInvalidTransition  = errors.New("transição de status não permitida")
+	ErrTooLarge           = errors.New("arquivo grande demais")
+	ErrUnsupportedType    = errors.New("tipo de arquivo não permitido")
+	ErrPreconditionFailed = errors.New("a versão informada não é a atual")
+)
+
+// VersionError indica que a tarefa não está mais na versão esperada. Com IfMatch a versão
+// veio do cliente e o erro equivale a ErrPreconditionFailed; sem ele outra alteração foi
+// gravada entre a leitura e a escrita, e o erro equivale a ErrConflict.
+type VersionError struct {
+	Current int // versão atual da tarefa
+	IfMatch bool
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("a tarefa foi alterada por outra pessoa; a versão atual é %d", e.Current)
+}
+
+func (e *VersionError) Is(target error) bool {
+	if e.IfMatch {
+		return target == ErrPreconditionFailed
+	}
+	return target == ErrConflict
+}
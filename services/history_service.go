@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TaskCreated    TaskEventKind = "created"
+	TaskUpdated    TaskEventKind = "updated"
+	TaskDeleted    TaskEventKind = "deleted"
+	TaskAssigned   TaskEventKind = "assigned"
+	TaskUnassigned TaskEventKind = "unassigned"
+	TaskLabeled    TaskEventKind = "labeled"
+	TaskUnlabeled  TaskEventKind = "unlabeled"
+)
+
+// trackedFields são os campos da tarefa comparados no histórico, na ordem em que
+// aparecem nos eventos. As datas de criação e de atualização ficam de fora: elas são a
+// própria data do evento.
+var trackedFields = []struct {
+	name  string
+	value func(task Task) string
+}{
+	{"title", func(task Task) string { return task.Title }},
+	{"description", func(task Task) string { return task.Description }},
+	{"status", func(task Task) string { return task.Status }},
+	{"priority", func(task Task) string { return task.Priority }},
+	{"type", func(task Task) string { return task.Type }},
+	{"resolution", func(task Task) string { return task.Resolution }},
+	{"teamId", func(task Task) string { return formatID(task.TeamID) }},
+	{"parentId", func(task Task) string { return formatID(task.ParentID) }},
+	{"startDate", func(task Task) string { return formatTime(task.StartDate) }},
+	{"dueDate", func(task Task) string { return formatTime(task.DueDate) }},
+	{"completedAt", func(task Task) string { return formatTime(task.CompletedAt) }},
+}
+
+// GetTaskHistory retorna os eventos da tarefa em ordem cronológica. O histórico de uma
+// tarefa excluída continua disponível.
+func (service teamTaskService) GetTaskHistory(taskID int) ([]TaskEvent, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	events, err := service.db.ListTaskEvents(taskID)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter o histórico da tarefa"))
+	}
+	if len(events) == 0 {
+		// Tarefas criadas antes do histórico existir não têm eventos
+		if _, err := service.db.GetTaskByID(taskID); err != nil {
+			return nil, errors.Join(err, errors.New("tarefa não encontrada"))
+		}
+		return []TaskEvent{}, nil
+	}
+
+	return events, nil
+}
+
+// recordEvent grava um evento no histórico da tarefa, em nome do usuário atual. Deve ser
+// chamado na mesma transação da mudança, para que uma não exista sem a outra.
+func (service teamTaskService) recordEvent(taskID int, kind TaskEventKind, changes []FieldChange) error {
+	event := TaskEvent{
+		TaskID:    taskID,
+		Kind:      kind,
+		Changes:   changes,
+		CreatedAt: time.Now().UTC(),
+	}
+	if service.actor != nil {
+		event.ActorID = service.actor.ID
+	}
+
+	if _, err := service.db.CreateTaskEvent(event); err != nil {
+		return errors.Join(err, errors.New("erro ao registrar o histórico da tarefa"))
+	}
+
+	return nil
+}
+
+// recordUpdate registra as diferenças entre as duas versões da tarefa; sem diferenças
+// nada é gravado.
+func (service teamTaskService) recordUpdate(before, after Task) error {
+	changes := taskChanges(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	return service.recordEvent(before.ID, TaskUpdated, changes)
+}
+
+// taskChanges compara os campos acompanhados pelo histórico.
+func taskChanges(before, after Task) []FieldChange {
+	var changes []FieldChange
+	for _, field := range trackedFields {
+		if was, is := field.value(before), field.value(after); was != is {
+			changes = append(changes, FieldChange{Field: field.name, Before: was, After: is})
+		}
+	}
+	return changes
+}
+
+// taskSnapshot lista os campos preenchidos da tarefa, com os responsáveis, como os valores
+// depois da criação ou, com deleted, antes da exclusão.
+func taskSnapshot(task Task, deleted bool) []FieldChange {
+	var empty Task
+	changes := taskChanges(empty, task)
+	if len(task.AssignedUsers) > 0 {
+		ids := make([]string, len(task.AssignedUsers))
+		for i, id := range task.AssignedUsers {
+			ids[i] = strconv.Itoa(id)
+		}
+		changes = append(changes, FieldChange{Field: "assignedUsers", After: strings.Join(ids, ",")})
+	}
+
+	if deleted {
+		for i := range changes {
+			changes[i].Before, changes[i].After = changes[i].After, ""
+		}
+	}
+	return changes
+}
+
+func formatID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
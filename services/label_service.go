@@ -0,0 +1,330 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultLabelColor é a cor das etiquetas criadas sem cor.
+const defaultLabelColor = "#9e9e9e"
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// TaskFilter seleciona tarefas em FilterTasks; campos vazios não filtram. Com
+// MatchAllLabels a tarefa precisa ter todas as etiquetas de Labels, sem ele basta uma.
+type TaskFilter struct {
+	Status         string
+	Priority       string // código ou nome exibido
+	Labels         []int
+	MatchAllLabels bool
+}
+
+// FilterTasks retorna as tarefas que atendem ao filtro, com suas etiquetas e bloqueios.
+func (service teamTaskService) FilterTasks(filter TaskFilter) ([]Task, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	if filter.Priority != "" {
+		p, err := service.resolvePriority(filter.Priority)
+		if err != nil {
+			return nil, err
+		}
+		filter.Priority = p.Code
+	}
+
+	tasks, err := service.db.GetAllTasks()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter tarefas"))
+	}
+	tasks, err = service.withDetails(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	var filteredTasks []Task
+	for _, task := range tasks {
+		if (filter.Status == "" || task.Status == filter.Status) &&
+			(filter.Priority == "" || task.Priority == filter.Priority) &&
+			filter.matchesLabels(task) {
+			filteredTasks = append(filteredTasks, task)
+		}
+	}
+
+	return filteredTasks, nil
+}
+
+// matchesLabels informa se a tarefa tem uma das etiquetas do filtro, ou todas com
+// MatchAllLabels.
+func (filter TaskFilter) matchesLabels(task Task) bool {
+	if len(filter.Labels) == 0 {
+		return true
+	}
+
+	has := make(map[int]bool, len(task.Labels))
+	for _, label := range task.Labels {
+		has[label.ID] = true
+	}
+	for _, labelID := range filter.Labels {
+		if has[labelID] && !filter.MatchAllLabels {
+			return true
+		}
+		if !has[labelID] && filter.MatchAllLabels {
+			return false
+		}
+	}
+
+	return filter.MatchAllLabels
+}
+
+// withDetails preenche os campos calculados das tarefas lidas do repositório: as
+// etiquetas, os responsáveis e os bloqueios.
+func (service teamTaskService) withDetails(tasks []Task) ([]Task, error) {
+	tasks, err := service.withLabels(tasks)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err = service.withAssignees(tasks)
+	if err != nil {
+		return nil, err
+	}
+	return service.withBlockers(tasks)
+}
+
+// withLabels preenche as etiquetas das tarefas.
+func (service teamTaskService) withLabels(tasks []Task) ([]Task, error) {
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	labels, err := service.db.GetLabelsForTasks(ids)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter as etiquetas das tarefas"))
+	}
+
+	for i := range tasks {
+		tasks[i].Labels = labels[tasks[i].ID]
+	}
+
+	return tasks, nil
+}
+
+// CreateLabel cria uma etiqueta na equipe. Apenas quem administra a equipe cria etiquetas.
+func (service teamTaskService) CreateLabel(label Label) (int, error) {
+	if _, err := service.teamForManagement(label.TeamID); err != nil {
+		return 0, err
+	}
+
+	label, err := normalizeLabel(label)
+	if err != nil {
+		return 0, err
+	}
+	if err := service.checkLabelName(label); err != nil {
+		return 0, err
+	}
+
+	labelID, err := service.db.CreateLabel(label)
+	if err != nil {
+		if errors.Is(err, ErrConflict) {
+			return 0, fmt.Errorf("%w: a equipe já tem uma etiqueta %q", ErrConflict, label.Name)
+		}
+		return 0, errors.Join(err, errors.New("erro ao criar a etiqueta"))
+	}
+
+	return labelID, nil
+}
+
+// ListTeamLabels retorna as etiquetas da equipe, em ordem de nome.
+func (service teamTaskService) ListTeamLabels(teamID int) ([]Label, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	if _, err := service.db.GetTeamByID(teamID); err != nil {
+		return nil, errors.Join(err, errors.New("equipe não encontrada"))
+	}
+
+	labels, err := service.db.ListLabels(teamID)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter as etiquetas da equipe"))
+	}
+
+	return labels, nil
+}
+
+// UpdateLabel renomeia ou muda a cor de uma etiqueta; a equipe não muda.
+func (service teamTaskService) UpdateLabel(labelID int, label Label) error {
+	current, err := service.labelForManagement(labelID)
+	if err != nil {
+		return err
+	}
+
+	label.ID, label.TeamID = current.ID, current.TeamID
+	label, err = normalizeLabel(label)
+	if err != nil {
+		return err
+	}
+	if err := service.checkLabelName(label); err != nil {
+		return err
+	}
+
+	if err := service.db.UpdateLabel(label); err != nil {
+		if errors.Is(err, ErrConflict) {
+			return fmt.Errorf("%w: a equipe já tem uma etiqueta %q", ErrConflict, label.Name)
+		}
+		return errors.Join(err, errors.New("erro ao alterar a etiqueta"))
+	}
+
+	return nil
+}
+
+// DeleteLabel exclui uma etiqueta e a retira das tarefas.
+func (service teamTaskService) DeleteLabel(labelID int) error {
+	if _, err := service.labelForManagement(labelID); err != nil {
+		return err
+	}
+
+	if err := service.db.DeleteLabel(labelID); err != nil {
+		return errors.Join(err, errors.New("erro ao excluir a etiqueta"))
+	}
+
+	return nil
+}
+
+// AddTaskLabel aplica uma etiqueta da equipe da tarefa. Quem edita a tarefa pode
+// etiquetá-la.
+func (service teamTaskService) AddTaskLabel(taskID, labelID int) error {
+	return service.inTransaction(func(tx teamTaskService) error {
+		task, label, err := tx.taskAndLabel(taskID, labelID)
+		if err != nil {
+			return err
+		}
+		if label.TeamID != task.TeamID {
+			return fmt.Errorf("%w: a etiqueta não pertence à equipe da tarefa", ErrValidation)
+		}
+
+		if err := tx.db.AddTaskLabel(taskID, labelID); err != nil {
+			if errors.Is(err, ErrConflict) {
+				return fmt.Errorf("%w: a tarefa já tem a etiqueta %q", ErrConflict, label.Name)
+			}
+			return errors.Join(err, errors.New("erro ao etiquetar a tarefa"))
+		}
+
+		return tx.recordEvent(taskID, TaskLabeled, []FieldChange{{Field: "label", After: label.Name}})
+	})
+}
+
+// RemoveTaskLabel retira uma etiqueta da tarefa.
+func (service teamTaskService) RemoveTaskLabel(taskID, labelID int) error {
+	return service.inTransaction(func(tx teamTaskService) error {
+		_, label, err := tx.taskAndLabel(taskID, labelID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.db.RemoveTaskLabel(taskID, labelID); err != nil {
+			return errors.Join(err, errors.New("a tarefa não tem a etiqueta"))
+		}
+
+		return tx.recordEvent(taskID, TaskUnlabeled, []FieldChange{{Field: "label", Before: label.Name}})
+	})
+}
+
+// taskAndLabel busca a tarefa e a etiqueta e verifica se o usuário atual edita a tarefa.
+func (service teamTaskService) taskAndLabel(taskID, labelID int) (Task, Label, error) {
+	task, err := service.db.GetTaskByID(taskID)
+	if err != nil {
+		return Task{}, Label{}, errors.Join(err, errors.New("tarefa não encontrada"))
+	}
+	if err := service.authorizeTaskEdit(taskID); err != nil {
+		return Task{}, Label{}, err
+	}
+
+	label, err := service.db.GetLabel(labelID)
+	if err != nil {
+		return Task{}, Label{}, errors.Join(err, errors.New("etiqueta não encontrada"))
+	}
+
+	return task, label, nil
+}
+
+// labelForManagement busca a etiqueta e verifica se o usuário atual administra sua equipe.
+func (service teamTaskService) labelForManagement(labelID int) (Label, error) {
+	label, err := service.db.GetLabel(labelID)
+	if err != nil {
+		return Label{}, errors.Join(err, errors.New("etiqueta não encontrada"))
+	}
+	if _, err := service.teamForManagement(label.TeamID); err != nil {
+		return Label{}, err
+	}
+
+	return label, nil
+}
+
+// dropForeignLabels retira da tarefa as etiquetas que não são da equipe teamID, usada
+// quando a tarefa muda de equipe.
+func (tx teamTaskService) dropForeignLabels(taskID, teamID int) error {
+	labels, err := tx.db.GetLabelsForTasks([]int{taskID})
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao obter as etiquetas da tarefa"))
+	}
+
+	for _, label := range labels[taskID] {
+		if label.TeamID == teamID {
+			continue
+		}
+		if err := tx.db.RemoveTaskLabel(taskID, label.ID); err != nil {
+			return errors.Join(err, errors.New("erro ao retirar a etiqueta da tarefa"))
+		}
+		if err := tx.recordEvent(taskID, TaskUnlabeled, []FieldChange{{Field: "label", Before: label.Name}}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkLabelName recusa um nome já usado por outra etiqueta da equipe, sem diferenciar
+// maiúsculas de minúsculas, como faz o MySQL e não fazem os outros bancos.
+func (service teamTaskService) checkLabelName(label Label) error {
+	labels, err := service.db.ListLabels(label.TeamID)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao obter as etiquetas da equipe"))
+	}
+
+	for _, other := range labels {
+		if other.ID != label.ID && strings.EqualFold(other.Name, label.Name) {
+			return fmt.Errorf("%w: a equipe já tem uma etiqueta %q", ErrConflict, other.Name)
+		}
+	}
+
+	return nil
+}
+
+// normalizeLabel valida o nome e a cor de uma etiqueta. A cor é gravada em minúsculas.
+func normalizeLabel(label Label) (Label, error) {
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" {
+		return Label{}, fmt.Errorf("%w: nome da etiqueta é obrigatório", ErrValidation)
+	}
+	if utf8.RuneCountInString(label.Name) > 50 {
+		return Label{}, fmt.Errorf("%w: nome da etiqueta com mais de 50 caracteres", ErrValidation)
+	}
+
+	label.Color = strings.ToLower(strings.TrimSpace(label.Color))
+	if label.Color == "" {
+		label.Color = defaultLabelColor
+	}
+	if !labelColorPattern.MatchString(label.Color) {
+		return Label{}, fmt.Errorf("%w: cor inválida %q; use o formato #rrggbb", ErrValidation, label.Color)
+	}
+
+	return label, nil
+}
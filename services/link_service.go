@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Eventos registrados, nas duas tarefas, quando uma dependência é criada ou desfeita.
+const (
+	TaskLinked   TaskEventKind = "linked"
+	TaskUnlinked TaskEventKind = "unlinked"
+)
+
+// LinkPolicy controla o efeito das dependências entre tarefas.
+type LinkPolicy struct {
+	// RefuseBlockedStart recusa levar uma tarefa bloqueada a um estado iniciado; sem ele
+	// a mudança é aceita e apenas registrada no log.
+	RefuseBlockedStart bool `yaml:"refuseBlockedStart"`
+}
+
+// DefaultLinkPolicy retorna a política usada quando nenhuma é configurada.
+func DefaultLinkPolicy() LinkPolicy {
+	return LinkPolicy{RefuseBlockedStart: true}
+}
+
+// WithLinkPolicy define a política de dependências entre tarefas.
+func WithLinkPolicy(policy LinkPolicy) Option {
+	return func(s *teamTaskService) {
+		s.links = policy
+	}
+}
+
+// AddTaskLink registra que blockerID bloqueia blockedID. Quem edita a tarefa bloqueada
+// cria a dependência; dependências que fechariam um ciclo são recusadas.
+func (service teamTaskService) AddTaskLink(blockerID, blockedID int) error {
+	return service.inTransaction(func(tx teamTaskService) error {
+		if err := tx.linkTasks(blockerID, blockedID); err != nil {
+			return err
+		}
+		if blockerID == blockedID {
+			return fmt.Errorf("%w: a tarefa não pode bloquear a si mesma", ErrValidation)
+		}
+
+		if path, err := tx.blockingPath(blockedID, blockerID); err != nil {
+			return err
+		} else if path != nil {
+			return fmt.Errorf("%w: a dependência criaria um ciclo: %s → #%d", ErrValidation, formatTaskIDs(path, " → "), blockedID)
+		}
+
+		if err := tx.db.AddTaskLink(TaskLink{BlockerID: blockerID, BlockedID: blockedID}); err != nil {
+			if errors.Is(err, ErrConflict) {
+				return fmt.Errorf("%w: a tarefa #%d já bloqueia #%d", ErrConflict, blockerID, blockedID)
+			}
+			return errors.Join(err, errors.New("erro ao criar a dependência"))
+		}
+
+		return tx.recordLink(TaskLinked, blockerID, blockedID)
+	})
+}
+
+// RemoveTaskLink desfaz a dependência entre as tarefas.
+func (service teamTaskService) RemoveTaskLink(blockerID, blockedID int) error {
+	return service.inTransaction(func(tx teamTaskService) error {
+		if err := tx.linkTasks(blockerID, blockedID); err != nil {
+			return err
+		}
+
+		if err := tx.db.RemoveTaskLink(TaskLink{BlockerID: blockerID, BlockedID: blockedID}); err != nil {
+			return errors.Join(err, errors.New("dependência não encontrada"))
+		}
+
+		return tx.recordLink(TaskUnlinked, blockerID, blockedID)
+	})
+}
+
+// recordLink registra a mudança da dependência no histórico das duas tarefas: na
+// bloqueadora o campo blocks, na bloqueada o campo blockedBy.
+func (service teamTaskService) recordLink(kind TaskEventKind, blockerID, blockedID int) error {
+	change := func(field string, other int) []FieldChange {
+		if kind == TaskLinked {
+			return []FieldChange{{Field: field, After: strconv.Itoa(other)}}
+		}
+		return []FieldChange{{Field: field, Before: strconv.Itoa(other)}}
+	}
+
+	if err := service.recordEvent(blockerID, kind, change("blocks", blockedID)); err != nil {
+		return err
+	}
+	return service.recordEvent(blockedID, kind, change("blockedBy", blockerID))
+}
+
+// GetTaskLinks retorna as dependências em que a tarefa bloqueia ou é bloqueada.
+func (service teamTaskService) GetTaskLinks(taskID int) ([]TaskLink, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	if _, err := service.db.GetTaskByID(taskID); err != nil {
+		return nil, errors.Join(err, errors.New("tarefa não encontrada"))
+	}
+
+	links, err := service.db.GetTaskLinks(taskID)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter as dependências da tarefa"))
+	}
+
+	// As dependências com tarefas da lixeira ficam guardadas, mas não aparecem
+	visible := make([]TaskLink, 0, len(links))
+	for _, link := range links {
+		other := link.BlockerID
+		if other == taskID {
+			other = link.BlockedID
+		}
+		_, err := service.db.GetTaskByID(other)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Join(err, errors.New("erro ao obter as dependências da tarefa"))
+		}
+		visible = append(visible, link)
+	}
+
+	return visible, nil
+}
+
+// linkTasks confere se as duas tarefas existem e se o usuário atual edita a bloqueada.
+func (service teamTaskService) linkTasks(blockerID, blockedID int) error {
+	if _, err := service.db.GetTaskByID(blockerID); err != nil {
+		return errors.Join(err, errors.New("tarefa bloqueadora não encontrada"))
+	}
+	if _, err := service.db.GetTaskByID(blockedID); err != nil {
+		return errors.Join(err, errors.New("tarefa bloqueada não encontrada"))
+	}
+
+	return service.authorizeTaskEdit(blockedID)
+}
+
+// blockingPath procura, seguindo as dependências existentes, um caminho em que from
+// bloqueia, direta ou indiretamente, to. Retorna o caminho de from até to, ou nil.
+func (service teamTaskService) blockingPath(from, to int) ([]int, error) {
+	previous := map[int]int{from: 0}
+	queue := []int{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			var path []int
+			for id := to; id != 0; id = previous[id] {
+				path = append([]int{id}, path...)
+			}
+			return path, nil
+		}
+
+		links, err := service.db.GetTaskLinks(current)
+		if err != nil {
+			return nil, errors.Join(err, errors.New("erro ao obter as dependências da tarefa"))
+		}
+		for _, link := range links {
+			if _, seen := previous[link.BlockedID]; link.BlockerID == current && !seen {
+				previous[link.BlockedID] = current
+				queue = append(queue, link.BlockedID)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// formatTaskIDs escreve os IDs como #1, #2, separados por sep, para as mensagens.
+func formatTaskIDs(ids []int, sep string) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = "#" + strconv.Itoa(id)
+	}
+	return strings.Join(names, sep)
+}
+
+// withBlockers preenche Blocked e BlockedBy com as tarefas abertas que bloqueiam cada
+// tarefa.
+func (service teamTaskService) withBlockers(tasks []Task) ([]Task, error) {
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	blockers, err := service.db.GetOpenBlockers(ids)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter as dependências das tarefas"))
+	}
+
+	for i := range tasks {
+		tasks[i].BlockedBy = blockers[tasks[i].ID]
+		tasks[i].Blocked = len(tasks[i].BlockedBy) > 0
+	}
+
+	return tasks, nil
+}
+
+// checkBlockedStart aplica a política de dependências quando a tarefa entra no estado
+// iniciado to vindo de outro status.
+func (service teamTaskService) checkBlockedStart(current Task, to WorkflowState) error {
+	if !to.Started || strings.EqualFold(current.Status, to.Name) {
+		return nil
+	}
+
+	blockers, err := service.db.GetOpenBlockers([]int{current.ID})
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao obter as dependências da tarefa"))
+	}
+	open := blockers[current.ID]
+	if len(open) == 0 {
+		return nil
+	}
+
+	if service.links.RefuseBlockedStart {
+		return fmt.Errorf("%w: a tarefa está bloqueada por %s", ErrConflict, formatTaskIDs(open, ", "))
+	}
+	service.log.Warn(fmt.Sprintf("A tarefa #%d entrou em %q bloqueada por %s", current.ID, to.Name, formatTaskIDs(open, ", ")))
+
+	return nil
+}
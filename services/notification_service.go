@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Categorias de notificação. O usuário pode desativar cada uma nas preferências.
+const (
+	NotificationAssignment   NotificationType = "assignment"
+	NotificationStatusChange NotificationType = "status_change"
+	NotificationComment      NotificationType = "comment"
+	NotificationMention      NotificationType = "mention"
+)
+
+// NotificationTypes lista todas as categorias de notificação conhecidas.
+var NotificationTypes = []NotificationType{
+	NotificationAssignment,
+	NotificationStatusChange,
+	NotificationComment,
+	NotificationMention,
+}
+
+// ListNotifications retorna as notificações do usuário atual, da mais recente à mais antiga.
+func (service teamTaskService) ListNotifications(unreadOnly bool) ([]Notification, error) {
+	if service.actor == nil {
+		return nil, ErrUnauthorized
+	}
+
+	notifications, err := service.db.ListNotifications(service.actor.ID, unreadOnly)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao listar as notificações"))
+	}
+	if notifications == nil {
+		notifications = []Notification{}
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationRead marca uma notificação do usuário atual como lida.
+func (service teamTaskService) MarkNotificationRead(notificationID int) error {
+	if service.actor == nil {
+		return ErrUnauthorized
+	}
+
+	notification, err := service.db.GetNotification(notificationID)
+	if err != nil {
+		return errors.Join(err, errors.New("notificação não encontrada"))
+	}
+	if notification.RecipientID != service.actor.ID {
+		// Não revelar a existência de notificações de outros usuários
+		return fmt.Errorf("%w: notificação não encontrada", ErrNotFound)
+	}
+
+	err = service.db.MarkNotificationRead(notificationID)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao marcar a notificação como lida"))
+	}
+
+	return nil
+}
+
+// MarkAllNotificationsRead marca todas as notificações do usuário atual como lidas.
+func (service teamTaskService) MarkAllNotificationsRead() error {
+	if service.actor == nil {
+		return ErrUnauthorized
+	}
+
+	err := service.db.MarkAllNotificationsRead(service.actor.ID)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao marcar as notificações como lidas"))
+	}
+
+	return nil
+}
+
+// GetNotificationPreferences retorna, para cada categoria, se o usuário atual a recebe.
+func (service teamTaskService) GetNotificationPreferences() (map[NotificationType]bool, error) {
+	if service.actor == nil {
+		return nil, ErrUnauthorized
+	}
+
+	return service.notificationPreferences(service.actor.ID)
+}
+
+// UpdateNotificationPreferences ativa ou desativa categorias de notificação para o
+// usuário atual. Categorias omitidas mantêm a preferência anterior.
+func (service teamTaskService) UpdateNotificationPreferences(prefs map[NotificationType]bool) error {
+	if service.actor == nil {
+		return ErrUnauthorized
+	}
+
+	for notificationType := range prefs {
+		if !isNotificationType(notificationType) {
+			return fmt.Errorf("%w: categoria de notificação desconhecida %q", ErrValidation, notificationType)
+		}
+	}
+
+	// Ou todas as preferências são salvas, ou nenhuma
+	return service.inTransaction(func(tx teamTaskService) error {
+		for notificationType, enabled := range prefs {
+			err := tx.db.SetNotificationPreference(tx.actor.ID, notificationType, enabled)
+			if err != nil {
+				return errors.Join(err, errors.New("erro ao salvar as preferências de notificação"))
+			}
+		}
+
+		return nil
+	})
+}
+
+// notificationPreferences completa as preferências gravadas com o padrão: tudo ativado.
+func (service teamTaskService) notificationPreferences(userID int) (map[NotificationType]bool, error) {
+	stored, err := service.db.GetNotificationPreferences(userID)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter as preferências de notificação"))
+	}
+
+	prefs := make(map[NotificationType]bool, len(NotificationTypes))
+	for _, notificationType := range NotificationTypes {
+		enabled, ok := stored[notificationType]
+		prefs[notificationType] = !ok || enabled
+	}
+
+	return prefs, nil
+}
+
+// notify envia uma notificação aos destinatários que não desativaram a categoria.
+// Quem causou o evento não é notificado. Falhas são apenas registradas, para não
+// desfazer a operação que originou a notificação.
+func (service teamTaskService) notify(notificationType NotificationType, taskID int, content string, recipients ...int) {
+	sent := make(map[int]bool)
+	for _, userID := range recipients {
+		if userID == 0 || sent[userID] || (service.actor != nil && service.actor.ID == userID) {
+			continue
+		}
+		sent[userID] = true
+
+		prefs, err := service.notificationPreferences(userID)
+		if err != nil {
+			service.log.Error(err.Error())
+			continue
+		}
+		if !prefs[notificationType] {
+			continue
+		}
+
+		_, err = service.db.CreateNotification(Notification{
+			RecipientID: userID,
+			Type:        notificationType,
+			Content:     content,
+			TaskID:      taskID,
+			CreatedAt:   time.Now(),
+		})
+		if err != nil {
+			service.log.Error("Erro ao criar notificação: " + err.Error())
+		}
+	}
+}
+
+// taskAssignees retorna os usuários atribuídos à tarefa; em caso de erro, nenhum.
+func (service teamTaskService) taskAssignees(taskID int) []int {
+	assignees, err := service.db.GetTaskAssignees(taskID)
+	if err != nil {
+		service.log.Error("Erro ao obter os responsáveis da tarefa: " + err.Error())
+		return nil
+	}
+
+	return assignees
+}
+
+func isNotificationType(notificationType NotificationType) bool {
+	for _, known := range NotificationTypes {
+		if known == notificationType {
+			return true
+		}
+	}
+
+	return false
+}
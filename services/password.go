@@ -0,0 +1,170 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algoritmos de hash de senha suportados.
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// HashParams define o algoritmo e os parâmetros usados para gerar novos hashes de senha.
+type HashParams struct {
+	Algorithm string
+
+	// Parâmetros do argon2id
+	Memory      uint32 // em KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+
+	// Parâmetro do bcrypt
+	Cost int
+}
+
+// DefaultHashParams retorna os parâmetros recomendados para o argon2id.
+func DefaultHashParams() HashParams {
+	return HashParams{
+		Algorithm:   AlgorithmArgon2id,
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+		Cost:        bcrypt.DefaultCost,
+	}
+}
+
+// PasswordHasher gera e verifica hashes de senha. O hash armazenado carrega o
+// algoritmo e os parâmetros usados, o que permite detectar hashes desatualizados.
+type PasswordHasher struct {
+	params HashParams
+}
+
+// NewPasswordHasher cria um PasswordHasher com os parâmetros informados.
+func NewPasswordHasher(params HashParams) PasswordHasher {
+	return PasswordHasher{params: params}
+}
+
+// Hash gera o hash codificado da senha com os parâmetros atuais.
+func (h PasswordHasher) Hash(password string) (string, error) {
+	switch h.params.Algorithm {
+	case AlgorithmBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.params.Cost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	case AlgorithmArgon2id:
+		salt := make([]byte, h.params.SaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version, h.params.Memory, h.params.Iterations, h.params.Parallelism,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key),
+		), nil
+	}
+
+	return "", fmt.Errorf("algoritmo de hash desconhecido: %q", h.params.Algorithm)
+}
+
+// Verify confere a senha contra o hash armazenado. needsRehash indica que a senha
+// confere, mas o hash foi gerado com outro algoritmo ou parâmetros e deve ser refeito.
+// Valores sem prefixo de algoritmo são tratados como senhas legadas em texto puro.
+// Senha ou valor armazenado vazios nunca conferem.
+func (h PasswordHasher) Verify(password, encoded string) (ok bool, needsRehash bool, err error) {
+	if password == "" || encoded == "" {
+		return false, false, nil
+	}
+
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, false, err
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(candidate, key) != 1 {
+			return false, false, nil
+		}
+		needsRehash = h.params.Algorithm != AlgorithmArgon2id ||
+			params.Memory != h.params.Memory ||
+			params.Iterations != h.params.Iterations ||
+			params.Parallelism != h.params.Parallelism ||
+			uint32(len(salt)) != h.params.SaltLength ||
+			uint32(len(key)) != h.params.KeyLength
+		return true, needsRehash, nil
+
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		if err != nil {
+			return false, false, err
+		}
+		needsRehash = h.params.Algorithm != AlgorithmBcrypt || cost != h.params.Cost
+		return true, needsRehash, nil
+
+	case strings.HasPrefix(encoded, "$"):
+		return false, false, errors.New("formato de hash de senha desconhecido")
+	}
+
+	// Senha legada gravada em texto puro: sempre precisa ser refeita
+	if subtle.ConstantTimeCompare([]byte(password), []byte(encoded)) != 1 {
+		return false, false, nil
+	}
+	return true, true, nil
+}
+
+// decodeArgon2id extrai parâmetros, salt e chave de um hash no formato PHC do argon2id.
+func decodeArgon2id(encoded string) (HashParams, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return HashParams{}, nil, nil, errors.New("hash argon2id malformado")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return HashParams{}, nil, nil, errors.New("hash argon2id malformado")
+	}
+	if version != argon2.Version {
+		return HashParams{}, nil, nil, errors.New("versão do argon2id incompatível")
+	}
+
+	params := HashParams{Algorithm: AlgorithmArgon2id}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return HashParams{}, nil, nil, errors.New("hash argon2id malformado")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return HashParams{}, nil, nil, errors.New("hash argon2id malformado")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return HashParams{}, nil, nil, errors.New("hash argon2id malformado")
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
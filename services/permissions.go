@@ -0,0 +1,127 @@
+package service
+
+import "fmt"
+
+// Role é o papel global de um usuário no sistema.
+type Role string
+
+// Papéis disponíveis, do mais ao menos privilegiado.
+const (
+	RoleAdmin   Role = "admin"
+	RoleManager Role = "manager"
+	RoleMember  Role = "member"
+	RoleViewer  Role = "viewer"
+)
+
+// Permission identifica uma ação protegida da camada de serviço.
+type Permission string
+
+const (
+	PermViewTasks        Permission = "task:view"
+	PermCreateTask       Permission = "task:create"
+	PermEditAnyTask      Permission = "task:edit:any"
+	PermEditAssignedTask Permission = "task:edit:assigned"
+	PermAssignTask       Permission = "task:assign"
+	PermDeleteTask       Permission = "task:delete"
+
+	PermManageTeams     Permission = "team:manage"
+	PermManageWorkflows Permission = "workflow:manage"
+
+	PermComment          Permission = "comment:create"
+	PermModerateComments Permission = "comment:moderate"
+
+	PermViewUsers   Permission = "user:view"
+	PermCreateUser  Permission = "user:create"
+	PermDeleteUser  Permission = "user:delete"
+	PermManageRoles Permission = "user:roles"
+)
+
+// permissionMatrix define o que cada papel pode fazer.
+var permissionMatrix = map[Role][]Permission{
+	RoleAdmin: {
+		PermViewTasks, PermCreateTask, PermEditAnyTask, PermEditAssignedTask, PermAssignTask, PermDeleteTask,
+		PermManageTeams, PermManageWorkflows,
+		PermComment, PermModerateComments,
+		PermViewUsers, PermCreateUser, PermDeleteUser, PermManageRoles,
+	},
+	RoleManager: {
+		PermViewTasks, PermCreateTask, PermEditAnyTask, PermEditAssignedTask, PermAssignTask, PermDeleteTask,
+		PermManageTeams,
+		PermComment, PermModerateComments,
+		PermViewUsers,
+	},
+	RoleMember: {
+		PermViewTasks, PermCreateTask, PermEditAssignedTask,
+		PermComment,
+		PermViewUsers,
+	},
+	RoleViewer: {
+		PermViewTasks,
+		PermViewUsers,
+	},
+}
+
+// ParseRole valida o nome de um papel.
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if _, ok := permissionMatrix[role]; !ok {
+		return "", fmt.Errorf("%w: papel desconhecido %q", ErrValidation, name)
+	}
+
+	return role, nil
+}
+
+// Can informa se o papel concede a permissão.
+func (r Role) Can(perm Permission) bool {
+	for _, p := range permissionMatrix[r] {
+		if p == perm {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AsUser retorna uma cópia do serviço que age em nome do usuário informado e
+// aplica suas permissões. Sem AsUser o serviço age como o próprio sistema.
+func (service teamTaskService) AsUser(actor User) Service {
+	service.actor = &actor
+	return &service
+}
+
+// authorize verifica se o usuário atual tem a permissão informada.
+func (service teamTaskService) authorize(perm Permission) error {
+	if service.actor == nil {
+		return nil
+	}
+
+	if !service.actor.Role.Can(perm) {
+		return fmt.Errorf("%w: %s", ErrForbidden, perm)
+	}
+
+	return nil
+}
+
+// authorizeTaskEdit verifica se o usuário atual pode editar a tarefa: papéis com
+// PermEditAnyTask editam qualquer uma, os demais apenas as atribuídas a eles.
+func (service teamTaskService) authorizeTaskEdit(taskID int) error {
+	if service.actor == nil || service.actor.Role.Can(PermEditAnyTask) {
+		return nil
+	}
+
+	if err := service.authorize(PermEditAssignedTask); err != nil {
+		return err
+	}
+
+	tasks, err := service.db.GetTasksForUser(service.actor.ID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if task.ID == taskID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: tarefa não atribuída ao usuário", ErrForbidden)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Priority é um nível de prioridade das tarefas. Code é o valor gravado na tarefa e não
+// muda; Labels traz o nome exibido em cada idioma, ex.: {"pt-BR": "Alta", "en": "High"}.
+// Rank ordena as prioridades, da mais urgente (menor) à menos urgente, e SLA, quando
+// positivo, é o prazo dado às tarefas criadas sem prazo.
+type Priority struct {
+	Code   string            `json:"code" yaml:"code"`
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels"`
+	Rank   int               `json:"rank" yaml:"rank"`
+	SLA    time.Duration     `json:"sla,omitempty" yaml:"sla"`
+}
+
+// MarshalJSON escreve o SLA como duração legível, ex.: "24h0m0s".
+func (p Priority) MarshalJSON() ([]byte, error) {
+	type view Priority
+	var sla string
+	if p.SLA > 0 {
+		sla = p.SLA.String()
+	}
+	return json.Marshal(struct {
+		view
+		SLA string `json:"sla,omitempty"`
+	}{view(p), sla})
+}
+
+// Priorities é o conjunto de prioridades aceitas pela instalação.
+type Priorities []Priority
+
+// DefaultPriorities retorna as prioridades usadas quando nenhuma é configurada.
+func DefaultPriorities() Priorities {
+	return Priorities{
+		{Code: "High", Labels: map[string]string{"pt-BR": "Alta", "en": "High"}, Rank: 1},
+		{Code: "Medium", Labels: map[string]string{"pt-BR": "Média", "en": "Medium"}, Rank: 2},
+		{Code: "Low", Labels: map[string]string{"pt-BR": "Baixa", "en": "Low"}, Rank: 3},
+	}
+}
+
+// Validate verifica se há ao menos uma prioridade, se os códigos são únicos e se nenhum
+// nome exibido coincide com o código ou o nome de outra prioridade.
+func (ps Priorities) Validate() error {
+	var errs []error
+	if len(ps) == 0 {
+		errs = append(errs, errors.New("informe ao menos uma prioridade"))
+	}
+
+	owner := make(map[string]string)
+	claim := func(name, code string) {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if other, ok := owner[key]; ok && other != code {
+			errs = append(errs, fmt.Errorf("%q identifica mais de uma prioridade (%s e %s)", name, other, code))
+		}
+		owner[key] = code
+	}
+
+	codes := make(map[string]bool, len(ps))
+	for _, p := range ps {
+		code := strings.TrimSpace(p.Code)
+		switch {
+		case code == "":
+			errs = append(errs, errors.New("prioridade sem código"))
+			continue
+		case codes[strings.ToLower(code)]:
+			errs = append(errs, fmt.Errorf("código de prioridade repetido %q", p.Code))
+			continue
+		}
+		codes[strings.ToLower(code)] = true
+		claim(code, code)
+		if p.SLA < 0 {
+			errs = append(errs, fmt.Errorf("SLA negativo na prioridade %q", p.Code))
+		}
+	}
+	for _, p := range ps {
+		for _, label := range p.Labels {
+			if strings.TrimSpace(label) != "" {
+				claim(label, strings.TrimSpace(p.Code))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Lookup procura uma prioridade pelo código ou por um dos nomes exibidos, sem
+// diferenciar maiúsculas de minúsculas.
+func (ps Priorities) Lookup(name string) (Priority, bool) {
+	name = strings.TrimSpace(name)
+	for _, p := range ps {
+		if strings.EqualFold(p.Code, name) {
+			return p, true
+		}
+	}
+	for _, p := range ps {
+		for _, label := range p.Labels {
+			if strings.EqualFold(label, name) {
+				return p, true
+			}
+		}
+	}
+
+	return Priority{}, false
+}
+
+// rank retorna a posição da prioridade na ordenação. Tarefas sem prioridade ou com um
+// código fora do conjunto ficam depois de todas as outras.
+func (ps Priorities) rank(code string) (int, bool) {
+	for _, p := range ps {
+		if p.Code == code {
+			return p.Rank, true
+		}
+	}
+
+	return 0, false
+}
+
+// SortTasksByPriority ordena as tarefas da mais à menos urgente segundo ps, mantendo a
+// ordem original entre tarefas de mesma prioridade.
+func SortTasksByPriority(tasks []Task, ps Priorities) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		ri, oki := ps.rank(tasks[i].Priority)
+		rj, okj := ps.rank(tasks[j].Priority)
+		if oki != okj {
+			return oki
+		}
+		return ri < rj
+	})
+}
+
+// WithPriorities define as prioridades aceitas. O conjunto deve ter sido validado.
+func WithPriorities(priorities Priorities) Option {
+	return func(s *teamTaskService) {
+		s.priorities = priorities
+	}
+}
+
+// ListPriorities retorna as prioridades aceitas, da mais à menos urgente.
+func (service teamTaskService) ListPriorities() Priorities {
+	priorities := append(Priorities(nil), service.priorities...)
+	sort.SliceStable(priorities, func(i, j int) bool { return priorities[i].Rank < priorities[j].Rank })
+	return priorities
+}
+
+// resolvePriority valida a prioridade informada em uma escrita e retorna o código a
+// gravar. Vazio continua vazio: a tarefa fica sem prioridade.
+func (service teamTaskService) resolvePriority(name string) (Priority, error) {
+	if strings.TrimSpace(name) == "" {
+		return Priority{}, nil
+	}
+
+	priority, ok := service.priorities.Lookup(name)
+	if !ok {
+		return Priority{}, fmt.Errorf("%w: prioridade inválida %q", ErrValidation, name)
+	}
+
+	return priority, nil
+}
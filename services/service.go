@@ -1,24 +1,154 @@
 package service
 
-import "go.uber.org/zap"
+import (
+	"crypto/rand"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+)
 
 // TaskInput representa a entrada para o serviço de criação de tarefa.
 type Task struct {
-	ID            int    `json:"id"`
-	Title         string `json:"title"`
-	Description   string `json:"description"`
-	Priority      string `json:"priority"`
-	Status        string `json:"status"`
-	AssignedUsers []int  `json:"assignedUsers"`
+	ID            int        `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	Priority      string     `json:"priority"` // código de uma das prioridades configuradas, ex.: High
+	Status        string     `json:"status"`
+	AssignedUsers []int      `json:"assignedUsers"`
+	TeamID        int        `json:"teamId,omitempty"` // zero quando a tarefa não pertence a uma equipe
+	StartDate     *time.Time `json:"startDate,omitempty"`
+	DueDate       *time.Time `json:"dueDate,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"` // preenchido pelo serviço quando a tarefa é concluída
+	Resolution    string     `json:"resolution,omitempty"`  // como a tarefa foi concluída; apagada quando ela é reaberta
+	Type          string     `json:"type,omitempty"`        // tipo livre, ex.: bug ou feature; escolhe o fluxo da tarefa
+	Labels        []Label    `json:"labels,omitempty"`      // preenchido pelo serviço nas leituras; alterado pelos endpoints de etiquetas
+	ParentID      int        `json:"parentId,omitempty"`    // zero nas tarefas de primeiro nível
+	Progress      *int       `json:"progress,omitempty"`    // percentual concluído das subtarefas; preenchido na consulta de uma tarefa com subtarefas
+	Blocked       bool       `json:"blocked"`               // preenchido pelo serviço: alguma tarefa que bloqueia esta ainda está aberta
+	BlockedBy     []int      `json:"blockedBy,omitempty"`   // IDs das tarefas abertas que bloqueiam esta
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`   // preenchido apenas nas tarefas da lixeira
+	Version       int        `json:"version"`               // incrementada a cada alteração; é a ETag da tarefa
 }
 
 // Definição da estrutura de dados do usuário
 type User struct {
 	ID       int
 	Name     string
-	Role     string
+	Role     Role
 	Email    string
 	Password string
+	// DeletedAt é preenchido apenas nos usuários da lixeira
+	DeletedAt *time.Time `json:",omitempty"`
+}
+
+// TeamRole é o papel de um usuário dentro de uma equipe.
+type TeamRole string
+
+const (
+	TeamRoleLead   TeamRole = "lead"
+	TeamRoleMember TeamRole = "member"
+)
+
+// Team representa uma equipe (tabela Equipe).
+type Team struct {
+	ID       int          `json:"id"`
+	Name     string       `json:"name"`
+	Archived bool         `json:"archived"`
+	Members  []TeamMember `json:"members,omitempty"`
+}
+
+// TeamMember associa um usuário a uma equipe com um papel.
+type TeamMember struct {
+	UserID int      `json:"userId"`
+	Role   TeamRole `json:"role"`
+}
+
+// Label é uma etiqueta de uma equipe, aplicada às tarefas da equipe para classificá-las.
+type Label struct {
+	ID     int    `json:"id"`
+	TeamID int    `json:"teamId"`
+	Name   string `json:"name"`
+	Color  string `json:"color"` // #rrggbb
+}
+
+// TaskLink registra que a tarefa BlockerID bloqueia a tarefa BlockedID: BlockedID só
+// deve começar depois que BlockerID for concluída.
+type TaskLink struct {
+	BlockerID int `json:"blockerId"`
+	BlockedID int `json:"blockedId"`
+}
+
+// Attachment é um arquivo anexado a uma tarefa. O conteúdo fica no BlobStore, sob
+// StorageKey.
+type Attachment struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"taskId"`
+	UploaderID  int       `json:"uploaderId,omitempty"` // zero se o usuário foi excluído
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TaskEventKind é o tipo de uma mudança registrada no histórico da tarefa.
+type TaskEventKind string
+
+// TaskEvent é uma mudança registrada no histórico de uma tarefa. Os eventos nunca são
+// alterados e continuam gravados depois que a tarefa ou o autor são excluídos.
+type TaskEvent struct {
+	ID        int           `json:"id"`
+	TaskID    int           `json:"taskId"`
+	ActorID   int           `json:"actorId,omitempty"` // zero para mudanças feitas pelo próprio sistema
+	Kind      TaskEventKind `json:"kind"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// FieldChange é o valor de um campo antes e depois de uma mudança. Valores vazios ficam
+// de fora: um campo criado só tem After e um apagado só tem Before.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Comment é um comentário em uma tarefa (tabela Comentario). Respostas apontam
+// para o comentário raiz da conversa em ParentID.
+type Comment struct {
+	ID        int        `json:"id"`
+	TaskID    int        `json:"taskId"`
+	AuthorID  int        `json:"authorId"`
+	ParentID  int        `json:"parentId,omitempty"`
+	Text      string     `json:"text"`
+	Mentions  []int      `json:"mentions"`
+	CreatedAt time.Time  `json:"createdAt"`
+	EditedAt  *time.Time `json:"editedAt,omitempty"`
+}
+
+// CommentPage é uma página da listagem de comentários de uma tarefa.
+type CommentPage struct {
+	Comments []Comment `json:"comments"`
+	Total    int       `json:"total"`
+	Limit    int       `json:"limit"`
+	Offset   int       `json:"offset"`
+}
+
+// NotificationType é a categoria de uma notificação.
+type NotificationType string
+
+// Notification é uma notificação da caixa de entrada de um usuário (tabela Notificacao).
+type Notification struct {
+	ID          int              `json:"id"`
+	RecipientID int              `json:"recipientId"`
+	Type        NotificationType `json:"type"`
+	Content     string           `json:"content"`
+	TaskID      int              `json:"taskId,omitempty"`
+	Read        bool             `json:"read"`
+	CreatedAt   time.Time        `json:"createdAt"`
 }
 
 type Service interface {
@@ -26,39 +156,294 @@ type Service interface {
 	GetVisibleTasksForUser(userID int) ([]Task, error)
 	FilterTasksByStatusAndPriority(status, priority string) ([]Task, error)
 	AssignMemberToTask(taskID, memberID int) error
+	UnassignMemberFromTask(taskID, memberID int) error
 	DeleteTask(taskID int) error
+	// ListTrashedTasks retorna as tarefas da lixeira, das excluídas há mais tempo às mais
+	// recentes.
+	ListTrashedTasks() ([]Task, error)
+	RestoreTask(taskID int) error
 	GetTaskByID(taskID int) (Task, error)
-	EditTask(taskID int, updatedTask Task) error
+	// EditTask substitui os campos informados da tarefa; os omitidos continuam os mesmos.
+	// Com AssignedUsers, os responsáveis passam a ser exatamente os da lista (vazia retira
+	// todos). Retorna a tarefa atualizada.
+	EditTask(taskID int, updatedTask Task) (Task, error)
+	// PatchTask aplica um JSON Merge Patch ou JSON Patch, lido por MergePatch ou JSONPatch,
+	// e retorna a tarefa atualizada.
+	PatchTask(taskID int, patch TaskPatch) (Task, error)
+	TransitionTask(taskID int, transition TaskTransition) (Task, error)
+	GetTaskWorkflow(taskID int) (Workflow, error)
 	GetAllTasks() ([]Task, error)
+	GetOverdueTasks() ([]Task, error)
+	GetUpcomingTasksForUser(userID, days int) ([]Task, error)
+	ListPriorities() Priorities
+	FilterTasks(filter TaskFilter) ([]Task, error)
+	SetTaskParent(taskID, parentID int) (Task, error)
+	GetTaskChildren(taskID int) ([]Task, error)
+	GetTaskTree(taskID int) (TaskTree, error)
+	AddTaskLink(blockerID, blockedID int) error
+	RemoveTaskLink(blockerID, blockedID int) error
+	GetTaskLinks(taskID int) ([]TaskLink, error)
+
+	// GetTaskHistory retorna os eventos da tarefa em ordem cronológica, inclusive os de
+	// uma tarefa já excluída.
+	GetTaskHistory(taskID int) ([]TaskEvent, error)
+
+	AddAttachment(taskID int, upload AttachmentUpload) (Attachment, error)
+	ListAttachments(taskID int) ([]Attachment, error)
+	// OpenAttachment retorna o anexo e seu conteúdo, que deve ser fechado por quem chama.
+	OpenAttachment(taskID, attachmentID int) (Attachment, io.ReadCloser, error)
+	DeleteAttachment(taskID, attachmentID int) error
 
 	RegisterNewUser(user User) (int, error)
 	DeleteUser(userID int) error
+	ListTrashedUsers() ([]User, error)
+	RestoreUser(userID int) error
 	GetUserByID(userID int) (User, error)
+	VerifyCredentials(email, password string) (User, error)
+	ChangeUserRole(userID int, role Role) error
+
+	// PurgeTrash apaga de vez as tarefas e os usuários que estão na lixeira desde antes de
+	// before e retorna quantos de cada foram apagados.
+	PurgeTrash(before time.Time) (tasks, users int, err error)
+
+	AsUser(actor User) Service
+	// IfMatch retorna uma cópia do serviço que só edita ou exclui uma tarefa se ela ainda
+	// estiver na versão informada.
+	IfMatch(version int) Service
+
+	CreateTeam(team Team) (int, error)
+	GetTeam(teamID int) (Team, error)
+	GetAllTeams() ([]Team, error)
+	RenameTeam(teamID int, name string) error
+	SetTeamArchived(teamID int, archived bool) error
+	AddTeamMember(teamID, userID int, role TeamRole) error
+	RemoveTeamMember(teamID, userID int) error
+	GetTasksForTeam(teamID int) ([]Task, error)
+
+	CreateLabel(label Label) (int, error)
+	ListTeamLabels(teamID int) ([]Label, error)
+	UpdateLabel(labelID int, label Label) error
+	DeleteLabel(labelID int) error
+	AddTaskLabel(taskID, labelID int) error
+	RemoveTaskLabel(taskID, labelID int) error
+
+	CreateComment(taskID int, comment Comment) (Comment, error)
+	EditComment(taskID, commentID int, text string) (Comment, error)
+	DeleteComment(taskID, commentID int) error
+	ListComments(taskID, limit, offset int) (CommentPage, error)
+
+	ListNotifications(unreadOnly bool) ([]Notification, error)
+	MarkNotificationRead(notificationID int) error
+	MarkAllNotificationsRead() error
+	GetNotificationPreferences() (map[NotificationType]bool, error)
+	UpdateNotificationPreferences(prefs map[NotificationType]bool) error
+
+	ListWorkflows() ([]Workflow, error)
+	GetWorkflow(workflowID int) (Workflow, error)
+	CreateWorkflow(workflow Workflow) (int, error)
+	UpdateWorkflow(workflowID int, workflow Workflow, statusMapping map[string]string) error
+	DeleteWorkflow(workflowID int) error
+	ListWorkflowAssignments() ([]WorkflowAssignment, error)
+	AssignWorkflow(assignment WorkflowAssignment, statusMapping map[string]string) error
+	UnassignWorkflow(teamID int, taskType string, statusMapping map[string]string) error
+
+	Login(email, password string) (TokenPair, error)
+	RefreshSession(refreshToken string) (TokenPair, error)
+	Logout(sessionID string) error
+	Authenticate(accessToken string) (User, string, error)
 }
 
 type Repository interface {
-	CreateTask(title, description, status, priority string, assignedUsers []int) (int, error)
+	// WithinTransaction executa fn com um repositório cujas operações são atômicas:
+	// tudo é confirmado se fn retornar nil e desfeito caso contrário.
+	WithinTransaction(fn func(repo Repository) error) error
+
+	CreateTask(task Task) (int, error)
 	AssignTaskToUser(taskID int, userID int) error
 	GetTaskByID(taskID int) (Task, error)
 	GetTasksForUser(userID int) ([]Task, error)
+	GetTaskAssignees(taskID int) ([]int, error)
+	// GetAssigneesForTasks retorna os responsáveis por cada uma das tarefas informadas, em
+	// ordem de ID; tarefas sem responsáveis ficam fora do mapa.
+	GetAssigneesForTasks(taskIDs []int) (map[int][]int, error)
+	// UnassignTaskFromUser retira o usuário da tarefa; ErrNotFound se ele não estava atribuído.
+	UnassignTaskFromUser(taskID, userID int) error
 	GetAllTasks() ([]Task, error)
+	GetOverdueTasks(now time.Time) ([]Task, error)
+	// GetTaskChildren retorna as subtarefas diretas da tarefa, em ordem de ID.
+	GetTaskChildren(parentID int) ([]Task, error)
+	// DeleteTask apaga de vez a tarefa, suas dependências e os registros dos anexos; as
+	// subtarefas passam a ser de primeiro nível.
 	DeleteTask(taskID int) error
+	// UpdateTask grava a tarefa se ela ainda estiver em updatedTask.Version e incrementa a
+	// versão; se outra alteração chegou antes, retorna um *VersionError com a versão atual.
 	UpdateTask(taskID int, updatedTask Task) error
+	// TrashTask move a tarefa para a lixeira: ela some das demais consultas, mas seus
+	// dados continuam gravados.
+	TrashTask(taskID int, at time.Time) error
+	// RestoreTask tira a tarefa da lixeira; ErrNotFound se ela não estiver lá.
+	RestoreTask(taskID int) error
+	// ListTrashedTasks retorna as tarefas da lixeira em ordem de exclusão.
+	ListTrashedTasks() ([]Task, error)
 
 	GetUserByEmail(email string) (User, error)
 	GetUserByID(id int) (User, error)
 	AddUser(user User) (int, error)
+	UpdateUserPassword(userID int, passwordHash string) error
+	UpdateUserRole(userID int, role Role) error
+	// CountUsers conta todos os usuários, inclusive os da lixeira.
+	CountUsers() (int, error)
+	// RemoveUser apaga de vez o usuário, suas atribuições e participações em equipes.
 	RemoveUser(userID int) error
+	// TrashUser move o usuário para a lixeira: ele some das consultas, das atribuições e
+	// das equipes, que voltam com ele ao ser restaurado.
+	TrashUser(userID int, at time.Time) error
+	// RestoreUser tira o usuário da lixeira; ErrNotFound se ele não estiver lá.
+	RestoreUser(userID int) error
+	// ListTrashedUsers retorna os usuários da lixeira em ordem de exclusão.
+	ListTrashedUsers() ([]User, error)
+
+	CreateTeam(team Team) (int, error)
+	GetTeamByID(teamID int) (Team, error)
+	GetAllTeams() ([]Team, error)
+	GetTeamsForUser(userID int) ([]Team, error)
+	UpdateTeam(team Team) error
+	AddTeamMember(teamID, userID int, role TeamRole) error
+	UpdateTeamMemberRole(teamID, userID int, role TeamRole) error
+	RemoveTeamMember(teamID, userID int) error
+	GetTeamMembers(teamID int) ([]TeamMember, error)
+	GetTasksForTeam(teamID int) ([]Task, error)
+
+	CreateLabel(label Label) (int, error)
+	GetLabel(labelID int) (Label, error)
+	ListLabels(teamID int) ([]Label, error)
+	UpdateLabel(label Label) error
+	// DeleteLabel exclui a etiqueta e a retira das tarefas.
+	DeleteLabel(labelID int) error
+	AddTaskLabel(taskID, labelID int) error
+	RemoveTaskLabel(taskID, labelID int) error
+	// GetLabelsForTasks retorna as etiquetas de cada uma das tarefas informadas, em ordem
+	// de nome; tarefas sem etiquetas ficam fora do mapa.
+	GetLabelsForTasks(taskIDs []int) (map[int][]Label, error)
+
+	AddTaskLink(link TaskLink) error
+	RemoveTaskLink(link TaskLink) error
+	// GetTaskLinks retorna as dependências em que a tarefa bloqueia ou é bloqueada, em
+	// ordem de BlockerID e BlockedID, inclusive as com tarefas da lixeira.
+	GetTaskLinks(taskID int) ([]TaskLink, error)
+	// GetOpenBlockers retorna, para cada tarefa informada, os IDs das tarefas não
+	// concluídas que a bloqueiam, em ordem; tarefas sem bloqueios ficam fora do mapa.
+	GetOpenBlockers(taskIDs []int) (map[int][]int, error)
+
+	CreateTaskEvent(event TaskEvent) (int, error)
+	// ListTaskEvents retorna os eventos da tarefa em ordem de ID.
+	ListTaskEvents(taskID int) ([]TaskEvent, error)
+
+	CreateAttachment(attachment Attachment) (int, error)
+	GetAttachment(attachmentID int) (Attachment, error)
+	// ListAttachments retorna os anexos da tarefa em ordem de ID.
+	ListAttachments(taskID int) ([]Attachment, error)
+	DeleteAttachment(attachmentID int) error
+
+	CreateComment(comment Comment) (int, error)
+	GetComment(commentID int) (Comment, error)
+	UpdateComment(comment Comment) error
+	DeleteComment(commentID int) error
+	ListComments(taskID, limit, offset int) ([]Comment, int, error)
+
+	CreateNotification(notification Notification) (int, error)
+	GetNotification(notificationID int) (Notification, error)
+	ListNotifications(userID int, unreadOnly bool) ([]Notification, error)
+	MarkNotificationRead(notificationID int) error
+	MarkAllNotificationsRead(userID int) error
+	GetNotificationPreferences(userID int) (map[NotificationType]bool, error)
+	SetNotificationPreference(userID int, notificationType NotificationType, enabled bool) error
+
+	CreateWorkflow(workflow Workflow) (int, error)
+	GetWorkflow(workflowID int) (Workflow, error)
+	ListWorkflows() ([]Workflow, error)
+	UpdateWorkflow(workflow Workflow) error
+	DeleteWorkflow(workflowID int) error
+	ListWorkflowAssignments() ([]WorkflowAssignment, error)
+	// SetWorkflowAssignment grava a atribuição, substituindo a que existir para a mesma
+	// equipe e tipo de tarefa.
+	SetWorkflowAssignment(assignment WorkflowAssignment) error
+	RemoveWorkflowAssignment(teamID int, taskType string) error
+
+	CreateSession(session Session) error
+	GetSession(sessionID string) (Session, error)
+	UpdateSessionRefresh(sessionID, refreshHash string, expiresAt time.Time) error
+	RevokeSession(sessionID string, revokedAt time.Time) error
 }
 
 type teamTaskService struct {
-	db  Repository
-	log *zap.Logger
+	db     Repository
+	log    *zap.Logger
+	hasher PasswordHasher
+	actor  *User // usuário em nome de quem o serviço age; nil para chamadas do sistema
+
+	ifMatch int // versão exigida nas edições e exclusões de tarefas; zero para qualquer uma
+
+	workflow   Workflow // fluxo das tarefas sem fluxo atribuído
+	priorities Priorities
+	subtasks   SubtaskPolicy
+	links      LinkPolicy
+
+	blobs       BlobStore // nil quando os anexos não estão configurados
+	attachments AttachmentPolicy
+
+	tokenSecret []byte
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
 }
 
-func NewService(db Repository, logger *zap.Logger) Service {
-	return &teamTaskService{
-		db:  db,
-		log: logger,
+// Option configura parâmetros opcionais do serviço.
+type Option func(*teamTaskService)
+
+// WithHashParams define o algoritmo e os parâmetros usados no hash de senhas.
+func WithHashParams(params HashParams) Option {
+	return func(s *teamTaskService) {
+		s.hasher = NewPasswordHasher(params)
 	}
 }
+
+func NewService(db Repository, logger *zap.Logger, opts ...Option) Service {
+	svc := &teamTaskService{
+		db:     db,
+		log:    logger,
+		hasher: NewPasswordHasher(DefaultHashParams()),
+
+		workflow:   DefaultWorkflow(),
+		priorities: DefaultPriorities(),
+		subtasks:   DefaultSubtaskPolicy(),
+		links:      DefaultLinkPolicy(),
+
+		attachments: DefaultAttachmentPolicy(),
+
+		accessTTL:  15 * time.Minute,
+		refreshTTL: 30 * 24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	if len(svc.tokenSecret) == 0 {
+		// Sem chave configurada os tokens deixam de valer quando o processo reinicia
+		logger.Warn("Nenhuma chave de assinatura de tokens configurada, usando uma chave aleatória")
+		svc.tokenSecret = make([]byte, 32)
+		_, _ = rand.Read(svc.tokenSecret)
+	}
+
+	return svc
+}
+
+// inTransaction executa fn com uma cópia do serviço cujo repositório participa de uma
+// única transação. Operações com várias escritas usam esse helper para que uma falha no
+// meio do caminho não deixe dados pela metade.
+func (service teamTaskService) inTransaction(fn func(tx teamTaskService) error) error {
+	return service.db.WithinTransaction(func(repo Repository) error {
+		tx := service
+		tx.db = repo
+		return fn(tx)
+	})
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SubtaskPolicy controla a hierarquia de tarefas.
+type SubtaskPolicy struct {
+	// MaxDepth é o número de níveis permitidos, contando a tarefa de primeiro nível;
+	// 1 desliga as subtarefas.
+	MaxDepth int `yaml:"maxDepth"`
+	// RequireClosedChildren impede concluir uma tarefa enquanto ela tiver subtarefas abertas.
+	RequireClosedChildren bool `yaml:"requireClosedChildren"`
+}
+
+// DefaultSubtaskPolicy retorna a política usada quando nenhuma é configurada.
+func DefaultSubtaskPolicy() SubtaskPolicy {
+	return SubtaskPolicy{MaxDepth: 5, RequireClosedChildren: true}
+}
+
+// Validate confere se a política é utilizável.
+func (p SubtaskPolicy) Validate() error {
+	if p.MaxDepth < 1 {
+		return errors.New("maxDepth precisa ser ao menos 1")
+	}
+	return nil
+}
+
+// WithSubtaskPolicy define a política de subtarefas. A política deve ter sido validada.
+func WithSubtaskPolicy(policy SubtaskPolicy) Option {
+	return func(s *teamTaskService) {
+		s.subtasks = policy
+	}
+}
+
+// TaskTree é uma tarefa com suas subtarefas, em ordem de ID.
+type TaskTree struct {
+	Task
+	Children []TaskTree `json:"children"`
+}
+
+// SetTaskParent move a tarefa para baixo de parentID, ou para o primeiro nível com zero,
+// e retorna a tarefa com a nova versão. Suas subtarefas vão junto, então a profundidade
+// da subárvore inteira precisa caber no limite configurado.
+func (service teamTaskService) SetTaskParent(taskID, parentID int) (Task, error) {
+	var task Task
+	err := service.inTransaction(func(tx teamTaskService) error {
+		var err error
+		task, err = tx.db.GetTaskByID(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("tarefa não encontrada"))
+		}
+		if err := tx.authorizeTaskEdit(taskID); err != nil {
+			return err
+		}
+		if err := tx.checkVersion(task); err != nil {
+			return err
+		}
+		if task.ParentID == parentID {
+			return nil
+		}
+
+		if parentID != 0 {
+			if parentID == taskID {
+				return fmt.Errorf("%w: a tarefa não pode ser subtarefa de si mesma", ErrValidation)
+			}
+			ancestors, err := tx.taskAncestors(parentID)
+			if err != nil {
+				return err
+			}
+			for _, ancestor := range ancestors {
+				if ancestor.ID == taskID {
+					return fmt.Errorf("%w: a tarefa #%d é subtarefa de #%d; a hierarquia não pode ter ciclos", ErrValidation, parentID, taskID)
+				}
+			}
+
+			height, err := tx.subtreeHeight(taskID)
+			if err != nil {
+				return err
+			}
+			if err := tx.checkParent(ancestors, height, task); err != nil {
+				return err
+			}
+		}
+
+		before := task
+		task.ParentID = parentID
+		task.UpdatedAt = time.Now().UTC()
+		if err := tx.db.UpdateTask(taskID, task); err != nil {
+			return errors.Join(err, errors.New("erro ao mover a tarefa"))
+		}
+		task.Version++
+
+		return tx.recordUpdate(before, task)
+	})
+	if err != nil {
+		return Task{}, err
+	}
+
+	return task, nil
+}
+
+// GetTaskChildren retorna as subtarefas diretas da tarefa, em ordem de ID.
+func (service teamTaskService) GetTaskChildren(taskID int) ([]Task, error) {
+	tree, err := service.GetTaskTree(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]Task, len(tree.Children))
+	for i, child := range tree.Children {
+		children[i] = child.Task
+	}
+
+	return children, nil
+}
+
+// GetTaskTree retorna a tarefa com todas as suas subtarefas, em qualquer nível.
+func (service teamTaskService) GetTaskTree(taskID int) (TaskTree, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return TaskTree{}, err
+	}
+
+	task, err := service.db.GetTaskByID(taskID)
+	if err != nil {
+		return TaskTree{}, errors.Join(err, errors.New("tarefa não encontrada"))
+	}
+
+	tree, err := service.loadTree(task, map[int]bool{})
+	if err != nil {
+		return TaskTree{}, err
+	}
+	if err := service.detailTree(&tree); err != nil {
+		return TaskTree{}, err
+	}
+
+	return tree, nil
+}
+
+// loadTree carrega as subtarefas de task recursivamente e calcula o progresso de cada
+// nível. seen protege contra ciclos gravados fora do serviço.
+func (service teamTaskService) loadTree(task Task, seen map[int]bool) (TaskTree, error) {
+	seen[task.ID] = true
+	tree := TaskTree{Task: task, Children: []TaskTree{}}
+
+	children, err := service.db.GetTaskChildren(task.ID)
+	if err != nil {
+		return TaskTree{}, errors.Join(err, errors.New("erro ao obter as subtarefas"))
+	}
+	for _, child := range children {
+		if seen[child.ID] {
+			continue
+		}
+		subtree, err := service.loadTree(child, seen)
+		if err != nil {
+			return TaskTree{}, err
+		}
+		tree.Children = append(tree.Children, subtree)
+	}
+	tree.Progress = treeProgress(tree)
+
+	return tree, nil
+}
+
+// detailTree preenche as etiquetas e os bloqueios de todas as tarefas da árvore de uma
+// só vez.
+func (service teamTaskService) detailTree(tree *TaskTree) error {
+	var nodes []*TaskTree
+	var collect func(node *TaskTree)
+	collect = func(node *TaskTree) {
+		nodes = append(nodes, node)
+		for i := range node.Children {
+			collect(&node.Children[i])
+		}
+	}
+	collect(tree)
+
+	tasks := make([]Task, len(nodes))
+	for i, node := range nodes {
+		tasks[i] = node.Task
+	}
+	tasks, err := service.withDetails(tasks)
+	if err != nil {
+		return err
+	}
+	for i, node := range nodes {
+		node.Labels, node.Blocked, node.BlockedBy = tasks[i].Labels, tasks[i].Blocked, tasks[i].BlockedBy
+	}
+
+	return nil
+}
+
+// treeProgress é a média do progresso das subtarefas diretas: uma subtarefa concluída
+// vale 100, uma aberta sem subtarefas vale 0 e uma aberta com subtarefas vale o próprio
+// progresso. Tarefas sem subtarefas não têm progresso.
+func treeProgress(tree TaskTree) *int {
+	if len(tree.Children) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, child := range tree.Children {
+		switch {
+		case child.CompletedAt != nil:
+			total += 100
+		case child.Progress != nil:
+			total += *child.Progress
+		}
+	}
+	progress := total / len(tree.Children)
+
+	return &progress
+}
+
+// validateNewSubtask confere a tarefa pai de uma tarefa que está sendo criada.
+func (service teamTaskService) validateNewSubtask(task Task) error {
+	if task.ParentID == 0 {
+		return nil
+	}
+
+	ancestors, err := service.taskAncestors(task.ParentID)
+	if err != nil {
+		return err
+	}
+
+	return service.checkParent(ancestors, 1, task)
+}
+
+// checkParent confere se uma subárvore com height níveis, cuja raiz é task, cabe abaixo
+// da tarefa ancestors[0]. Com RequireClosedChildren uma tarefa aberta não entra abaixo
+// de uma tarefa concluída.
+func (service teamTaskService) checkParent(ancestors []Task, height int, task Task) error {
+	if depth := len(ancestors) + height; depth > service.subtasks.MaxDepth {
+		return fmt.Errorf("%w: a hierarquia passaria de %d níveis", ErrValidation, service.subtasks.MaxDepth)
+	}
+
+	parent := ancestors[0]
+	if service.subtasks.RequireClosedChildren && parent.CompletedAt != nil && task.CompletedAt == nil {
+		return fmt.Errorf("%w: a tarefa pai #%d já está concluída", ErrConflict, parent.ID)
+	}
+
+	return nil
+}
+
+// taskAncestors retorna a tarefa taskID seguida de suas tarefas pai, até o primeiro nível.
+func (service teamTaskService) taskAncestors(taskID int) ([]Task, error) {
+	var ancestors []Task
+	seen := map[int]bool{}
+	for id := taskID; id != 0 && !seen[id]; {
+		task, err := service.db.GetTaskByID(id)
+		if err != nil {
+			if id == taskID {
+				return nil, errors.Join(err, errors.New("tarefa pai não encontrada"))
+			}
+			return nil, errors.Join(err, errors.New("erro ao obter as tarefas pai"))
+		}
+		seen[id] = true
+		ancestors = append(ancestors, task)
+		id = task.ParentID
+	}
+
+	return ancestors, nil
+}
+
+// subtreeHeight retorna quantos níveis a tarefa ocupa, contando ela mesma.
+func (service teamTaskService) subtreeHeight(taskID int) (int, error) {
+	task, err := service.db.GetTaskByID(taskID)
+	if err != nil {
+		return 0, errors.Join(err, errors.New("tarefa não encontrada"))
+	}
+	tree, err := service.loadTree(task, map[int]bool{})
+	if err != nil {
+		return 0, err
+	}
+
+	var height func(node TaskTree) int
+	height = func(node TaskTree) int {
+		h := 0
+		for _, child := range node.Children {
+			h = max(h, height(child))
+		}
+		return h + 1
+	}
+
+	return height(tree), nil
+}
+
+// checkOpenChildren recusa concluir uma tarefa com subtarefas abertas, quando a política
+// exige. Só as subtarefas diretas são consultadas: uma subtarefa concluída já passou por
+// esta mesma verificação.
+func (service teamTaskService) checkOpenChildren(current Task, completed bool) error {
+	if !service.subtasks.RequireClosedChildren || !completed || current.CompletedAt != nil {
+		return nil
+	}
+
+	children, err := service.db.GetTaskChildren(current.ID)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao obter as subtarefas"))
+	}
+	var open []int
+	for _, child := range children {
+		if child.CompletedAt == nil {
+			open = append(open, child.ID)
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%w: a tarefa tem subtarefas abertas %v", ErrConflict, open)
+	}
+
+	return nil
+}
+
+// withProgress preenche o progresso de uma tarefa com subtarefas.
+func (service teamTaskService) withProgress(task Task) (Task, error) {
+	tree, err := service.loadTree(task, map[int]bool{})
+	if err != nil {
+		return Task{}, err
+	}
+
+	task.Progress = tree.Progress
+	return task, nil
+}
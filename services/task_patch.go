@@ -0,0 +1,371 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation é uma operação de JSON Patch (RFC 6902).
+type PatchOperation struct {
+	Op    string          `json:"op"` // add, remove, replace, move, copy ou test
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`  // origem de move e copy
+	Value json.RawMessage `json:"value,omitempty"` // valor de add, replace e test
+}
+
+// TaskPatch é uma alteração parcial de uma tarefa, aplicada operação por operação sobre a
+// tarefa em JSON. Só os campos em patchableFields podem ser alterados; test pode conferir
+// qualquer campo, inclusive a versão.
+type TaskPatch []PatchOperation
+
+// patchableFields são os campos da tarefa alterados por PatchTask. Os demais são
+// preenchidos pelo serviço ou têm endpoints próprios, como a tarefa pai e as etiquetas.
+var patchableFields = []string{"title", "description", "priority", "status", "type", "resolution", "teamId", "startDate", "dueDate", "assignedUsers"}
+
+// MergePatch lê um documento JSON Merge Patch (RFC 7396): os campos presentes substituem
+// os da tarefa e null apaga o campo.
+func MergePatch(document []byte) (TaskPatch, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(document, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("%w: o merge patch deve ser um objeto JSON", ErrValidation)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	patch := make(TaskPatch, 0, len(names))
+	for _, name := range names {
+		path := "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+		if string(fields[name]) == "null" {
+			patch = append(patch, PatchOperation{Op: "remove", Path: path})
+			continue
+		}
+		patch = append(patch, PatchOperation{Op: "replace", Path: path, Value: fields[name]})
+	}
+
+	return patch, patch.validate()
+}
+
+// JSONPatch lê um documento JSON Patch (RFC 6902), uma lista de operações aplicadas em
+// ordem; se uma falhar, nenhuma é aplicada.
+func JSONPatch(document []byte) (TaskPatch, error) {
+	var patch TaskPatch
+	if err := json.Unmarshal(document, &patch); err != nil || patch == nil {
+		return nil, fmt.Errorf("%w: o JSON patch deve ser uma lista de operações", ErrValidation)
+	}
+
+	return patch, patch.validate()
+}
+
+// validate confere as operações antes de aplicá-las: os campos exigidos por cada uma e
+// se os campos alterados podem ser alterados.
+func (patch TaskPatch) validate() error {
+	for i, op := range patch {
+		var writes []string
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return fmt.Errorf("%w: a operação %d (%s) precisa de value", ErrValidation, i, op.Op)
+			}
+			if op.Op != "test" {
+				writes = []string{op.Path}
+			}
+		case "remove":
+			writes = []string{op.Path}
+		case "move", "copy":
+			if _, err := parsePointer(op.From); err != nil {
+				return fmt.Errorf("%w: operação %d", err, i)
+			}
+			writes = []string{op.Path}
+			if op.Op == "move" {
+				writes = append(writes, op.From)
+			}
+		default:
+			return fmt.Errorf("%w: operação desconhecida %q", ErrValidation, op.Op)
+		}
+
+		if _, err := parsePointer(op.Path); err != nil {
+			return fmt.Errorf("%w: operação %d", err, i)
+		}
+		for _, path := range writes {
+			tokens, _ := parsePointer(path)
+			if len(tokens) == 0 || !slices.Contains(patchableFields, tokens[0]) {
+				return fmt.Errorf("%w: o campo %q não existe ou não pode ser alterado", ErrValidation, path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply aplica as operações sobre a tarefa e retorna a tarefa alterada; os campos fora de
+// patchableFields continuam os da tarefa atual.
+func (patch TaskPatch) apply(current Task) (Task, error) {
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return Task{}, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return Task{}, err
+	}
+	// Campos vazios omitidos no JSON também podem ser substituídos
+	for _, name := range patchableFields {
+		if _, ok := fields[name]; !ok {
+			fields[name] = nil
+		}
+	}
+
+	var document any = fields
+	for i, op := range patch {
+		document, err = applyOperation(document, op)
+		if errors.Is(err, ErrConflict) {
+			return Task{}, err
+		}
+		if err != nil {
+			return Task{}, fmt.Errorf("%w: operação %d (%s %s): %s", ErrValidation, i, op.Op, op.Path, err)
+		}
+	}
+
+	patched := make(map[string]any, len(patchableFields))
+	for _, name := range patchableFields {
+		patched[name] = fields[name]
+	}
+	encoded, err = json.Marshal(patched)
+	if err != nil {
+		return Task{}, err
+	}
+
+	task := current
+	task.Title, task.Description, task.Priority, task.Status, task.Type, task.Resolution = "", "", "", "", "", ""
+	task.TeamID, task.StartDate, task.DueDate, task.AssignedUsers = 0, nil, nil, nil
+	if err := json.Unmarshal(encoded, &task); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return Task{}, fmt.Errorf("%w: valor inválido para %q", ErrValidation, typeErr.Field)
+		}
+		return Task{}, fmt.Errorf("%w: %s", ErrValidation, err)
+	}
+	// Toda tarefa tem um status do fluxo; os demais campos podem ser apagados
+	if strings.TrimSpace(task.Status) == "" {
+		return Task{}, fmt.Errorf("%w: o status não pode ser apagado", ErrValidation)
+	}
+	// A lista resultante é sempre a lista completa de responsáveis; apagada, não sobra nenhum
+	if task.AssignedUsers == nil {
+		task.AssignedUsers = []int{}
+	}
+
+	return task, nil
+}
+
+// applyOperation aplica uma operação ao documento e retorna o documento alterado.
+func applyOperation(document any, op PatchOperation) (any, error) {
+	path, _ := parsePointer(op.Path)
+	switch op.Op {
+	case "add":
+		value, err := decodeValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(document, path, value)
+	case "remove":
+		_, result, err := removeValue(document, path)
+		return result, err
+	case "replace":
+		value, err := decodeValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if _, document, err = removeValue(document, path); err != nil {
+			return nil, err
+		}
+		return addValue(document, path, value)
+	case "move", "copy":
+		from, _ := parsePointer(op.From)
+		value, err := getValue(document, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if _, document, err = removeValue(document, from); err != nil {
+				return nil, err
+			}
+		} else if value, err = decodeValue(mustEncode(value)); err != nil {
+			return nil, err
+		}
+		return addValue(document, path, value)
+	case "test":
+		want, err := decodeValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		got, err := getValue(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(got, want) {
+			return nil, fmt.Errorf("%w: o teste de %s falhou; o valor atual é %s", ErrConflict, op.Path, mustEncode(got))
+		}
+		return document, nil
+	}
+
+	return nil, errors.New("operação desconhecida")
+}
+
+// parsePointer separa um JSON Pointer (RFC 6901) nos seus tokens; "" é o documento todo.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: caminho inválido %q", ErrValidation, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func getValue(document any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := document.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("o campo %q não existe", token)
+			}
+			document = value
+		case []any:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			document = node[i]
+		default:
+			return nil, errors.New("o caminho não existe")
+		}
+	}
+
+	return document, nil
+}
+
+func addValue(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token, rest := path[0], path[1:]
+	switch node := document.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			node[token] = value
+			return node, nil
+		}
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("o campo %q não existe", token)
+		}
+		child, err := addValue(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = child
+		return node, nil
+	case []any:
+		if len(rest) == 0 {
+			i := len(node)
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(token, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			return slices.Insert(node, i, value), nil
+		}
+		i, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		if node[i], err = addValue(node[i], rest, value); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	return nil, errors.New("o caminho não existe")
+}
+
+// removeValue retira o valor do caminho e retorna o valor retirado e o documento alterado.
+func removeValue(document any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("o documento todo não pode ser removido")
+	}
+
+	token, rest := path[0], path[1:]
+	switch node := document.(type) {
+	case map[string]any:
+		child, ok := node[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("o campo %q não existe", token)
+		}
+		if len(rest) == 0 {
+			delete(node, token)
+			return child, node, nil
+		}
+		removed, child, err := removeValue(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		node[token] = child
+		return removed, node, nil
+	case []any:
+		i, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			return node[i], slices.Delete(node, i, i+1), nil
+		}
+		removed, child, err := removeValue(node[i], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		node[i] = child
+		return removed, node, nil
+	}
+
+	return nil, nil, errors.New("o caminho não existe")
+}
+
+// arrayIndex lê o índice de uma lista, entre zero e max.
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("índice inválido %q", token)
+	}
+	return i, nil
+}
+
+func decodeValue(raw json.RawMessage) (any, error) {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, errors.New("value inválido")
+	}
+	return value, nil
+}
+
+func mustEncode(value any) json.RawMessage {
+	encoded, _ := json.Marshal(value)
+	return encoded
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CreateTeam cria uma nova equipe. Quem cria a equipe entra nela como líder.
+func (service teamTaskService) CreateTeam(team Team) (int, error) {
+	if err := service.authorize(PermManageTeams); err != nil {
+		return 0, err
+	}
+
+	team.Name = strings.TrimSpace(team.Name)
+	if team.Name == "" {
+		return 0, fmt.Errorf("%w: nome da equipe é obrigatório", ErrValidation)
+	}
+	team.Archived = false
+
+	// A equipe e seu líder são gravados juntos
+	var teamID int
+	err := service.inTransaction(func(tx teamTaskService) error {
+		var err error
+		teamID, err = tx.db.CreateTeam(team)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao criar a equipe"))
+		}
+
+		if tx.actor != nil {
+			err = tx.db.AddTeamMember(teamID, tx.actor.ID, TeamRoleLead)
+			if err != nil {
+				return errors.Join(err, errors.New("erro ao adicionar o líder da equipe"))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return teamID, nil
+}
+
+// GetTeam retorna uma equipe com seus membros.
+func (service teamTaskService) GetTeam(teamID int) (Team, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return Team{}, err
+	}
+
+	team, err := service.db.GetTeamByID(teamID)
+	if err != nil {
+		return Team{}, errors.Join(err, errors.New("equipe não encontrada"))
+	}
+
+	team.Members, err = service.db.GetTeamMembers(teamID)
+	if err != nil {
+		return Team{}, errors.Join(err, errors.New("erro ao obter os membros da equipe"))
+	}
+
+	return team, nil
+}
+
+// GetAllTeams retorna todas as equipes, sem os membros.
+func (service teamTaskService) GetAllTeams() ([]Team, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	teams, err := service.db.GetAllTeams()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao recuperar as equipes"))
+	}
+
+	return teams, nil
+}
+
+// RenameTeam altera o nome de uma equipe.
+func (service teamTaskService) RenameTeam(teamID int, name string) error {
+	team, err := service.teamForManagement(teamID)
+	if err != nil {
+		return err
+	}
+
+	team.Name = strings.TrimSpace(name)
+	if team.Name == "" {
+		return fmt.Errorf("%w: nome da equipe é obrigatório", ErrValidation)
+	}
+
+	err = service.db.UpdateTeam(team)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao renomear a equipe"))
+	}
+
+	return nil
+}
+
+// SetTeamArchived arquiva ou reativa uma equipe. Equipes arquivadas continuam
+// visíveis, mas não recebem novas tarefas nem novos membros.
+func (service teamTaskService) SetTeamArchived(teamID int, archived bool) error {
+	team, err := service.teamForManagement(teamID)
+	if err != nil {
+		return err
+	}
+
+	team.Archived = archived
+	err = service.db.UpdateTeam(team)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao arquivar a equipe"))
+	}
+
+	return nil
+}
+
+// AddTeamMember adiciona um usuário à equipe ou altera seu papel, se ele já for membro.
+func (service teamTaskService) AddTeamMember(teamID, userID int, role TeamRole) error {
+	team, err := service.teamForManagement(teamID)
+	if err != nil {
+		return err
+	}
+	if team.Archived {
+		return fmt.Errorf("%w: equipe arquivada", ErrValidation)
+	}
+
+	if role == "" {
+		role = TeamRoleMember
+	}
+	if role != TeamRoleLead && role != TeamRoleMember {
+		return fmt.Errorf("%w: papel de equipe desconhecido %q", ErrValidation, role)
+	}
+
+	_, err = service.db.GetUserByID(userID)
+	if err != nil {
+		return errors.Join(err, errors.New("usuário não encontrado"))
+	}
+
+	// A verificação de participação e a gravação acontecem na mesma transação
+	return service.inTransaction(func(tx teamTaskService) error {
+		if _, isMember, err := tx.teamRole(teamID, userID); err != nil {
+			return err
+		} else if isMember {
+			err = tx.db.UpdateTeamMemberRole(teamID, userID, role)
+			if err != nil {
+				return errors.Join(err, errors.New("erro ao alterar o papel do membro"))
+			}
+			return nil
+		}
+
+		err := tx.db.AddTeamMember(teamID, userID, role)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao adicionar membro à equipe"))
+		}
+
+		return nil
+	})
+}
+
+// RemoveTeamMember remove um usuário da equipe.
+func (service teamTaskService) RemoveTeamMember(teamID, userID int) error {
+	if _, err := service.teamForManagement(teamID); err != nil {
+		return err
+	}
+
+	if _, isMember, err := service.teamRole(teamID, userID); err != nil {
+		return err
+	} else if !isMember {
+		return fmt.Errorf("%w: usuário não é membro da equipe", ErrNotFound)
+	}
+
+	err := service.db.RemoveTeamMember(teamID, userID)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao remover membro da equipe"))
+	}
+
+	return nil
+}
+
+// GetTasksForTeam retorna as tarefas vinculadas a uma equipe.
+func (service teamTaskService) GetTasksForTeam(teamID int) ([]Task, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	_, err := service.db.GetTeamByID(teamID)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("equipe não encontrada"))
+	}
+
+	tasks, err := service.db.GetTasksForTeam(teamID)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter tarefas da equipe"))
+	}
+
+	return service.withDetails(tasks)
+}
+
+// teamForManagement busca a equipe e verifica se o usuário atual pode administrá-la:
+// papéis com PermManageTeams administram qualquer equipe, líderes apenas a sua.
+func (service teamTaskService) teamForManagement(teamID int) (Team, error) {
+	team, err := service.db.GetTeamByID(teamID)
+	if err != nil {
+		return Team{}, errors.Join(err, errors.New("equipe não encontrada"))
+	}
+
+	if service.actor == nil || service.actor.Role.Can(PermManageTeams) {
+		return team, nil
+	}
+
+	role, isMember, err := service.teamRole(teamID, service.actor.ID)
+	if err != nil {
+		return Team{}, err
+	}
+	if !isMember || role != TeamRoleLead {
+		return Team{}, fmt.Errorf("%w: apenas líderes administram a equipe", ErrForbidden)
+	}
+
+	return team, nil
+}
+
+// teamRole retorna o papel do usuário na equipe e se ele é membro.
+func (service teamTaskService) teamRole(teamID, userID int) (TeamRole, bool, error) {
+	members, err := service.db.GetTeamMembers(teamID)
+	if err != nil {
+		return "", false, errors.Join(err, errors.New("erro ao obter os membros da equipe"))
+	}
+
+	for _, member := range members {
+		if member.UserID == userID {
+			return member.Role, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// validateTaskTeam verifica se a tarefa pode ser vinculada à equipe: ela precisa
+// existir, estar ativa e, para quem não edita qualquer tarefa, ter o usuário como membro.
+func (service teamTaskService) validateTaskTeam(teamID int) error {
+	if teamID == 0 {
+		return nil
+	}
+
+	team, err := service.db.GetTeamByID(teamID)
+	if err != nil {
+		return errors.Join(err, errors.New("equipe não encontrada"))
+	}
+	if team.Archived {
+		return fmt.Errorf("%w: equipe arquivada", ErrValidation)
+	}
+
+	if service.actor != nil && !service.actor.Role.Can(PermEditAnyTask) {
+		_, isMember, err := service.teamRole(teamID, service.actor.ID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return fmt.Errorf("%w: usuário não é membro da equipe", ErrForbidden)
+		}
+	}
+
+	return nil
+}
+
+// mergeTasks junta listas de tarefas sem repetições, ordenadas pelo ID.
+func mergeTasks(lists ...[]Task) []Task {
+	seen := make(map[int]bool)
+	var merged []Task
+	for _, list := range lists {
+		for _, task := range list {
+			if !seen[task.ID] {
+				seen[task.ID] = true
+				merged = append(merged, task)
+			}
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ID < merged[j].ID })
+	return merged
+}
@@ -2,18 +2,26 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // RegisterNewUser registra um novo usuário no sistema.
 func (service teamTaskService) RegisterNewUser(user User) (int, error) {
 	// Verificar se o e-mail do usuário já existe no banco de dados
-	emptyUsr := User{}
-	existingUser, err := service.db.GetUserByEmail(user.Email)
-	if err == nil && existingUser != emptyUsr {
+	_, err := service.db.GetUserByEmail(user.Email)
+	if err == nil {
 		service.log.Info("E-mail já está em uso")
 
 		return 0, errors.New("e-mail já está em uso")
 	}
+	if !errors.Is(err, ErrNotFound) {
+		return 0, errors.Join(err, errors.New("erro ao registrar novo usuário"))
+	}
 
 	// Validar os dados do usuário
 	if user.Name == "" || user.Email == "" || user.Password == "" {
@@ -22,6 +30,21 @@ func (service teamTaskService) RegisterNewUser(user User) (int, error) {
 		return 0, errors.New("dados do usuário incompletos")
 	}
 
+	// Definir o papel do novo usuário
+	role, err := service.newUserRole(user.Role)
+	if err != nil {
+		return 0, err
+	}
+	user.Role = role
+
+	// Nunca gravar a senha em texto puro
+	hash, err := service.hasher.Hash(user.Password)
+	if err != nil {
+		service.log.Error("Erro ao gerar hash da senha")
+		return 0, errors.New("erro ao registrar novo usuário")
+	}
+	user.Password = hash
+
 	// Adicionar o novo usuário ao banco de dados
 	user_id, err := service.db.AddUser(user)
 	if err != nil {
@@ -32,28 +55,168 @@ func (service teamTaskService) RegisterNewUser(user User) (int, error) {
 	return user_id, nil
 }
 
+// newUserRole decide o papel de um usuário recém-cadastrado. Apenas administradores
+// escolhem o papel; no autocadastro o primeiro usuário do sistema vira administrador
+// e os demais entram como membros.
+func (service teamTaskService) newUserRole(requested Role) (Role, error) {
+	if service.actor != nil && service.actor.Role.Can(PermManageRoles) {
+		if requested == "" {
+			return RoleMember, nil
+		}
+		return ParseRole(string(requested))
+	}
+
+	if requested != "" && requested != RoleMember {
+		return "", fmt.Errorf("%w: apenas administradores definem o papel de um usuário", ErrForbidden)
+	}
+
+	count, err := service.db.CountUsers()
+	if err != nil {
+		return "", errors.Join(err, errors.New("erro ao registrar novo usuário"))
+	}
+	if count == 0 {
+		return RoleAdmin, nil
+	}
+
+	return RoleMember, nil
+}
+
+// ChangeUserRole altera o papel de um usuário existente.
+func (service teamTaskService) ChangeUserRole(userID int, role Role) error {
+	if err := service.authorize(PermManageRoles); err != nil {
+		return err
+	}
+
+	role, err := ParseRole(string(role))
+	if err != nil {
+		return err
+	}
+
+	_, err = service.db.GetUserByID(userID)
+	if err != nil {
+		return errors.Join(err, errors.New("usuário não encontrado"))
+	}
+
+	err = service.db.UpdateUserRole(userID, role)
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao alterar o papel do usuário"))
+	}
+
+	return nil
+}
+
+// VerifyCredentials confere e-mail e senha e retorna o usuário autenticado, sem o hash da senha.
+// Se o hash armazenado usar parâmetros desatualizados, ele é refeito de forma transparente.
+func (service teamTaskService) VerifyCredentials(email, password string) (User, error) {
+	user, err := service.db.GetUserByEmail(email)
+	if errors.Is(err, ErrNotFound) {
+		// Gastar o mesmo tempo de um usuário existente para não revelar quais e-mails estão cadastrados
+		_, _ = service.hasher.Hash(password)
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, errors.Join(err, errors.New("erro ao buscar usuário"))
+	}
+
+	ok, needsRehash, err := service.hasher.Verify(password, user.Password)
+	if err != nil {
+		service.log.Error("Hash de senha inválido no banco de dados")
+		return User{}, ErrInvalidCredentials
+	}
+	if !ok {
+		return User{}, ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		hash, err := service.hasher.Hash(password)
+		if err == nil {
+			err = service.db.UpdateUserPassword(user.ID, hash)
+		}
+		if err != nil {
+			// O login não deve falhar por causa do rehash; ele será tentado novamente no próximo acesso
+			service.log.Error("Erro ao atualizar hash da senha: " + err.Error())
+		}
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
 // CreateTask cria uma nova tarefa com base nos dados de entrada fornecidos.
 // Retorna o ID da tarefa criada e um erro, se houver.
 func (service teamTaskService) CreateTask(input Task) (int, error) {
+	if err := service.authorize(PermCreateTask); err != nil {
+		return 0, err
+	}
+
 	// Validar entrada
 	if input.Title == "" || input.Description == "" {
 		service.log.Error("título e descrição são obrigatórios")
 		return 0, errors.New("título e descrição são obrigatórios")
 	}
 
-	// Validar prioridade (por exemplo, garantir que seja um valor válido como "High", "Medium" ou "Low")
-	if (input.Priority != "Alta" && input.Priority != "Média" && input.Priority != "Baixa") && input.Priority != "" {
+	// Validar prioridade: aceita o código ("High") ou um nome exibido ("Alta") e grava o código
+	priority, err := service.resolvePriority(input.Priority)
+	if err != nil {
 		service.log.Error("prioridade inválida")
-		return 0, errors.New("prioridade inválida")
+		return 0, err
+	}
+	input.Priority = priority.Code
+
+	// Validar a equipe da tarefa, se houver
+	if err := service.validateTaskTeam(input.TeamID); err != nil {
+		return 0, err
+	}
+
+	// Tarefas sem status começam no estado inicial do fluxo da equipe e do tipo da tarefa
+	input.Type = normalizeTaskType(input.Type)
+	workflow, err := service.workflowFor(input)
+	if err != nil {
+		return 0, err
+	}
+	if input.Status == "" {
+		input.Status = workflow.Initial
+	}
+	state, ok := workflow.State(input.Status)
+	if !ok {
+		return 0, fmt.Errorf("%w: status desconhecido %q", ErrValidation, input.Status)
+	}
+	input.Status = state.Name
+
+	if err := validateTaskDates(input); err != nil {
+		return 0, err
+	}
+	input = stampTask(input, nil, time.Now().UTC(), state.Completed)
+
+	// Validar a tarefa pai, se houver
+	if err := service.validateNewSubtask(input); err != nil {
+		return 0, err
 	}
 
-	// Criar a tarefa no banco de dados
-	taskID, err := service.db.CreateTask(input.Title, input.Description, input.Status, input.Priority, input.AssignedUsers)
+	// Sem prazo informado, a tarefa recebe o SLA da prioridade
+	if input.DueDate == nil && priority.SLA > 0 {
+		due := input.CreatedAt.Add(priority.SLA)
+		input.DueDate = &due
+	}
+
+	// Criar a tarefa no banco de dados, junto com o primeiro evento do histórico
+	var taskID int
+	err = service.inTransaction(func(tx teamTaskService) error {
+		var err error
+		taskID, err = tx.db.CreateTask(input)
+		if err != nil {
+			service.log.Error("Error salvado a task")
+			return err
+		}
+
+		return tx.recordEvent(taskID, TaskCreated, taskSnapshot(input, false))
+	})
 	if err != nil {
-		service.log.Error("Error salvado a task")
 		return 0, err
 	}
 
+	service.notify(NotificationAssignment, taskID, fmt.Sprintf("Você foi atribuído à tarefa #%d: %s", taskID, input.Title), input.AssignedUsers...)
+
 	// Se tudo correu bem, retornamos o ID da tarefa criada
 	return taskID, nil
 }
@@ -65,73 +228,228 @@ func (service teamTaskService) GetVisibleTasksForUser(userID int) ([]Task, error
 		return nil, errors.Join(err, errors.New("usuário não encontrado"))
 	}
 
-	// Obter todas as tarefas visíveis para o usuário
+	// Obter todas as tarefas visíveis para o usuário: as atribuídas a ele e as das suas equipes
 	tasks, err := service.db.GetTasksForUser(userID)
 	if err != nil {
 		return nil, errors.Join(err, errors.New("erro ao obter tarefas para o usuário"))
 	}
 
-	return tasks, nil
-}
-
-// FilterTasksByStatusAndPriority retorna todas as tarefas  com o status e a prioridade especificados.
-func (service teamTaskService) FilterTasksByStatusAndPriority(status, priority string) ([]Task, error) {
-
-	// Filtrar tarefas com base no status e na prioridade
-	tasks, err := service.db.GetAllTasks()
+	teams, err := service.db.GetTeamsForUser(userID)
 	if err != nil {
-		return nil, errors.Join(err, errors.New("erro ao obter tarefas"))
+		return nil, errors.Join(err, errors.New("erro ao obter equipes do usuário"))
 	}
-
-	var filteredTasks []Task
-	for _, task := range tasks {
-		if (status == "" || task.Status == status) && (priority == "" || task.Priority == priority) {
-			filteredTasks = append(filteredTasks, task)
+	for _, team := range teams {
+		teamTasks, err := service.db.GetTasksForTeam(team.ID)
+		if err != nil {
+			return nil, errors.Join(err, errors.New("erro ao obter tarefas da equipe"))
 		}
+		tasks = mergeTasks(tasks, teamTasks)
 	}
 
-	return filteredTasks, nil
+	return service.withDetails(tasks)
+}
+
+// FilterTasksByStatusAndPriority retorna todas as tarefas  com o status e a prioridade especificados.
+// A prioridade pode ser informada pelo código ou por um nome exibido.
+func (service teamTaskService) FilterTasksByStatusAndPriority(status, priority string) ([]Task, error) {
+	return service.FilterTasks(TaskFilter{Status: status, Priority: priority})
 }
 
 // AssignMemberToTask associa um membro da equipe a uma tarefa específica.
 func (service teamTaskService) AssignMemberToTask(taskID, memberID int) error {
-
-	// Verificar se a tarefa existe
-	_, err := service.db.GetTaskByID(taskID)
-	if err != nil {
-		return errors.Join(err, errors.New("tarefa não encontrada"))
+	if err := service.authorize(PermAssignTask); err != nil {
+		return err
 	}
 
 	// Verificar se o membro da equipe existe
-	_, err = service.db.GetUserByID(memberID)
+	_, err := service.db.GetUserByID(memberID)
 	if err != nil {
 		return errors.Join(err, errors.New("membro da equipe não encontrado"))
 	}
 
 	// Associar o membro da equipe à tarefa
-	err = service.db.AssignTaskToUser(taskID, memberID)
+	var task Task
+	err = service.inTransaction(func(tx teamTaskService) error {
+		// Verificar se a tarefa existe
+		var err error
+		task, err = tx.db.GetTaskByID(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("tarefa não encontrada"))
+		}
+		if err := tx.checkVersion(task); err != nil {
+			return err
+		}
+
+		if err := tx.db.AssignTaskToUser(taskID, memberID); err != nil {
+			return errors.Join(err, errors.New("erro ao associar membro da equipe à tarefa"))
+		}
+		if err := tx.touchTask(task); err != nil {
+			return err
+		}
+
+		return tx.recordEvent(taskID, TaskAssigned, []FieldChange{{Field: "assignee", After: strconv.Itoa(memberID)}})
+	})
 	if err != nil {
-		return errors.Join(err, errors.New("erro ao associar membro da equipe à tarefa"))
+		return err
 	}
 
+	service.notify(NotificationAssignment, taskID, fmt.Sprintf("Você foi atribuído à tarefa #%d: %s", taskID, task.Title), memberID)
+
 	return nil
 }
 
-// DeleteTask exclui uma tarefa específica do banco de dados.
-func (service teamTaskService) DeleteTask(taskID int) error {
-	// Verificar se a tarefa existe
-	_, err := service.db.GetTaskByID(taskID)
-	if err != nil {
-		return errors.Join(errors.New("tarefa não encontrada"))
+// UnassignMemberFromTask retira um membro da equipe dos responsáveis pela tarefa.
+func (service teamTaskService) UnassignMemberFromTask(taskID, memberID int) error {
+	if err := service.authorize(PermAssignTask); err != nil {
+		return err
 	}
 
-	// Excluir a tarefa do banco de dados
-	err = service.db.DeleteTask(taskID)
+	return service.inTransaction(func(tx teamTaskService) error {
+		task, err := tx.db.GetTaskByID(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("tarefa não encontrada"))
+		}
+		if err := tx.checkVersion(task); err != nil {
+			return err
+		}
+		if err := tx.db.UnassignTaskFromUser(taskID, memberID); err != nil {
+			return err
+		}
+		if err := tx.touchTask(task); err != nil {
+			return err
+		}
+
+		return tx.recordEvent(taskID, TaskUnassigned, []FieldChange{{Field: "assignee", Before: strconv.Itoa(memberID)}})
+	})
+}
+
+// touchTask avança a versão e a data de alteração da tarefa. As mudanças gravadas fora
+// da tabela de tarefas, como as atribuições, também invalidam a ETag que o cliente tem.
+func (service teamTaskService) touchTask(task Task) error {
+	task.UpdatedAt = time.Now().UTC()
+	if err := service.db.UpdateTask(task.ID, task); err != nil {
+		return errors.Join(err, errors.New("erro ao atualizar a versão da tarefa"))
+	}
+	return nil
+}
+
+// setAssignees deixa a tarefa com exatamente os responsáveis desejados, atribuindo os
+// que faltam e retirando os que sobram, e retorna os que foram atribuídos. Chamado depois
+// de UpdateTask, que já avançou a versão da tarefa.
+func (service teamTaskService) setAssignees(taskID int, current, desired []int) ([]int, error) {
+	assigned := make(map[int]bool, len(current))
+	for _, userID := range current {
+		assigned[userID] = true
+	}
+	wanted := make(map[int]bool, len(desired))
+	for _, userID := range desired {
+		wanted[userID] = true
+	}
+	if maps.Equal(assigned, wanted) {
+		return nil, nil
+	}
+	if err := service.authorize(PermAssignTask); err != nil {
+		return nil, err
+	}
+
+	for _, userID := range current {
+		if wanted[userID] {
+			continue
+		}
+		if err := service.db.UnassignTaskFromUser(taskID, userID); err != nil {
+			return nil, errors.Join(err, errors.New("erro ao retirar membro da equipe da tarefa"))
+		}
+		if err := service.recordEvent(taskID, TaskUnassigned, []FieldChange{{Field: "assignee", Before: strconv.Itoa(userID)}}); err != nil {
+			return nil, err
+		}
+	}
+
+	var added []int
+	for _, userID := range desired {
+		if assigned[userID] {
+			continue
+		}
+		assigned[userID] = true
+		if err := service.db.AssignTaskToUser(taskID, userID); err != nil {
+			return nil, errors.Join(err, errors.New("erro ao associar membro da equipe à tarefa"))
+		}
+		if err := service.recordEvent(taskID, TaskAssigned, []FieldChange{{Field: "assignee", After: strconv.Itoa(userID)}}); err != nil {
+			return nil, err
+		}
+		added = append(added, userID)
+	}
+
+	return added, nil
+}
+
+// withAssignees preenche os responsáveis pelas tarefas com as atribuições gravadas.
+func (service teamTaskService) withAssignees(tasks []Task) ([]Task, error) {
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	assignees, err := service.db.GetAssigneesForTasks(ids)
 	if err != nil {
-		return errors.Join(errors.New("erro ao excluir a tarefa"))
+		return nil, errors.Join(err, errors.New("erro ao obter os responsáveis pelas tarefas"))
 	}
 
-	return nil
+	for i := range tasks {
+		tasks[i].AssignedUsers = assignees[tasks[i].ID]
+		if tasks[i].AssignedUsers == nil {
+			tasks[i].AssignedUsers = []int{}
+		}
+	}
+
+	return tasks, nil
+}
+
+// DeleteTask move a tarefa para a lixeira, de onde ela pode ser restaurada até a
+// limpeza apagá-la de vez. Suas subtarefas passam ao primeiro nível.
+func (service teamTaskService) DeleteTask(taskID int) error {
+	if err := service.authorize(PermDeleteTask); err != nil {
+		return err
+	}
+
+	return service.inTransaction(func(tx teamTaskService) error {
+		// Verificar se a tarefa existe
+		task, err := tx.db.GetTaskByID(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("tarefa não encontrada"))
+		}
+		if err := tx.checkVersion(task); err != nil {
+			return err
+		}
+
+		// O evento guarda a tarefa como estava ao ir para a lixeira
+		task.AssignedUsers, err = tx.db.GetTaskAssignees(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao obter os responsáveis pela tarefa"))
+		}
+		if err := tx.recordEvent(taskID, TaskDeleted, taskSnapshot(task, true)); err != nil {
+			return err
+		}
+
+		children, err := tx.db.GetTaskChildren(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao obter as subtarefas"))
+		}
+		for _, child := range children {
+			if err := tx.moveToTopLevel(child); err != nil {
+				return err
+			}
+		}
+
+		// Mover a tarefa para a lixeira
+		if err := tx.db.TrashTask(taskID, time.Now().UTC()); err != nil {
+			return errors.Join(err, errors.New("erro ao excluir a tarefa"))
+		}
+
+		return nil
+	})
 }
 
 func (service teamTaskService) GetTaskByID(taskID int) (Task, error) {
@@ -141,24 +459,251 @@ func (service teamTaskService) GetTaskByID(taskID int) (Task, error) {
 		return Task{}, errors.Join(err, errors.New("tarefa não encontrada"))
 	}
 
-	return task, nil
+	tasks, err := service.withDetails([]Task{task})
+	if err != nil {
+		return Task{}, err
+	}
+
+	return service.withProgress(tasks[0])
 }
 
-// EditTask edita uma tarefa existente no banco de dados.
-func (service teamTaskService) EditTask(taskID int, updatedTask Task) error {
-	// Verificar se a tarefa existe
-	_, err := service.GetTaskByID(taskID)
+// EditTask edita uma tarefa existente no banco de dados e retorna a tarefa atualizada.
+// Título e descrição são obrigatórios; os demais campos omitidos mantêm os valores atuais.
+func (service teamTaskService) EditTask(taskID int, updatedTask Task) (Task, error) {
+	return service.updateTask(taskID, func(current Task) (Task, error) {
+		// Os campos omitidos mantêm os valores atuais; só o PATCH apaga campos
+		if updatedTask.Status == "" {
+			updatedTask.Status = current.Status
+		}
+		if normalizeTaskType(updatedTask.Type) == "" {
+			updatedTask.Type = current.Type
+		}
+		if updatedTask.Resolution == "" {
+			updatedTask.Resolution = current.Resolution
+		}
+		if updatedTask.Priority == "" {
+			updatedTask.Priority = current.Priority
+		}
+		if updatedTask.TeamID == 0 {
+			updatedTask.TeamID = current.TeamID
+		}
+		if updatedTask.StartDate == nil {
+			updatedTask.StartDate = current.StartDate
+		}
+		if updatedTask.DueDate == nil {
+			updatedTask.DueDate = current.DueDate
+		}
+		return updatedTask, nil
+	})
+}
+
+// PatchTask altera apenas os campos da tarefa tocados pelo patch e retorna a tarefa
+// atualizada. A tarefa resultante passa pelas mesmas validações do EditTask.
+func (service teamTaskService) PatchTask(taskID int, patch TaskPatch) (Task, error) {
+	return service.updateTask(taskID, func(current Task) (Task, error) {
+		return patch.apply(current)
+	})
+}
+
+// updateTask grava a tarefa retornada por change, que recebe a tarefa atual depois das
+// verificações de acesso e de versão, com as validações comuns às edições, e retorna a
+// tarefa como ficou gravada.
+func (service teamTaskService) updateTask(taskID int, change func(current Task) (Task, error)) (Task, error) {
+	// A leitura do estado atual e a gravação acontecem na mesma transação
+	var current, updatedTask, saved Task
+	var added []int
+	err := service.inTransaction(func(tx teamTaskService) error {
+		// Verificar se a tarefa existe
+		var err error
+		current, err = tx.GetTaskByID(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("tarefa não encontrada"))
+		}
+
+		// Verificar se o usuário pode editar esta tarefa
+		if err := tx.authorizeTaskEdit(taskID); err != nil {
+			return err
+		}
+		if err := tx.checkVersion(current); err != nil {
+			return err
+		}
+		updatedTask, err = change(current)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(updatedTask.Title) == "" || strings.TrimSpace(updatedTask.Description) == "" {
+			return fmt.Errorf("%w: título e descrição são obrigatórios", ErrValidation)
+		}
+
+		// Validar a nova equipe, se a tarefa mudar de equipe; as etiquetas da equipe
+		// anterior saem da tarefa
+		if updatedTask.TeamID != current.TeamID {
+			if err := tx.validateTaskTeam(updatedTask.TeamID); err != nil {
+				return err
+			}
+			if err := tx.dropForeignLabels(taskID, updatedTask.TeamID); err != nil {
+				return err
+			}
+		}
+
+		// A tarefa pai só muda por SetTaskParent
+		updatedTask.ParentID = current.ParentID
+
+		updatedTask.Type = normalizeTaskType(updatedTask.Type)
+		priority, err := tx.resolvePriority(updatedTask.Priority)
+		if err != nil {
+			return err
+		}
+		updatedTask.Priority = priority.Code
+
+		workflows, err := tx.loadWorkflows()
+		if err != nil {
+			return err
+		}
+		workflow := workflows.forTask(updatedTask)
+		state, known := workflow.State(updatedTask.Status)
+		switch {
+		case workflows.forTask(current).ID != workflow.ID:
+			// Ao mudar de fluxo, por equipe ou tipo, a tarefa precisa de um status do novo
+			// fluxo, sem transição a validar
+			if !known {
+				return fmt.Errorf("%w: o status %q não existe no fluxo da tarefa; informe um dos status do novo fluxo", ErrValidation, updatedTask.Status)
+			}
+		case strings.EqualFold(updatedTask.Status, current.Status):
+		case !known:
+			return fmt.Errorf("%w: status desconhecido %q", ErrValidation, updatedTask.Status)
+		default:
+			// Mudanças de status seguem as transições do fluxo
+			if err := checkTransition(workflow, current, state, updatedTask.Resolution, ""); err != nil {
+				return err
+			}
+		}
+		if known {
+			updatedTask.Status = state.Name
+		}
+		if err := tx.checkOpenChildren(current, known && state.Completed); err != nil {
+			return err
+		}
+		if err := tx.checkBlockedStart(current, state); err != nil {
+			return err
+		}
+
+		if err := validateTaskDates(updatedTask); err != nil {
+			return err
+		}
+		updatedTask = stampTask(updatedTask, &current, time.Now().UTC(), known && state.Completed)
+
+		// Executar a edição da tarefa no banco de dados
+		err = tx.db.UpdateTask(taskID, updatedTask)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao editar a tarefa"))
+		}
+		if err := tx.recordUpdate(current, updatedTask); err != nil {
+			return err
+		}
+
+		// Sem a lista de responsáveis, eles continuam os mesmos
+		if updatedTask.AssignedUsers != nil {
+			added, err = tx.setAssignees(taskID, current.AssignedUsers, updatedTask.AssignedUsers)
+			if err != nil {
+				return err
+			}
+		}
+
+		saved, err = tx.GetTaskByID(taskID)
+		return err
+	})
 	if err != nil {
-		return errors.Join(err, errors.New("tarefa não encontrada"))
+		return Task{}, err
 	}
 
-	// Executar a edição da tarefa no banco de dados
-	err = service.db.UpdateTask(taskID, updatedTask)
+	// As notificações só saem depois que a edição foi confirmada
+	service.notifyStatusChange(taskID, current.Status, updatedTask.Status)
+	service.notify(NotificationAssignment, taskID, fmt.Sprintf("Você foi atribuído à tarefa #%d: %s", taskID, updatedTask.Title), added...)
+
+	return saved, nil
+}
+
+// TransitionTask muda o status da tarefa seguindo o fluxo de trabalho. A resolução e o
+// comentário são exigidos quando a transição pede; o comentário é publicado na tarefa
+// em nome do usuário atual, na mesma transação da mudança.
+func (service teamTaskService) TransitionTask(taskID int, transition TaskTransition) (Task, error) {
+	transition.Resolution = strings.TrimSpace(transition.Resolution)
+	transition.Comment = strings.TrimSpace(transition.Comment)
+
+	var current, task Task
+	err := service.inTransaction(func(tx teamTaskService) error {
+		var err error
+		current, err = tx.GetTaskByID(taskID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.authorizeTaskEdit(taskID); err != nil {
+			return err
+		}
+
+		workflow, err := tx.workflowFor(current)
+		if err != nil {
+			return err
+		}
+		state, ok := workflow.State(transition.To)
+		if !ok {
+			return fmt.Errorf("%w: status desconhecido %q", ErrValidation, transition.To)
+		}
+		if strings.EqualFold(current.Status, state.Name) {
+			return fmt.Errorf("%w: a tarefa já está em %q", ErrInvalidTransition, current.Status)
+		}
+		if err := checkTransition(workflow, current, state, transition.Resolution, transition.Comment); err != nil {
+			return err
+		}
+		if err := tx.checkOpenChildren(current, state.Completed); err != nil {
+			return err
+		}
+		if err := tx.checkBlockedStart(current, state); err != nil {
+			return err
+		}
+
+		task = current
+		task.Status = state.Name
+		if transition.Resolution != "" {
+			task.Resolution = transition.Resolution
+		}
+		task = stampTask(task, &current, time.Now().UTC(), state.Completed)
+
+		if err := tx.db.UpdateTask(taskID, task); err != nil {
+			return errors.Join(err, errors.New("erro ao mudar o status da tarefa"))
+		}
+		task.Version++
+		if err := tx.recordUpdate(current, task); err != nil {
+			return err
+		}
+
+		if transition.Comment != "" {
+			if _, err := tx.CreateComment(taskID, Comment{Text: transition.Comment}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return errors.Join(err, errors.New("erro ao editar a tarefa"))
+		return Task{}, err
 	}
 
-	return nil
+	service.notifyStatusChange(taskID, current.Status, task.Status)
+
+	return task, nil
+}
+
+// notifyStatusChange avisa os responsáveis pela tarefa quando o status muda.
+func (service teamTaskService) notifyStatusChange(taskID int, from, to string) {
+	if from == to {
+		return
+	}
+
+	content := fmt.Sprintf("A tarefa #%d mudou de status: %s → %s", taskID, from, to)
+	service.notify(NotificationStatusChange, taskID, content, service.taskAssignees(taskID)...)
 }
 
 func (service teamTaskService) GetAllTasks() ([]Task, error) {
@@ -167,33 +712,143 @@ func (service teamTaskService) GetAllTasks() ([]Task, error) {
 		return []Task{}, errors.Join(err, errors.New("erro ao recuperar as tarefas"))
 	}
 
-	return tasks, nil
+	return service.withDetails(tasks)
+}
+
+// GetOverdueTasks retorna as tarefas não concluídas com o prazo vencido, da mais atrasada
+// à menos atrasada.
+func (service teamTaskService) GetOverdueTasks() ([]Task, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	tasks, err := service.db.GetOverdueTasks(time.Now().UTC())
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter tarefas atrasadas"))
+	}
+
+	return service.withDetails(tasks)
+}
+
+// GetUpcomingTasksForUser retorna as tarefas visíveis para o usuário, ainda não
+// concluídas, cujo prazo termina nos próximos days dias, ordenadas pelo prazo.
+func (service teamTaskService) GetUpcomingTasksForUser(userID, days int) ([]Task, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("%w: o número de dias deve ser positivo", ErrValidation)
+	}
+
+	tasks, err := service.GetVisibleTasksForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	limit := now.AddDate(0, 0, days)
+	var upcoming []Task
+	for _, task := range tasks {
+		if task.DueDate != nil && task.CompletedAt == nil && !task.DueDate.Before(now) && !task.DueDate.After(limit) {
+			upcoming = append(upcoming, task)
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].DueDate.Before(*upcoming[j].DueDate) })
+
+	return upcoming, nil
+}
+
+// validateTaskDates confere se a data de início não é posterior ao prazo.
+func validateTaskDates(task Task) error {
+	if task.StartDate != nil && task.DueDate != nil && task.StartDate.After(*task.DueDate) {
+		return fmt.Errorf("%w: a data de início não pode ser posterior ao prazo", ErrValidation)
+	}
+
+	return nil
+}
+
+// stampTask preenche as datas controladas pelo serviço. current é a versão gravada da
+// tarefa, ou nil na criação: a data de criação é preservada e a de conclusão é definida
+// quando a tarefa entra em um status concluído e apagada, junto com a resolução, quando
+// ela é reaberta.
+func stampTask(task Task, current *Task, now time.Time, completed bool) Task {
+	task.StartDate = utcOrNil(task.StartDate)
+	task.DueDate = utcOrNil(task.DueDate)
+
+	task.CreatedAt = now
+	if current != nil {
+		task.CreatedAt = current.CreatedAt
+		task.Version = current.Version
+	}
+	task.UpdatedAt = now
+
+	switch {
+	case !completed:
+		task.CompletedAt = nil
+		task.Resolution = ""
+	case current != nil && current.CompletedAt != nil:
+		task.CompletedAt = current.CompletedAt
+	default:
+		task.CompletedAt = &now
+	}
+
+	return task
 }
 
-// DeleteUser deleta um usuário existente do banco de dados.
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// DeleteUser move um usuário para a lixeira. Ele deixa de entrar no sistema e sai das
+// tarefas e equipes até ser restaurado.
 func (service teamTaskService) DeleteUser(userID int) error {
+	if err := service.authorize(PermDeleteUser); err != nil {
+		return err
+	}
+
 	// Verificar se o usuário existe
 	_, err := service.db.GetUserByID(userID)
 	if err != nil {
 		return errors.Join(err, errors.New("usuário não encontrado"))
 	}
 
-	// Deletar o usuário do banco de dados
-	err = service.db.RemoveUser(userID)
-	if err != nil {
-		return errors.Join(err, errors.New("erro ao deletar o usuário"))
-	}
+	// As tarefas atribuídas a ele registram a saída no histórico
+	return service.inTransaction(func(tx teamTaskService) error {
+		tasks, err := tx.db.GetTasksForUser(userID)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao obter as tarefas do usuário"))
+		}
 
-	return nil
+		if err := tx.db.TrashUser(userID, time.Now().UTC()); err != nil {
+			return errors.Join(err, errors.New("erro ao deletar o usuário"))
+		}
+
+		for _, task := range tasks {
+			if err := tx.touchTask(task); err != nil {
+				return err
+			}
+			if err := tx.recordEvent(task.ID, TaskUnassigned, []FieldChange{{Field: "assignee", Before: strconv.Itoa(userID)}}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 // GetUserByID busca um usuário no banco de dados pelo ID.
 func (service teamTaskService) GetUserByID(userID int) (User, error) {
-	// Verificar se a tarefa existe
-	task, err := service.db.GetUserByID(userID)
+	// Verificar se o usuário existe
+	user, err := service.db.GetUserByID(userID)
 	if err != nil {
 		return User{}, err
 	}
 
-	return task, nil
+	// O hash da senha não sai da camada de serviço
+	user.Password = ""
+	return user, nil
 }
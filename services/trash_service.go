@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TaskRestored é registrado quando a tarefa sai da lixeira.
+const TaskRestored TaskEventKind = "restored"
+
+// ListTrashedTasks retorna as tarefas da lixeira, das excluídas há mais tempo às mais
+// recentes.
+func (service teamTaskService) ListTrashedTasks() ([]Task, error) {
+	if err := service.authorize(PermDeleteTask); err != nil {
+		return nil, err
+	}
+
+	tasks, err := service.db.ListTrashedTasks()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter a lixeira de tarefas"))
+	}
+	if tasks == nil {
+		return []Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// RestoreTask tira a tarefa da lixeira. Se a tarefa pai já não existir, ela volta no
+// primeiro nível; se o status saiu do fluxo enquanto ela estava na lixeira, ela volta no
+// estado inicial.
+func (service teamTaskService) RestoreTask(taskID int) error {
+	if err := service.authorize(PermDeleteTask); err != nil {
+		return err
+	}
+
+	return service.inTransaction(func(tx teamTaskService) error {
+		if err := tx.db.RestoreTask(taskID); err != nil {
+			return errors.Join(err, errors.New("tarefa não encontrada na lixeira"))
+		}
+
+		task, err := tx.db.GetTaskByID(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao obter a tarefa restaurada"))
+		}
+		task.AssignedUsers, err = tx.db.GetTaskAssignees(taskID)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao obter os responsáveis pela tarefa"))
+		}
+		if err := tx.recordEvent(taskID, TaskRestored, taskSnapshot(task, false)); err != nil {
+			return err
+		}
+		if task, err = tx.fitRestoredStatus(task); err != nil {
+			return err
+		}
+
+		if task.ParentID == 0 {
+			return nil
+		}
+		_, err = tx.db.GetTaskByID(task.ParentID)
+		if errors.Is(err, ErrNotFound) {
+			return tx.moveToTopLevel(task)
+		}
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao obter a tarefa pai"))
+		}
+
+		return nil
+	})
+}
+
+// ListTrashedUsers retorna os usuários da lixeira, dos excluídos há mais tempo aos mais
+// recentes.
+func (service teamTaskService) ListTrashedUsers() ([]User, error) {
+	if err := service.authorize(PermDeleteUser); err != nil {
+		return nil, err
+	}
+
+	users, err := service.db.ListTrashedUsers()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter a lixeira de usuários"))
+	}
+
+	// O hash da senha não sai da camada de serviço
+	trashed := make([]User, len(users))
+	for i, user := range users {
+		user.Password = ""
+		trashed[i] = user
+	}
+	return trashed, nil
+}
+
+// RestoreUser tira o usuário da lixeira, com suas atribuições e participações em equipes.
+func (service teamTaskService) RestoreUser(userID int) error {
+	if err := service.authorize(PermDeleteUser); err != nil {
+		return err
+	}
+
+	return service.inTransaction(func(tx teamTaskService) error {
+		if err := tx.db.RestoreUser(userID); err != nil {
+			return errors.Join(err, errors.New("usuário não encontrado na lixeira"))
+		}
+
+		// As tarefas que voltam a ter o usuário registram a atribuição no histórico
+		tasks, err := tx.db.GetTasksForUser(userID)
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao obter as tarefas do usuário"))
+		}
+		for _, task := range tasks {
+			if err := tx.touchTask(task); err != nil {
+				return err
+			}
+			if err := tx.recordEvent(task.ID, TaskAssigned, []FieldChange{{Field: "assignee", After: strconv.Itoa(userID)}}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// PurgeTrash apaga de vez as tarefas e os usuários que estão na lixeira desde antes de
+// before. Cada item é apagado em sua própria transação; o conteúdo dos anexos só é
+// apagado depois que a tarefa saiu do banco.
+func (service teamTaskService) PurgeTrash(before time.Time) (tasks, users int, err error) {
+	if err := service.authorize(PermDeleteTask); err != nil {
+		return 0, 0, err
+	}
+	if err := service.authorize(PermDeleteUser); err != nil {
+		return 0, 0, err
+	}
+
+	trashedTasks, err := service.db.ListTrashedTasks()
+	if err != nil {
+		return 0, 0, errors.Join(err, errors.New("erro ao obter a lixeira de tarefas"))
+	}
+	for _, task := range trashedTasks {
+		if !task.DeletedAt.Before(before) {
+			break
+		}
+
+		var blobKeys []string
+		err := service.inTransaction(func(tx teamTaskService) error {
+			attachments, err := tx.db.ListAttachments(task.ID)
+			if err != nil {
+				return errors.Join(err, errors.New("erro ao obter os anexos da tarefa"))
+			}
+			for _, attachment := range attachments {
+				blobKeys = append(blobKeys, attachment.StorageKey)
+			}
+
+			return tx.db.DeleteTask(task.ID)
+		})
+		if err != nil {
+			return tasks, 0, errors.Join(err, fmt.Errorf("erro ao apagar a tarefa #%d", task.ID))
+		}
+		service.removeBlobs(blobKeys...)
+		tasks++
+	}
+
+	trashedUsers, err := service.db.ListTrashedUsers()
+	if err != nil {
+		return tasks, 0, errors.Join(err, errors.New("erro ao obter a lixeira de usuários"))
+	}
+	for _, user := range trashedUsers {
+		if !user.DeletedAt.Before(before) {
+			break
+		}
+		if err := service.db.RemoveUser(user.ID); err != nil {
+			return tasks, users, errors.Join(err, fmt.Errorf("erro ao apagar o usuário %d", user.ID))
+		}
+		users++
+	}
+
+	return tasks, users, nil
+}
+
+// fitRestoredStatus ajusta o status da tarefa restaurada ao fluxo atual. As mudanças de
+// fluxo não migram as tarefas da lixeira, por isso um status renomeado ou removido
+// volta ao estado inicial, e a data de conclusão segue o estado.
+func (service teamTaskService) fitRestoredStatus(task Task) (Task, error) {
+	workflow, err := service.workflowFor(task)
+	if err != nil {
+		return Task{}, err
+	}
+	state, known := workflow.State(task.Status)
+	if !known {
+		state, _ = workflow.State(workflow.Initial)
+	}
+	if state.Name == task.Status && state.Completed == (task.CompletedAt != nil) {
+		return task, nil
+	}
+
+	updated := task
+	updated.Status = state.Name
+	updated = stampTask(updated, &task, time.Now().UTC(), state.Completed)
+	if err := service.db.UpdateTask(task.ID, updated); err != nil {
+		return Task{}, errors.Join(err, errors.New("erro ao ajustar o status da tarefa restaurada"))
+	}
+	updated.Version++
+
+	return updated, service.recordUpdate(task, updated)
+}
+
+// moveToTopLevel leva a tarefa para o primeiro nível, quando a tarefa pai sai de cena.
+func (service teamTaskService) moveToTopLevel(task Task) error {
+	before := task
+	task.ParentID = 0
+	task.UpdatedAt = time.Now().UTC()
+	if err := service.db.UpdateTask(task.ID, task); err != nil {
+		return errors.Join(err, fmt.Errorf("erro ao mover a subtarefa #%d para o primeiro nível", task.ID))
+	}
+
+	return service.recordUpdate(before, task)
+}
@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/services/unit_tests/mock"
+	"github.com/mclcavalcante/teamTask/storage"
+	"go.uber.org/zap"
+)
+
+// pngHeader é o início de um arquivo PNG, suficiente para a detecção do tipo.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newAttachmentService(t *testing.T, policy service.AttachmentPolicy) (service.Service, *storage.Local) {
+	t.Helper()
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("Erro ao criar o armazenamento: %v", err)
+	}
+	s := service.NewService(mock.NewTestRepository(), zap.NewNop(), service.WithHashParams(testHashParams),
+		service.WithAttachmentStore(store), service.WithAttachmentPolicy(policy))
+	return s, store
+}
+
+func upload(name string, content []byte) service.AttachmentUpload {
+	return service.AttachmentUpload{Name: name, Size: int64(len(content)), Content: bytes.NewReader(content)}
+}
+
+func TestAddAttachment(t *testing.T) {
+	s, _ := newAttachmentService(t, service.DefaultAttachmentPolicy())
+	users := newUsersWithRoles(t, s)
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+
+	// O tipo vem do conteúdo; o caminho enviado junto com o nome é descartado
+	photo, err := s.AddAttachment(taskID, upload(`C:\fotos\..\tela.bin`, pngHeader))
+	if err != nil {
+		t.Fatalf("Erro ao anexar imagem: %v", err)
+	}
+	if photo.ID == 0 || photo.TaskID != taskID || photo.Name != "tela.bin" || photo.ContentType != "image/png" || photo.Size != int64(len(pngHeader)) {
+		t.Errorf("Anexo incorreto: %+v", photo)
+	}
+
+	// Texto simples ganha o tipo da extensão
+	csv, err := s.AddAttachment(taskID, upload("../../dados.csv", []byte("a,b\n1,2\n")))
+	if err != nil {
+		t.Fatalf("Erro ao anexar CSV: %v", err)
+	}
+	if csv.Name != "dados.csv" || !strings.HasPrefix(csv.ContentType, "text/csv") {
+		t.Errorf("Anexo CSV incorreto: %+v", csv)
+	}
+
+	// HTML com extensão de texto continua sendo HTML, que não é permitido
+	if _, err := s.AddAttachment(taskID, upload("nota.txt", []byte("<html><script>alert(1)</script></html>"))); !errors.Is(err, service.ErrUnsupportedType) {
+		t.Errorf("Esperava-se ErrUnsupportedType para HTML, obtido %v", err)
+	}
+	if _, err := s.AddAttachment(taskID, upload("programa.exe", []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00"))); !errors.Is(err, service.ErrUnsupportedType) {
+		t.Errorf("Esperava-se ErrUnsupportedType para executável, obtido %v", err)
+	}
+	if _, err := s.AddAttachment(taskID, upload("  ", pngHeader)); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para nome vazio, obtido %v", err)
+	}
+	if _, err := s.AddAttachment(taskID, upload("vazio.txt", nil)); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para arquivo vazio, obtido %v", err)
+	}
+	if _, err := s.AddAttachment(999, upload("foto.png", pngHeader)); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para tarefa inexistente, obtido %v", err)
+	}
+	if _, err := s.AsUser(users[service.RoleViewer]).AddAttachment(taskID, upload("foto.png", pngHeader)); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para leitor, obtido %v", err)
+	}
+
+	attachments, err := s.AsUser(users[service.RoleViewer]).ListAttachments(taskID)
+	if err != nil {
+		t.Fatalf("Erro ao listar anexos: %v", err)
+	}
+	if len(attachments) != 2 || attachments[0].ID != photo.ID || attachments[1].ID != csv.ID {
+		t.Errorf("Anexos incorretos: %+v", attachments)
+	}
+}
+
+func TestAttachmentLimits(t *testing.T) {
+	s, _ := newAttachmentService(t, service.AttachmentPolicy{MaxSize: 16, AllowedTypes: []string{"image/*"}})
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+
+	if _, err := s.AddAttachment(taskID, upload("foto.png", pngHeader)); err != nil {
+		t.Errorf("Imagens deveriam ser aceitas pelo curinga: %v", err)
+	}
+	if _, err := s.AddAttachment(taskID, upload("grande.png", append(pngHeader, make([]byte, 16)...))); !errors.Is(err, service.ErrTooLarge) {
+		t.Errorf("Esperava-se ErrTooLarge, obtido %v", err)
+	}
+	if _, err := s.AddAttachment(taskID, upload("nota.txt", []byte("texto"))); !errors.Is(err, service.ErrUnsupportedType) {
+		t.Errorf("Esperava-se ErrUnsupportedType para texto, obtido %v", err)
+	}
+
+	if err := (service.AttachmentPolicy{MaxSize: 0, AllowedTypes: []string{"texto"}}).Validate(); err == nil {
+		t.Errorf("Esperava-se erro para política inválida")
+	}
+
+	// Sem armazenamento configurado os anexos ficam desligados
+	plain := NewTestService()
+	taskID, _ = plain.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+	if _, err := plain.AddAttachment(taskID, upload("foto.png", pngHeader)); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation sem armazenamento, obtido %v", err)
+	}
+}
+
+func TestOpenAndDeleteAttachment(t *testing.T) {
+	s, store := newAttachmentService(t, service.DefaultAttachmentPolicy())
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+	other, _ := s.CreateTask(service.Task{Title: "Outra", Description: "Descrição"})
+	attachment, err := s.AddAttachment(taskID, upload("foto.png", pngHeader))
+	if err != nil {
+		t.Fatalf("Erro ao anexar: %v", err)
+	}
+
+	got, content, err := s.OpenAttachment(taskID, attachment.ID)
+	if err != nil {
+		t.Fatalf("Erro ao abrir o anexo: %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if got.Name != "foto.png" || !bytes.Equal(data, pngHeader) {
+		t.Errorf("Conteúdo incorreto: %+v %q", got, data)
+	}
+
+	if _, _, err := s.OpenAttachment(other, attachment.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para anexo de outra tarefa, obtido %v", err)
+	}
+	if err := s.DeleteAttachment(other, attachment.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound ao excluir por outra tarefa, obtido %v", err)
+	}
+
+	if err := s.DeleteAttachment(taskID, attachment.ID); err != nil {
+		t.Fatalf("Erro ao excluir o anexo: %v", err)
+	}
+	if _, _, err := s.OpenAttachment(taskID, attachment.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound depois da exclusão, obtido %v", err)
+	}
+	if _, err := store.Get(attachment.StorageKey); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("O conteúdo do anexo excluído continua no armazenamento: %v", err)
+	}
+
+	// A tarefa na lixeira mantém os anexos; apagá-la de vez apaga o conteúdo
+	note, _ := s.AddAttachment(taskID, upload("nota.txt", []byte("texto")))
+	if err := s.DeleteTask(taskID); err != nil {
+		t.Fatalf("Erro ao excluir a tarefa: %v", err)
+	}
+	stored, err := store.Get(note.StorageKey)
+	if err != nil {
+		t.Fatalf("O conteúdo do anexo deveria continuar no armazenamento enquanto a tarefa estiver na lixeira: %v", err)
+	}
+	stored.Close()
+	if _, _, err := s.PurgeTrash(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Erro ao limpar a lixeira: %v", err)
+	}
+	if _, err := store.Get(note.StorageKey); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para o conteúdo depois de apagar a tarefa, obtido %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+func newLoggedInService(t *testing.T) (service.Service, service.TokenPair) {
+	s := NewTestService()
+
+	_, err := s.RegisterNewUser(service.User{Name: "John Doe", Email: "john@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Erro ao registrar usuário: %v", err)
+	}
+
+	tokens, err := s.Login("john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Erro inesperado no login: %v", err)
+	}
+
+	return s, tokens
+}
+
+func TestLoginAndAuthenticate(t *testing.T) {
+	s, tokens := newLoggedInService(t)
+
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.TokenType != "Bearer" {
+		t.Fatalf("Par de tokens incompleto: %+v", tokens)
+	}
+
+	user, sessionID, err := s.Authenticate(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Erro inesperado ao autenticar o access token: %v", err)
+	}
+	if user.Email != "john@example.com" || user.Password != "" || sessionID == "" {
+		t.Errorf("Usuário autenticado não corresponde ao esperado: %+v", user)
+	}
+}
+
+func TestLoginWithWrongPassword(t *testing.T) {
+	s, _ := newLoggedInService(t)
+
+	_, err := s.Login("john@example.com", "errada")
+	if !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Errorf("Esperava-se ErrInvalidCredentials, obtido: %v", err)
+	}
+}
+
+func TestAuthenticateWithInvalidToken(t *testing.T) {
+	s, tokens := newLoggedInService(t)
+
+	_, _, err := s.Authenticate(tokens.AccessToken + "x")
+	if !errors.Is(err, service.ErrUnauthorized) {
+		t.Errorf("Esperava-se ErrUnauthorized para token adulterado, obtido: %v", err)
+	}
+
+	// Token assinado por outra instância com outra chave
+	other := NewTestService()
+	_, _, err = other.Authenticate(tokens.AccessToken)
+	if !errors.Is(err, service.ErrUnauthorized) {
+		t.Errorf("Esperava-se ErrUnauthorized para token de outra chave, obtido: %v", err)
+	}
+}
+
+func TestRefreshSessionRotatesToken(t *testing.T) {
+	s, tokens := newLoggedInService(t)
+
+	refreshed, err := s.RefreshSession(tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("Erro inesperado ao renovar a sessão: %v", err)
+	}
+	if refreshed.RefreshToken == tokens.RefreshToken {
+		t.Error("O refresh token não foi rotacionado")
+	}
+
+	// Reapresentar o refresh token antigo revoga a sessão inteira
+	_, err = s.RefreshSession(tokens.RefreshToken)
+	if !errors.Is(err, service.ErrUnauthorized) {
+		t.Errorf("Esperava-se ErrUnauthorized ao reutilizar refresh token, obtido: %v", err)
+	}
+	if _, err := s.RefreshSession(refreshed.RefreshToken); !errors.Is(err, service.ErrUnauthorized) {
+		t.Error("A sessão deveria ter sido revogada após a reutilização do refresh token")
+	}
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	s, tokens := newLoggedInService(t)
+
+	_, sessionID, err := s.Authenticate(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Erro inesperado ao autenticar: %v", err)
+	}
+
+	if err := s.Logout(sessionID); err != nil {
+		t.Fatalf("Erro inesperado no logout: %v", err)
+	}
+
+	if _, _, err := s.Authenticate(tokens.AccessToken); !errors.Is(err, service.ErrUnauthorized) {
+		t.Error("O access token deveria ser rejeitado após o logout")
+	}
+	if _, err := s.RefreshSession(tokens.RefreshToken); !errors.Is(err, service.ErrUnauthorized) {
+		t.Error("O refresh token deveria ser rejeitado após o logout")
+	}
+}
@@ -0,0 +1,147 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+func TestCreateCommentWithMentions(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+
+	comment, err := s.AsUser(member).CreateComment(taskID, service.Comment{
+		Text: "Pode revisar, @manager@example.com? Cc @viewer@example.com e @ninguem@example.com. Obrigado @manager@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Erro inesperado ao comentar: %v", err)
+	}
+
+	if comment.AuthorID != member.ID || comment.TaskID != taskID || comment.ID == 0 {
+		t.Errorf("Comentário criado não corresponde ao esperado: %+v", comment)
+	}
+	expected := []int{users[service.RoleManager].ID, users[service.RoleViewer].ID}
+	if len(comment.Mentions) != 2 || comment.Mentions[0] != expected[0] || comment.Mentions[1] != expected[1] {
+		t.Errorf("Menções esperadas %v, obtidas %v", expected, comment.Mentions)
+	}
+}
+
+func TestCreateCommentValidation(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	otherTask, _ := s.CreateTask(service.Task{Title: "Outra", Description: "Description"})
+	root, _ := s.AsUser(member).CreateComment(otherTask, service.Comment{Text: "Raiz"})
+
+	if _, err := s.AsUser(member).CreateComment(taskID, service.Comment{Text: "   "}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para comentário vazio, obtido: %v", err)
+	}
+	if _, err := s.AsUser(member).CreateComment(taskID, service.Comment{Text: "Resposta", ParentID: root.ID}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao responder comentário de outra tarefa, obtido: %v", err)
+	}
+	if _, err := s.AsUser(users[service.RoleViewer]).CreateComment(taskID, service.Comment{Text: "Oi"}); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para visualizador comentando, obtido: %v", err)
+	}
+	if _, err := s.AsUser(member).CreateComment(999, service.Comment{Text: "Oi"}); err == nil {
+		t.Error("Esperava-se um erro ao comentar em tarefa inexistente")
+	}
+}
+
+func TestRepliesAttachToThreadRoot(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	root, _ := s.AsUser(member).CreateComment(taskID, service.Comment{Text: "Raiz"})
+	reply, _ := s.AsUser(member).CreateComment(taskID, service.Comment{Text: "Resposta", ParentID: root.ID})
+	nested, err := s.AsUser(member).CreateComment(taskID, service.Comment{Text: "Resposta da resposta", ParentID: reply.ID})
+	if err != nil {
+		t.Fatalf("Erro inesperado ao responder: %v", err)
+	}
+
+	if reply.ParentID != root.ID || nested.ParentID != root.ID {
+		t.Errorf("Respostas deveriam apontar para a raiz %d, obtido %d e %d", root.ID, reply.ParentID, nested.ParentID)
+	}
+
+	// Excluir a raiz remove a conversa inteira
+	if err := s.AsUser(member).DeleteComment(taskID, root.ID); err != nil {
+		t.Fatalf("Erro inesperado ao excluir comentário: %v", err)
+	}
+	page, _ := s.ListComments(taskID, 0, 0)
+	if page.Total != 0 {
+		t.Errorf("A conversa deveria ter sido removida, restaram %d comentários", page.Total)
+	}
+}
+
+func TestEditCommentOnlyByAuthor(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	comment, _ := s.AsUser(member).CreateComment(taskID, service.Comment{Text: "Original"})
+
+	if _, err := s.AsUser(users[service.RoleAdmin]).EditComment(taskID, comment.ID, "Alterado"); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden ao editar comentário alheio, obtido: %v", err)
+	}
+
+	edited, err := s.AsUser(member).EditComment(taskID, comment.ID, "Alterado @viewer@example.com")
+	if err != nil {
+		t.Fatalf("Erro inesperado ao editar comentário: %v", err)
+	}
+	if edited.Text != "Alterado @viewer@example.com" || edited.EditedAt == nil || len(edited.Mentions) != 1 {
+		t.Errorf("Comentário editado não corresponde ao esperado: %+v", edited)
+	}
+
+	if _, err := s.AsUser(member).EditComment(taskID+1, comment.ID, "Outra tarefa"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound ao editar comentário pela tarefa errada, obtido: %v", err)
+	}
+}
+
+func TestDeleteCommentPermissions(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	first, _ := s.AsUser(users[service.RoleManager]).CreateComment(taskID, service.Comment{Text: "Do gerente"})
+	second, _ := s.AsUser(member).CreateComment(taskID, service.Comment{Text: "Do membro"})
+
+	if err := s.AsUser(member).DeleteComment(taskID, first.ID); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden ao membro excluir comentário alheio, obtido: %v", err)
+	}
+	if err := s.AsUser(users[service.RoleManager]).DeleteComment(taskID, second.ID); err != nil {
+		t.Errorf("Gerente deveria moderar comentários: %v", err)
+	}
+}
+
+func TestListCommentsPagination(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	for _, text := range []string{"um", "dois", "três", "quatro", "cinco"} {
+		s.AsUser(member).CreateComment(taskID, service.Comment{Text: text})
+	}
+
+	page, err := s.ListComments(taskID, 2, 2)
+	if err != nil {
+		t.Fatalf("Erro inesperado ao listar comentários: %v", err)
+	}
+	if page.Total != 5 || len(page.Comments) != 2 || page.Comments[0].Text != "três" || page.Comments[1].Text != "quatro" {
+		t.Errorf("Página de comentários não corresponde à esperada: %+v", page)
+	}
+
+	page, _ = s.ListComments(taskID, 1000, 0)
+	if page.Limit != service.MaxCommentPageSize || len(page.Comments) != 5 {
+		t.Errorf("O limite da página deveria ser %d, obtido: %d", service.MaxCommentPageSize, page.Limit)
+	}
+}
@@ -0,0 +1,1389 @@
+// Package conformance é a suíte de testes que toda implementação de service.Repository
+// precisa passar: o mock dos testes e os bancos suportados. Ela fixa o comportamento de
+// cada método, inclusive nos casos de registro inexistente (service.ErrNotFound) e de
+// duplicidade (service.ErrConflict), para que as implementações não divirjam.
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// Factory cria um repositório vazio para um teste.
+type Factory func(t *testing.T) service.Repository
+
+// missingID nunca é usado pelos registros criados na suíte.
+const missingID = 999999
+
+// Run executa a suíte completa contra os repositórios criados por newRepo.
+func Run(t *testing.T, newRepo Factory) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, newRepo) })
+	t.Run("Tasks", func(t *testing.T) { testTasks(t, newRepo) })
+	t.Run("Teams", func(t *testing.T) { testTeams(t, newRepo) })
+	t.Run("Comments", func(t *testing.T) { testComments(t, newRepo) })
+	t.Run("Notifications", func(t *testing.T) { testNotifications(t, newRepo) })
+	t.Run("Workflows", func(t *testing.T) { testWorkflows(t, newRepo) })
+	t.Run("Labels", func(t *testing.T) { testLabels(t, newRepo) })
+	t.Run("Links", func(t *testing.T) { testLinks(t, newRepo) })
+	t.Run("Attachments", func(t *testing.T) { testAttachments(t, newRepo) })
+	t.Run("TaskEvents", func(t *testing.T) { testTaskEvents(t, newRepo) })
+	t.Run("Trash", func(t *testing.T) { testTrash(t, newRepo) })
+	t.Run("Sessions", func(t *testing.T) { testSessions(t, newRepo) })
+	t.Run("Transactions", func(t *testing.T) { testTransactions(t, newRepo) })
+}
+
+func testUsers(t *testing.T, newRepo Factory) {
+	t.Run("AddUser and lookups round trip", func(t *testing.T) {
+		repo := newRepo(t)
+		want := service.User{Name: "Ana", Email: "ana@example.com", Password: "hash", Role: service.RoleManager}
+
+		id := mustAddUser(t, repo, want)
+		want.ID = id
+
+		got, err := repo.GetUserByID(id)
+		expectNoError(t, err, "GetUserByID")
+		expectEqual(t, got, want, "GetUserByID")
+
+		got, err = repo.GetUserByEmail(want.Email)
+		expectNoError(t, err, "GetUserByEmail")
+		expectEqual(t, got, want, "GetUserByEmail")
+	})
+
+	t.Run("unknown user is not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetUserByID(missingID)
+		expectError(t, err, service.ErrNotFound, "GetUserByID")
+		_, err = repo.GetUserByEmail("ninguem@example.com")
+		expectError(t, err, service.ErrNotFound, "GetUserByEmail")
+		expectError(t, repo.UpdateUserPassword(missingID, "hash"), service.ErrNotFound, "UpdateUserPassword")
+		expectError(t, repo.UpdateUserRole(missingID, service.RoleAdmin), service.ErrNotFound, "UpdateUserRole")
+		expectError(t, repo.RemoveUser(missingID), service.ErrNotFound, "RemoveUser")
+	})
+
+	t.Run("duplicate email is a conflict", func(t *testing.T) {
+		repo := newRepo(t)
+		mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+
+		_, err := repo.AddUser(service.User{Name: "Outra Ana", Email: "ana@example.com", Role: service.RoleMember})
+		expectError(t, err, service.ErrConflict, "AddUser")
+	})
+
+	t.Run("password and role updates", func(t *testing.T) {
+		repo := newRepo(t)
+		id := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Password: "antigo", Role: service.RoleMember})
+
+		expectNoError(t, repo.UpdateUserPassword(id, "novo"), "UpdateUserPassword")
+		expectNoError(t, repo.UpdateUserRole(id, service.RoleViewer), "UpdateUserRole")
+		// Gravar o mesmo valor de novo não é "registro inexistente"
+		expectNoError(t, repo.UpdateUserRole(id, service.RoleViewer), "UpdateUserRole sem mudança")
+
+		got, err := repo.GetUserByID(id)
+		expectNoError(t, err, "GetUserByID")
+		if got.Password != "novo" || got.Role != service.RoleViewer {
+			t.Errorf("usuário não atualizado: %+v", got)
+		}
+	})
+
+	t.Run("CountUsers", func(t *testing.T) {
+		repo := newRepo(t)
+		expectCount(t, repo, 0)
+		mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+		expectCount(t, repo, 2)
+	})
+
+	t.Run("RemoveUser removes what belongs to the user", func(t *testing.T) {
+		repo := newRepo(t)
+		id := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa", AssignedUsers: []int{id}})
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		expectNoError(t, repo.AddTeamMember(teamID, id, service.TeamRoleMember), "AddTeamMember")
+		expectNoError(t, repo.CreateSession(newSession("sessao", id)), "CreateSession")
+		mustCreateNotification(t, repo, id, taskID)
+
+		expectNoError(t, repo.RemoveUser(id), "RemoveUser")
+
+		_, err := repo.GetUserByID(id)
+		expectError(t, err, service.ErrNotFound, "GetUserByID")
+		expectIDs(t, taskAssignees(t, repo, taskID), nil, "atribuições")
+		expectIDs(t, memberIDs(t, repo, teamID), nil, "membros da equipe")
+		_, err = repo.GetSession("sessao")
+		expectError(t, err, service.ErrNotFound, "GetSession")
+		notifications, err := repo.ListNotifications(id, false)
+		expectNoError(t, err, "ListNotifications")
+		if len(notifications) != 0 {
+			t.Errorf("notificações do usuário removido continuam gravadas: %+v", notifications)
+		}
+
+		expectError(t, repo.RemoveUser(id), service.ErrNotFound, "RemoveUser de novo")
+	})
+}
+
+func testTasks(t *testing.T, newRepo Factory) {
+	t.Run("CreateTask and GetTaskByID round trip", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		bia := mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+		teamID := mustCreateTeam(t, repo, "Equipe")
+
+		start, due := now().AddDate(0, 0, -1), now().AddDate(0, 0, 7)
+		input := stamped(service.Task{Title: "Tarefa", Description: "Descrição", Priority: "Alta", Status: "Open", TeamID: teamID,
+			Type: "bug", AssignedUsers: []int{bia, ana}, StartDate: &start, DueDate: &due})
+		id := mustCreateTask(t, repo, input)
+
+		got, err := repo.GetTaskByID(id)
+		expectNoError(t, err, "GetTaskByID")
+		expectTask(t, got, id, input)
+		expectIDs(t, taskAssignees(t, repo, id), []int{ana, bia}, "GetTaskAssignees")
+	})
+
+	t.Run("unknown task is not found", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+
+		_, err := repo.GetTaskByID(missingID)
+		expectError(t, err, service.ErrNotFound, "GetTaskByID")
+		expectError(t, repo.UpdateTask(missingID, stamped(service.Task{Title: "x"})), service.ErrNotFound, "UpdateTask")
+		expectError(t, repo.DeleteTask(missingID), service.ErrNotFound, "DeleteTask")
+		expectError(t, repo.AssignTaskToUser(missingID, userID), service.ErrNotFound, "AssignTaskToUser")
+		expectIDs(t, taskAssignees(t, repo, missingID), nil, "GetTaskAssignees")
+	})
+
+	t.Run("CreateTask with unknown references stores nothing", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+
+		_, err := repo.CreateTask(stamped(service.Task{Title: "Tarefa", AssignedUsers: []int{userID, missingID}}))
+		expectError(t, err, service.ErrNotFound, "CreateTask com usuário inexistente")
+		_, err = repo.CreateTask(stamped(service.Task{Title: "Tarefa", TeamID: missingID}))
+		expectError(t, err, service.ErrNotFound, "CreateTask com equipe inexistente")
+
+		expectTaskIDs(t, allTasks(t, repo), nil, "GetAllTasks")
+		expectTaskIDs(t, tasksForUser(t, repo, userID), nil, "GetTasksForUser")
+	})
+
+	t.Run("AssignTaskToUser", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+
+		expectNoError(t, repo.AssignTaskToUser(taskID, userID), "AssignTaskToUser")
+		expectError(t, repo.AssignTaskToUser(taskID, userID), service.ErrConflict, "AssignTaskToUser duplicado")
+		expectError(t, repo.AssignTaskToUser(taskID, missingID), service.ErrNotFound, "AssignTaskToUser com usuário inexistente")
+		expectIDs(t, taskAssignees(t, repo, taskID), []int{userID}, "GetTaskAssignees")
+	})
+
+	t.Run("UnassignTaskFromUser and GetAssigneesForTasks", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		bia := mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+		first := mustCreateTask(t, repo, service.Task{Title: "Primeira", AssignedUsers: []int{bia, ana}})
+		second := mustCreateTask(t, repo, service.Task{Title: "Segunda", AssignedUsers: []int{ana}})
+		empty := mustCreateTask(t, repo, service.Task{Title: "Sem responsáveis"})
+
+		assignees, err := repo.GetAssigneesForTasks([]int{first, second, empty, missingID})
+		expectNoError(t, err, "GetAssigneesForTasks")
+		expectEqual(t, len(assignees), 2, "tarefas com responsáveis")
+		expectIDs(t, assignees[first], []int{ana, bia}, "GetAssigneesForTasks da primeira")
+		expectIDs(t, assignees[second], []int{ana}, "GetAssigneesForTasks da segunda")
+
+		expectNoError(t, repo.UnassignTaskFromUser(first, ana), "UnassignTaskFromUser")
+		expectError(t, repo.UnassignTaskFromUser(first, ana), service.ErrNotFound, "UnassignTaskFromUser repetido")
+		expectError(t, repo.UnassignTaskFromUser(empty, ana), service.ErrNotFound, "UnassignTaskFromUser sem atribuição")
+		expectIDs(t, taskAssignees(t, repo, first), []int{bia}, "GetTaskAssignees após retirar")
+		expectIDs(t, taskAssignees(t, repo, second), []int{ana}, "GetTaskAssignees da outra tarefa")
+
+		// Usuários na lixeira não aparecem entre os responsáveis
+		expectNoError(t, repo.TrashUser(bia, now()), "TrashUser")
+		assignees, err = repo.GetAssigneesForTasks([]int{first})
+		expectNoError(t, err, "GetAssigneesForTasks")
+		expectIDs(t, assignees[first], nil, "GetAssigneesForTasks com usuário na lixeira")
+		expectError(t, repo.UnassignTaskFromUser(first, bia), service.ErrNotFound, "UnassignTaskFromUser de usuário na lixeira")
+	})
+
+	t.Run("task listings are ordered by ID", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		bia := mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+
+		var ids []int
+		for i := 0; i < 5; i++ {
+			ids = append(ids, mustCreateTask(t, repo, service.Task{Title: fmt.Sprintf("Tarefa %d", i), AssignedUsers: []int{ana}}))
+		}
+		expectNoError(t, repo.AssignTaskToUser(ids[3], bia), "AssignTaskToUser")
+		expectNoError(t, repo.AssignTaskToUser(ids[1], bia), "AssignTaskToUser")
+
+		expectTaskIDs(t, allTasks(t, repo), ids, "GetAllTasks")
+		expectTaskIDs(t, tasksForUser(t, repo, ana), ids, "GetTasksForUser")
+		expectTaskIDs(t, tasksForUser(t, repo, bia), []int{ids[1], ids[3]}, "GetTasksForUser")
+		expectTaskIDs(t, tasksForUser(t, repo, missingID), nil, "GetTasksForUser de usuário inexistente")
+	})
+
+	t.Run("UpdateTask keeps the assignees", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		created := stamped(service.Task{Title: "Tarefa", Description: "Antes", AssignedUsers: []int{userID}})
+		id := mustCreateTask(t, repo, created)
+
+		due, completed := now().AddDate(0, 0, 3), now().Add(time.Hour)
+		updated := service.Task{ID: missingID, Title: "Nova", Description: "Depois", Priority: "Baixa", Status: "Closed", TeamID: teamID, Type: "feature",
+			DueDate: &due, CreatedAt: now().AddDate(-1, 0, 0), UpdatedAt: completed, CompletedAt: &completed, Resolution: "Corrigida", Version: 1}
+		expectNoError(t, repo.UpdateTask(id, updated), "UpdateTask")
+
+		got, err := repo.GetTaskByID(id)
+		expectNoError(t, err, "GetTaskByID")
+		// A data de criação não muda
+		updated.CreatedAt = created.CreatedAt
+		expectTask(t, got, id, updated)
+		expectEqual(t, got.Version, 2, "Version após UpdateTask")
+		expectIDs(t, taskAssignees(t, repo, id), []int{userID}, "GetTaskAssignees")
+
+		expectError(t, repo.UpdateTask(id, service.Task{Title: "Nova", TeamID: missingID}), service.ErrNotFound, "UpdateTask com equipe inexistente")
+	})
+
+	t.Run("UpdateTask requires the current version", func(t *testing.T) {
+		repo := newRepo(t)
+		id := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+
+		got, err := repo.GetTaskByID(id)
+		expectNoError(t, err, "GetTaskByID")
+		expectEqual(t, got.Version, 1, "Version da tarefa criada")
+
+		first, second := got, got
+		first.Title = "Primeira edição"
+		second.Title = "Segunda edição"
+		expectNoError(t, repo.UpdateTask(id, first), "UpdateTask")
+
+		// A segunda edição partiu da versão 1, que não é mais a atual
+		err = repo.UpdateTask(id, second)
+		expectError(t, err, service.ErrConflict, "UpdateTask com versão antiga")
+		var stale *service.VersionError
+		if errors.As(err, &stale) {
+			expectEqual(t, stale.Current, 2, "VersionError.Current")
+		}
+
+		got, err = repo.GetTaskByID(id)
+		expectNoError(t, err, "GetTaskByID")
+		expectEqual(t, got.Title, "Primeira edição", "Title após conflito")
+		expectEqual(t, got.Version, 2, "Version após conflito")
+	})
+
+	t.Run("GetOverdueTasks", func(t *testing.T) {
+		repo := newRepo(t)
+		reference := now()
+		due := func(days int) *time.Time {
+			d := reference.AddDate(0, 0, days)
+			return &d
+		}
+
+		mustCreateTask(t, repo, service.Task{Title: "Sem prazo"})
+		yesterday := mustCreateTask(t, repo, service.Task{Title: "Ontem", DueDate: due(-1)})
+		mustCreateTask(t, repo, service.Task{Title: "Amanhã", DueDate: due(1)})
+		lastWeek := mustCreateTask(t, repo, service.Task{Title: "Semana passada", DueDate: due(-7)})
+		completed := reference.Add(-time.Hour)
+		mustCreateTask(t, repo, service.Task{Title: "Concluída", DueDate: due(-3), CompletedAt: &completed})
+
+		tasks, err := repo.GetOverdueTasks(reference)
+		expectNoError(t, err, "GetOverdueTasks")
+		expectTaskIDs(t, tasks, []int{lastWeek, yesterday}, "GetOverdueTasks")
+	})
+
+	t.Run("DeleteTask removes assignments and comments", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		id := mustCreateTask(t, repo, service.Task{Title: "Tarefa", AssignedUsers: []int{userID}})
+		commentID := mustCreateComment(t, repo, service.Comment{TaskID: id, AuthorID: userID, Text: "Oi"})
+
+		expectNoError(t, repo.DeleteTask(id), "DeleteTask")
+
+		_, err := repo.GetTaskByID(id)
+		expectError(t, err, service.ErrNotFound, "GetTaskByID")
+		expectTaskIDs(t, tasksForUser(t, repo, userID), nil, "GetTasksForUser")
+		expectIDs(t, taskAssignees(t, repo, id), nil, "GetTaskAssignees")
+		_, err = repo.GetComment(commentID)
+		expectError(t, err, service.ErrNotFound, "GetComment")
+	})
+
+	t.Run("subtasks", func(t *testing.T) {
+		repo := newRepo(t)
+		parent := mustCreateTask(t, repo, service.Task{Title: "Pai"})
+		first := mustCreateTask(t, repo, service.Task{Title: "Primeira", ParentID: parent})
+		other := mustCreateTask(t, repo, service.Task{Title: "Outra"})
+		second := mustCreateTask(t, repo, service.Task{Title: "Segunda", ParentID: parent})
+
+		_, err := repo.CreateTask(stamped(service.Task{Title: "Órfã", ParentID: missingID}))
+		expectError(t, err, service.ErrNotFound, "CreateTask com tarefa pai inexistente")
+
+		got, err := repo.GetTaskByID(first)
+		expectNoError(t, err, "GetTaskByID")
+		expectEqual(t, got.ParentID, parent, "ParentID")
+
+		children, err := repo.GetTaskChildren(parent)
+		expectNoError(t, err, "GetTaskChildren")
+		expectTaskIDs(t, children, []int{first, second}, "GetTaskChildren")
+
+		// Mover a subtarefa para outra tarefa pai e depois para o primeiro nível
+		moved := got
+		moved.ParentID = other
+		expectNoError(t, repo.UpdateTask(first, moved), "UpdateTask")
+		children, err = repo.GetTaskChildren(other)
+		expectNoError(t, err, "GetTaskChildren")
+		expectTaskIDs(t, children, []int{first}, "GetTaskChildren após mover")
+		moved.ParentID = 0
+		moved.Version++
+		expectNoError(t, repo.UpdateTask(first, moved), "UpdateTask")
+		children, err = repo.GetTaskChildren(other)
+		expectNoError(t, err, "GetTaskChildren")
+		expectTaskIDs(t, children, nil, "GetTaskChildren após desvincular")
+		expectError(t, repo.UpdateTask(first, service.Task{Title: "Primeira", ParentID: missingID}), service.ErrNotFound, "UpdateTask com tarefa pai inexistente")
+
+		// Excluir a tarefa pai mantém as subtarefas, agora no primeiro nível
+		expectNoError(t, repo.DeleteTask(parent), "DeleteTask")
+		got, err = repo.GetTaskByID(second)
+		expectNoError(t, err, "GetTaskByID")
+		expectEqual(t, got.ParentID, 0, "ParentID após excluir a tarefa pai")
+	})
+}
+
+func testTeams(t *testing.T, newRepo Factory) {
+	t.Run("CreateTeam, GetTeamByID and UpdateTeam", func(t *testing.T) {
+		repo := newRepo(t)
+		id := mustCreateTeam(t, repo, "Equipe")
+
+		got, err := repo.GetTeamByID(id)
+		expectNoError(t, err, "GetTeamByID")
+		expectEqual(t, got, service.Team{ID: id, Name: "Equipe"}, "GetTeamByID")
+
+		expectNoError(t, repo.UpdateTeam(service.Team{ID: id, Name: "Renomeada", Archived: true}), "UpdateTeam")
+		got, err = repo.GetTeamByID(id)
+		expectNoError(t, err, "GetTeamByID")
+		expectEqual(t, got, service.Team{ID: id, Name: "Renomeada", Archived: true}, "GetTeamByID depois de UpdateTeam")
+	})
+
+	t.Run("unknown team is not found", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+
+		_, err := repo.GetTeamByID(missingID)
+		expectError(t, err, service.ErrNotFound, "GetTeamByID")
+		expectError(t, repo.UpdateTeam(service.Team{ID: missingID, Name: "x"}), service.ErrNotFound, "UpdateTeam")
+		expectError(t, repo.AddTeamMember(missingID, userID, service.TeamRoleMember), service.ErrNotFound, "AddTeamMember")
+		expectIDs(t, memberIDs(t, repo, missingID), nil, "GetTeamMembers")
+	})
+
+	t.Run("team listings are ordered by ID", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+
+		var ids []int
+		for i := 0; i < 4; i++ {
+			ids = append(ids, mustCreateTeam(t, repo, fmt.Sprintf("Equipe %d", i)))
+		}
+		expectNoError(t, repo.AddTeamMember(ids[2], userID, service.TeamRoleMember), "AddTeamMember")
+		expectNoError(t, repo.AddTeamMember(ids[0], userID, service.TeamRoleLead), "AddTeamMember")
+
+		teams, err := repo.GetAllTeams()
+		expectNoError(t, err, "GetAllTeams")
+		expectIDs(t, teamIDs(teams), ids, "GetAllTeams")
+
+		teams, err = repo.GetTeamsForUser(userID)
+		expectNoError(t, err, "GetTeamsForUser")
+		expectIDs(t, teamIDs(teams), []int{ids[0], ids[2]}, "GetTeamsForUser")
+	})
+
+	t.Run("team members", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		bia := mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+		teamID := mustCreateTeam(t, repo, "Equipe")
+
+		expectNoError(t, repo.AddTeamMember(teamID, bia, service.TeamRoleMember), "AddTeamMember")
+		expectNoError(t, repo.AddTeamMember(teamID, ana, service.TeamRoleLead), "AddTeamMember")
+		expectError(t, repo.AddTeamMember(teamID, ana, service.TeamRoleMember), service.ErrConflict, "AddTeamMember duplicado")
+		expectError(t, repo.AddTeamMember(teamID, missingID, service.TeamRoleMember), service.ErrNotFound, "AddTeamMember com usuário inexistente")
+
+		members, err := repo.GetTeamMembers(teamID)
+		expectNoError(t, err, "GetTeamMembers")
+		expectEqual(t, members, []service.TeamMember{{UserID: ana, Role: service.TeamRoleLead}, {UserID: bia, Role: service.TeamRoleMember}}, "GetTeamMembers")
+
+		expectNoError(t, repo.UpdateTeamMemberRole(teamID, bia, service.TeamRoleLead), "UpdateTeamMemberRole")
+		expectNoError(t, repo.RemoveTeamMember(teamID, ana), "RemoveTeamMember")
+		members, err = repo.GetTeamMembers(teamID)
+		expectNoError(t, err, "GetTeamMembers")
+		expectEqual(t, members, []service.TeamMember{{UserID: bia, Role: service.TeamRoleLead}}, "GetTeamMembers")
+
+		expectError(t, repo.UpdateTeamMemberRole(teamID, ana, service.TeamRoleLead), service.ErrNotFound, "UpdateTeamMemberRole de quem não é membro")
+		expectError(t, repo.RemoveTeamMember(teamID, ana), service.ErrNotFound, "RemoveTeamMember de quem não é membro")
+	})
+
+	t.Run("GetTasksForTeam", func(t *testing.T) {
+		repo := newRepo(t)
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		other := mustCreateTeam(t, repo, "Outra")
+
+		first := mustCreateTask(t, repo, service.Task{Title: "Primeira", TeamID: teamID})
+		mustCreateTask(t, repo, service.Task{Title: "Sem equipe"})
+		mustCreateTask(t, repo, service.Task{Title: "Outra equipe", TeamID: other})
+		second := mustCreateTask(t, repo, service.Task{Title: "Segunda", TeamID: teamID})
+
+		tasks, err := repo.GetTasksForTeam(teamID)
+		expectNoError(t, err, "GetTasksForTeam")
+		expectTaskIDs(t, tasks, []int{first, second}, "GetTasksForTeam")
+	})
+}
+
+func testComments(t *testing.T, newRepo Factory) {
+	t.Run("CreateComment and GetComment round trip", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		bia := mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+
+		root := service.Comment{TaskID: taskID, AuthorID: ana, Text: "Raiz", Mentions: []int{bia, ana}, CreatedAt: now()}
+		rootID := mustCreateComment(t, repo, root)
+		reply := service.Comment{TaskID: taskID, AuthorID: bia, ParentID: rootID, Text: "Resposta", CreatedAt: now()}
+		replyID := mustCreateComment(t, repo, reply)
+
+		got, err := repo.GetComment(rootID)
+		expectNoError(t, err, "GetComment")
+		root.ID, root.Mentions = rootID, []int{ana, bia}
+		expectComment(t, got, root)
+
+		got, err = repo.GetComment(replyID)
+		expectNoError(t, err, "GetComment")
+		reply.ID = replyID
+		expectComment(t, got, reply)
+	})
+
+	t.Run("unknown comment is not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.CreateComment(service.Comment{TaskID: missingID, Text: "Oi", CreatedAt: now()})
+		expectError(t, err, service.ErrNotFound, "CreateComment em tarefa inexistente")
+		_, err = repo.GetComment(missingID)
+		expectError(t, err, service.ErrNotFound, "GetComment")
+		expectError(t, repo.UpdateComment(service.Comment{ID: missingID, Text: "x"}), service.ErrNotFound, "UpdateComment")
+		expectError(t, repo.DeleteComment(missingID), service.ErrNotFound, "DeleteComment")
+	})
+
+	t.Run("UpdateComment replaces text and mentions", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		bia := mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+		comment := service.Comment{TaskID: taskID, AuthorID: ana, Text: "Antes", Mentions: []int{ana}, CreatedAt: now()}
+		comment.ID = mustCreateComment(t, repo, comment)
+
+		editedAt := now().Add(time.Minute)
+		comment.Text, comment.Mentions, comment.EditedAt = "Depois", []int{bia}, &editedAt
+		expectNoError(t, repo.UpdateComment(comment), "UpdateComment")
+
+		got, err := repo.GetComment(comment.ID)
+		expectNoError(t, err, "GetComment")
+		expectComment(t, got, comment)
+	})
+
+	t.Run("DeleteComment on a root removes the thread", func(t *testing.T) {
+		repo := newRepo(t)
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+		rootID := mustCreateComment(t, repo, service.Comment{TaskID: taskID, Text: "Raiz", CreatedAt: now()})
+		replyID := mustCreateComment(t, repo, service.Comment{TaskID: taskID, ParentID: rootID, Text: "Resposta", CreatedAt: now()})
+
+		expectNoError(t, repo.DeleteComment(rootID), "DeleteComment")
+		_, err := repo.GetComment(replyID)
+		expectError(t, err, service.ErrNotFound, "GetComment da resposta")
+	})
+
+	t.Run("ListComments pages in creation order", func(t *testing.T) {
+		repo := newRepo(t)
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+		other := mustCreateTask(t, repo, service.Task{Title: "Outra"})
+
+		var ids []int
+		for i := 0; i < 3; i++ {
+			ids = append(ids, mustCreateComment(t, repo, service.Comment{TaskID: taskID, Text: fmt.Sprintf("Comentário %d", i), CreatedAt: now()}))
+			mustCreateComment(t, repo, service.Comment{TaskID: other, Text: "Em outra tarefa", CreatedAt: now()})
+		}
+
+		comments, total, err := repo.ListComments(taskID, 2, 1)
+		expectNoError(t, err, "ListComments")
+		if total != 3 {
+			t.Errorf("ListComments: total esperado 3, obtido %d", total)
+		}
+		var got []int
+		for _, comment := range comments {
+			got = append(got, comment.ID)
+		}
+		expectIDs(t, got, ids[1:], "ListComments")
+	})
+}
+
+func testNotifications(t *testing.T, newRepo Factory) {
+	t.Run("CreateNotification and GetNotification round trip", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+
+		want := service.Notification{RecipientID: userID, Type: "assignment", Content: "Olá", TaskID: taskID, CreatedAt: now()}
+		id, err := repo.CreateNotification(want)
+		expectNoError(t, err, "CreateNotification")
+		want.ID = id
+
+		got, err := repo.GetNotification(id)
+		expectNoError(t, err, "GetNotification")
+		expectNotification(t, got, want)
+
+		_, err = repo.GetNotification(missingID)
+		expectError(t, err, service.ErrNotFound, "GetNotification")
+		expectError(t, repo.MarkNotificationRead(missingID), service.ErrNotFound, "MarkNotificationRead")
+	})
+
+	t.Run("inbox listing and read state", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		bia := mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+
+		first := mustCreateNotification(t, repo, ana, 0)
+		second := mustCreateNotification(t, repo, ana, 0)
+		third := mustCreateNotification(t, repo, ana, 0)
+		others := mustCreateNotification(t, repo, bia, 0)
+
+		expectIDs(t, notificationIDs(t, repo, ana, false), []int{third, second, first}, "ListNotifications")
+
+		expectNoError(t, repo.MarkNotificationRead(second), "MarkNotificationRead")
+		expectIDs(t, notificationIDs(t, repo, ana, true), []int{third, first}, "ListNotifications não lidas")
+
+		expectNoError(t, repo.MarkAllNotificationsRead(ana), "MarkAllNotificationsRead")
+		expectIDs(t, notificationIDs(t, repo, ana, true), nil, "ListNotifications não lidas")
+		expectIDs(t, notificationIDs(t, repo, bia, true), []int{others}, "ListNotifications de outro usuário")
+	})
+
+	t.Run("preferences", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+
+		prefs, err := repo.GetNotificationPreferences(userID)
+		expectNoError(t, err, "GetNotificationPreferences")
+		if len(prefs) != 0 {
+			t.Errorf("GetNotificationPreferences: esperava-se nenhuma preferência, obtido %v", prefs)
+		}
+
+		expectNoError(t, repo.SetNotificationPreference(userID, "comment", false), "SetNotificationPreference")
+		expectNoError(t, repo.SetNotificationPreference(userID, "mention", false), "SetNotificationPreference")
+		expectNoError(t, repo.SetNotificationPreference(userID, "mention", true), "SetNotificationPreference")
+
+		prefs, err = repo.GetNotificationPreferences(userID)
+		expectNoError(t, err, "GetNotificationPreferences")
+		expectEqual(t, prefs, map[service.NotificationType]bool{"comment": false, "mention": true}, "GetNotificationPreferences")
+	})
+}
+
+func testWorkflows(t *testing.T, newRepo Factory) {
+	t.Run("CreateWorkflow, GetWorkflow and UpdateWorkflow", func(t *testing.T) {
+		repo := newRepo(t)
+		want := service.DefaultWorkflow()
+		want.Name = "Bugs"
+
+		id, err := repo.CreateWorkflow(want)
+		expectNoError(t, err, "CreateWorkflow")
+		want.ID = id
+
+		got, err := repo.GetWorkflow(id)
+		expectNoError(t, err, "GetWorkflow")
+		expectEqual(t, got, want, "GetWorkflow")
+
+		want.Name = "Bugs de produção"
+		want.States = append(want.States, service.WorkflowState{Name: "Won't Fix", Completed: true})
+		want.Transitions = append(want.Transitions, service.WorkflowTransition{From: "Open", To: "Won't Fix", RequireComment: true})
+		expectNoError(t, repo.UpdateWorkflow(want), "UpdateWorkflow")
+		expectNoError(t, repo.UpdateWorkflow(want), "UpdateWorkflow sem mudança")
+
+		got, err = repo.GetWorkflow(id)
+		expectNoError(t, err, "GetWorkflow")
+		expectEqual(t, got, want, "GetWorkflow depois de UpdateWorkflow")
+
+		second, err := repo.CreateWorkflow(service.Workflow{Name: "Features", Initial: "Idea", States: []service.WorkflowState{{Name: "Idea"}}})
+		expectNoError(t, err, "CreateWorkflow")
+		workflows, err := repo.ListWorkflows()
+		expectNoError(t, err, "ListWorkflows")
+		expectIDs(t, workflowIDs(workflows), []int{id, second}, "ListWorkflows")
+	})
+
+	t.Run("unknown workflow is not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetWorkflow(missingID)
+		expectError(t, err, service.ErrNotFound, "GetWorkflow")
+		expectError(t, repo.UpdateWorkflow(service.Workflow{ID: missingID, Name: "Nenhum"}), service.ErrNotFound, "UpdateWorkflow")
+		expectError(t, repo.DeleteWorkflow(missingID), service.ErrNotFound, "DeleteWorkflow")
+		expectError(t, repo.SetWorkflowAssignment(service.WorkflowAssignment{WorkflowID: missingID}), service.ErrNotFound, "SetWorkflowAssignment")
+		expectError(t, repo.RemoveWorkflowAssignment(0, "bug"), service.ErrNotFound, "RemoveWorkflowAssignment")
+	})
+
+	t.Run("assignments are replaced per scope", func(t *testing.T) {
+		repo := newRepo(t)
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		bugs, _ := repo.CreateWorkflow(service.Workflow{Name: "Bugs", Initial: "Open", States: []service.WorkflowState{{Name: "Open"}}})
+		board, _ := repo.CreateWorkflow(service.Workflow{Name: "Quadro", Initial: "Todo", States: []service.WorkflowState{{Name: "Todo"}}})
+
+		expectNoError(t, repo.SetWorkflowAssignment(service.WorkflowAssignment{WorkflowID: bugs, TaskType: "bug"}), "SetWorkflowAssignment por tipo")
+		expectNoError(t, repo.SetWorkflowAssignment(service.WorkflowAssignment{WorkflowID: bugs, TeamID: teamID}), "SetWorkflowAssignment por equipe")
+		expectNoError(t, repo.SetWorkflowAssignment(service.WorkflowAssignment{WorkflowID: board, TeamID: teamID}), "SetWorkflowAssignment substituindo")
+		expectNoError(t, repo.SetWorkflowAssignment(service.WorkflowAssignment{WorkflowID: board, TeamID: teamID, TaskType: "bug"}), "SetWorkflowAssignment por equipe e tipo")
+		expectError(t, repo.SetWorkflowAssignment(service.WorkflowAssignment{WorkflowID: bugs, TeamID: missingID}), service.ErrNotFound, "SetWorkflowAssignment com equipe inexistente")
+
+		assignments, err := repo.ListWorkflowAssignments()
+		expectNoError(t, err, "ListWorkflowAssignments")
+		expectEqual(t, assignments, []service.WorkflowAssignment{
+			{WorkflowID: bugs, TaskType: "bug"},
+			{WorkflowID: board, TeamID: teamID},
+			{WorkflowID: board, TeamID: teamID, TaskType: "bug"},
+		}, "ListWorkflowAssignments")
+
+		expectNoError(t, repo.RemoveWorkflowAssignment(teamID, ""), "RemoveWorkflowAssignment")
+		expectError(t, repo.RemoveWorkflowAssignment(teamID, ""), service.ErrNotFound, "RemoveWorkflowAssignment repetido")
+
+		// Excluir o fluxo remove as atribuições que restaram dele
+		expectNoError(t, repo.DeleteWorkflow(board), "DeleteWorkflow")
+		assignments, err = repo.ListWorkflowAssignments()
+		expectNoError(t, err, "ListWorkflowAssignments")
+		expectEqual(t, assignments, []service.WorkflowAssignment{{WorkflowID: bugs, TaskType: "bug"}}, "ListWorkflowAssignments depois de DeleteWorkflow")
+	})
+}
+
+func testLabels(t *testing.T, newRepo Factory) {
+	t.Run("CreateLabel, GetLabel, UpdateLabel and ListLabels", func(t *testing.T) {
+		repo := newRepo(t)
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		otherTeam := mustCreateTeam(t, repo, "Outra")
+
+		want := service.Label{TeamID: teamID, Name: "frontend", Color: "#1e88e5"}
+		id, err := repo.CreateLabel(want)
+		expectNoError(t, err, "CreateLabel")
+		want.ID = id
+
+		got, err := repo.GetLabel(id)
+		expectNoError(t, err, "GetLabel")
+		expectEqual(t, got, want, "GetLabel")
+
+		want.Name, want.Color = "web", "#43a047"
+		expectNoError(t, repo.UpdateLabel(want), "UpdateLabel")
+		expectNoError(t, repo.UpdateLabel(want), "UpdateLabel sem mudança")
+		got, err = repo.GetLabel(id)
+		expectNoError(t, err, "GetLabel")
+		expectEqual(t, got, want, "GetLabel depois de UpdateLabel")
+
+		bug := service.Label{TeamID: teamID, Name: "bug", Color: "#e53935"}
+		bug.ID, err = repo.CreateLabel(bug)
+		expectNoError(t, err, "CreateLabel")
+		_, err = repo.CreateLabel(service.Label{TeamID: otherTeam, Name: "bug", Color: "#e53935"})
+		expectNoError(t, err, "CreateLabel com o mesmo nome em outra equipe")
+
+		labels, err := repo.ListLabels(teamID)
+		expectNoError(t, err, "ListLabels")
+		expectEqual(t, labels, []service.Label{bug, want}, "ListLabels")
+	})
+
+	t.Run("duplicate name in the team is a conflict", func(t *testing.T) {
+		repo := newRepo(t)
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		first, _ := repo.CreateLabel(service.Label{TeamID: teamID, Name: "bug", Color: "#e53935"})
+		second, _ := repo.CreateLabel(service.Label{TeamID: teamID, Name: "feature", Color: "#43a047"})
+
+		_, err := repo.CreateLabel(service.Label{TeamID: teamID, Name: "bug", Color: "#000000"})
+		expectError(t, err, service.ErrConflict, "CreateLabel repetido")
+		expectError(t, repo.UpdateLabel(service.Label{ID: second, TeamID: teamID, Name: "bug", Color: "#000000"}), service.ErrConflict, "UpdateLabel para nome repetido")
+
+		got, err := repo.GetLabel(first)
+		expectNoError(t, err, "GetLabel")
+		expectEqual(t, got.Color, "#e53935", "cor depois do conflito")
+	})
+
+	t.Run("unknown label is not found", func(t *testing.T) {
+		repo := newRepo(t)
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa", Description: "Descrição"})
+
+		_, err := repo.GetLabel(missingID)
+		expectError(t, err, service.ErrNotFound, "GetLabel")
+		_, err = repo.CreateLabel(service.Label{TeamID: missingID, Name: "bug", Color: "#e53935"})
+		expectError(t, err, service.ErrNotFound, "CreateLabel com equipe inexistente")
+		expectError(t, repo.UpdateLabel(service.Label{ID: missingID, Name: "bug", Color: "#e53935"}), service.ErrNotFound, "UpdateLabel")
+		expectError(t, repo.DeleteLabel(missingID), service.ErrNotFound, "DeleteLabel")
+		expectError(t, repo.AddTaskLabel(taskID, missingID), service.ErrNotFound, "AddTaskLabel com etiqueta inexistente")
+		expectError(t, repo.RemoveTaskLabel(taskID, missingID), service.ErrNotFound, "RemoveTaskLabel")
+	})
+
+	t.Run("task labels", func(t *testing.T) {
+		repo := newRepo(t)
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		bug := service.Label{TeamID: teamID, Name: "bug", Color: "#e53935"}
+		bug.ID, _ = repo.CreateLabel(bug)
+		api := service.Label{TeamID: teamID, Name: "api", Color: "#1e88e5"}
+		api.ID, _ = repo.CreateLabel(api)
+		first := mustCreateTask(t, repo, service.Task{Title: "Primeira", Description: "Descrição", TeamID: teamID})
+		second := mustCreateTask(t, repo, service.Task{Title: "Segunda", Description: "Descrição", TeamID: teamID})
+		third := mustCreateTask(t, repo, service.Task{Title: "Terceira", Description: "Descrição", TeamID: teamID})
+
+		expectNoError(t, repo.AddTaskLabel(first, bug.ID), "AddTaskLabel")
+		expectNoError(t, repo.AddTaskLabel(first, api.ID), "AddTaskLabel")
+		expectNoError(t, repo.AddTaskLabel(second, bug.ID), "AddTaskLabel")
+		expectError(t, repo.AddTaskLabel(first, bug.ID), service.ErrConflict, "AddTaskLabel repetido")
+		expectError(t, repo.AddTaskLabel(missingID, bug.ID), service.ErrNotFound, "AddTaskLabel com tarefa inexistente")
+
+		labels, err := repo.GetLabelsForTasks([]int{first, second, third})
+		expectNoError(t, err, "GetLabelsForTasks")
+		expectEqual(t, labels, map[int][]service.Label{first: {api, bug}, second: {bug}}, "GetLabelsForTasks")
+
+		expectNoError(t, repo.RemoveTaskLabel(first, api.ID), "RemoveTaskLabel")
+		expectError(t, repo.RemoveTaskLabel(first, api.ID), service.ErrNotFound, "RemoveTaskLabel repetido")
+
+		// Excluir a etiqueta ou a tarefa desfaz as associações
+		expectNoError(t, repo.DeleteLabel(bug.ID), "DeleteLabel")
+		expectNoError(t, repo.AddTaskLabel(second, api.ID), "AddTaskLabel")
+		expectNoError(t, repo.DeleteTask(second), "DeleteTask")
+		labels, err = repo.GetLabelsForTasks([]int{first, second})
+		expectNoError(t, err, "GetLabelsForTasks")
+		expectEqual(t, labels, map[int][]service.Label{}, "GetLabelsForTasks depois das exclusões")
+	})
+}
+
+func testLinks(t *testing.T, newRepo Factory) {
+	t.Run("AddTaskLink, GetTaskLinks and RemoveTaskLink", func(t *testing.T) {
+		repo := newRepo(t)
+		first := mustCreateTask(t, repo, service.Task{Title: "Primeira"})
+		second := mustCreateTask(t, repo, service.Task{Title: "Segunda"})
+		third := mustCreateTask(t, repo, service.Task{Title: "Terceira"})
+
+		expectNoError(t, repo.AddTaskLink(service.TaskLink{BlockerID: second, BlockedID: third}), "AddTaskLink")
+		expectNoError(t, repo.AddTaskLink(service.TaskLink{BlockerID: first, BlockedID: second}), "AddTaskLink")
+		expectError(t, repo.AddTaskLink(service.TaskLink{BlockerID: first, BlockedID: second}), service.ErrConflict, "AddTaskLink repetido")
+		expectError(t, repo.AddTaskLink(service.TaskLink{BlockerID: missingID, BlockedID: second}), service.ErrNotFound, "AddTaskLink com bloqueadora inexistente")
+		expectError(t, repo.AddTaskLink(service.TaskLink{BlockerID: first, BlockedID: missingID}), service.ErrNotFound, "AddTaskLink com bloqueada inexistente")
+
+		links, err := repo.GetTaskLinks(second)
+		expectNoError(t, err, "GetTaskLinks")
+		expectEqual(t, links, []service.TaskLink{{BlockerID: first, BlockedID: second}, {BlockerID: second, BlockedID: third}}, "GetTaskLinks")
+
+		expectNoError(t, repo.RemoveTaskLink(service.TaskLink{BlockerID: first, BlockedID: second}), "RemoveTaskLink")
+		expectError(t, repo.RemoveTaskLink(service.TaskLink{BlockerID: first, BlockedID: second}), service.ErrNotFound, "RemoveTaskLink repetido")
+		links, err = repo.GetTaskLinks(first)
+		expectNoError(t, err, "GetTaskLinks")
+		expectEqual(t, len(links), 0, "GetTaskLinks depois de RemoveTaskLink")
+	})
+
+	t.Run("GetOpenBlockers ignores completed blockers", func(t *testing.T) {
+		repo := newRepo(t)
+		completed := now()
+		open := mustCreateTask(t, repo, service.Task{Title: "Aberta"})
+		done := mustCreateTask(t, repo, service.Task{Title: "Concluída", CompletedAt: &completed})
+		other := mustCreateTask(t, repo, service.Task{Title: "Outra"})
+		blocked := mustCreateTask(t, repo, service.Task{Title: "Bloqueada"})
+		free := mustCreateTask(t, repo, service.Task{Title: "Livre"})
+
+		for _, blocker := range []int{other, done, open} {
+			expectNoError(t, repo.AddTaskLink(service.TaskLink{BlockerID: blocker, BlockedID: blocked}), "AddTaskLink")
+		}
+		expectNoError(t, repo.AddTaskLink(service.TaskLink{BlockerID: done, BlockedID: free}), "AddTaskLink")
+
+		blockers, err := repo.GetOpenBlockers([]int{blocked, free, missingID})
+		expectNoError(t, err, "GetOpenBlockers")
+		expectEqual(t, blockers, map[int][]int{blocked: {open, other}}, "GetOpenBlockers")
+	})
+
+	t.Run("DeleteTask removes its links", func(t *testing.T) {
+		repo := newRepo(t)
+		blocker := mustCreateTask(t, repo, service.Task{Title: "Bloqueadora"})
+		blocked := mustCreateTask(t, repo, service.Task{Title: "Bloqueada"})
+		expectNoError(t, repo.AddTaskLink(service.TaskLink{BlockerID: blocker, BlockedID: blocked}), "AddTaskLink")
+
+		expectNoError(t, repo.DeleteTask(blocker), "DeleteTask")
+		links, err := repo.GetTaskLinks(blocked)
+		expectNoError(t, err, "GetTaskLinks")
+		expectEqual(t, len(links), 0, "GetTaskLinks depois de DeleteTask")
+	})
+}
+
+func testAttachments(t *testing.T, newRepo Factory) {
+	t.Run("CreateAttachment, GetAttachment, ListAttachments and DeleteAttachment", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+		other := mustCreateTask(t, repo, service.Task{Title: "Outra"})
+
+		report := service.Attachment{TaskID: taskID, UploaderID: ana, Name: "relatório.pdf", ContentType: "application/pdf",
+			Size: 3 << 30, StorageKey: "tasks/1/a", CreatedAt: now()}
+		report.ID = mustCreateAttachment(t, repo, report)
+		photo := service.Attachment{TaskID: taskID, Name: "foto.png", ContentType: "image/png", Size: 10, StorageKey: "tasks/1/b", CreatedAt: now()}
+		photo.ID = mustCreateAttachment(t, repo, photo)
+		mustCreateAttachment(t, repo, service.Attachment{TaskID: other, Name: "outra.txt", ContentType: "text/plain", Size: 1, StorageKey: "tasks/2/c", CreatedAt: now()})
+
+		_, err := repo.CreateAttachment(service.Attachment{TaskID: taskID, Name: "repetido", ContentType: "text/plain", Size: 1, StorageKey: "tasks/1/a", CreatedAt: now()})
+		expectError(t, err, service.ErrConflict, "CreateAttachment com chave repetida")
+		_, err = repo.CreateAttachment(service.Attachment{TaskID: missingID, Name: "órfão", ContentType: "text/plain", Size: 1, StorageKey: "tasks/x/d", CreatedAt: now()})
+		expectError(t, err, service.ErrNotFound, "CreateAttachment com tarefa inexistente")
+
+		got, err := repo.GetAttachment(report.ID)
+		expectNoError(t, err, "GetAttachment")
+		expectAttachment(t, got, report)
+
+		attachments, err := repo.ListAttachments(taskID)
+		expectNoError(t, err, "ListAttachments")
+		expectEqual(t, len(attachments), 2, "ListAttachments")
+		if len(attachments) == 2 {
+			expectAttachment(t, attachments[0], report)
+			expectAttachment(t, attachments[1], photo)
+		}
+
+		expectNoError(t, repo.DeleteAttachment(report.ID), "DeleteAttachment")
+		expectError(t, repo.DeleteAttachment(report.ID), service.ErrNotFound, "DeleteAttachment repetido")
+		_, err = repo.GetAttachment(report.ID)
+		expectError(t, err, service.ErrNotFound, "GetAttachment depois de DeleteAttachment")
+	})
+
+	t.Run("DeleteTask and RemoveUser", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+		other := mustCreateTask(t, repo, service.Task{Title: "Outra"})
+		removed := mustCreateAttachment(t, repo, service.Attachment{TaskID: taskID, Name: "a.txt", ContentType: "text/plain", Size: 1, StorageKey: "a", CreatedAt: now()})
+		kept := mustCreateAttachment(t, repo, service.Attachment{TaskID: other, UploaderID: ana, Name: "b.txt", ContentType: "text/plain", Size: 1, StorageKey: "b", CreatedAt: now()})
+
+		expectNoError(t, repo.DeleteTask(taskID), "DeleteTask")
+		_, err := repo.GetAttachment(removed)
+		expectError(t, err, service.ErrNotFound, "GetAttachment depois de DeleteTask")
+
+		expectNoError(t, repo.RemoveUser(ana), "RemoveUser")
+		got, err := repo.GetAttachment(kept)
+		expectNoError(t, err, "GetAttachment depois de RemoveUser")
+		expectEqual(t, got.UploaderID, 0, "UploaderID depois de RemoveUser")
+	})
+}
+
+func testTaskEvents(t *testing.T, newRepo Factory) {
+	t.Run("CreateTaskEvent and ListTaskEvents round trip", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+		other := mustCreateTask(t, repo, service.Task{Title: "Outra"})
+
+		created := service.TaskEvent{TaskID: taskID, ActorID: ana, Kind: "created", CreatedAt: now(),
+			Changes: []service.FieldChange{{Field: "title", After: "Tarefa"}, {Field: "description", After: "Descrição com acentuação"}}}
+		created.ID = mustCreateTaskEvent(t, repo, created)
+		mustCreateTaskEvent(t, repo, service.TaskEvent{TaskID: other, Kind: "created", CreatedAt: now(), Changes: []service.FieldChange{{Field: "title", After: "Outra"}}})
+		updated := service.TaskEvent{TaskID: taskID, Kind: "updated", CreatedAt: now(),
+			Changes: []service.FieldChange{{Field: "status", Before: "Open", After: "Closed"}}}
+		updated.ID = mustCreateTaskEvent(t, repo, updated)
+
+		events, err := repo.ListTaskEvents(taskID)
+		expectNoError(t, err, "ListTaskEvents")
+		expectTaskEvents(t, events, []service.TaskEvent{created, updated})
+	})
+
+	t.Run("events outlive the task and the actor", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+		created := service.TaskEvent{TaskID: taskID, ActorID: ana, Kind: "created", CreatedAt: now(), Changes: []service.FieldChange{{Field: "title", After: "Tarefa"}}}
+		created.ID = mustCreateTaskEvent(t, repo, created)
+
+		expectNoError(t, repo.DeleteTask(taskID), "DeleteTask")
+		expectNoError(t, repo.RemoveUser(ana), "RemoveUser")
+		deleted := service.TaskEvent{TaskID: taskID, ActorID: ana, Kind: "deleted", CreatedAt: now(), Changes: []service.FieldChange{{Field: "title", Before: "Tarefa"}}}
+		deleted.ID = mustCreateTaskEvent(t, repo, deleted)
+
+		events, err := repo.ListTaskEvents(taskID)
+		expectNoError(t, err, "ListTaskEvents")
+		expectTaskEvents(t, events, []service.TaskEvent{created, deleted})
+	})
+}
+
+func testTrash(t *testing.T, newRepo Factory) {
+	t.Run("TrashTask hides the task until RestoreTask", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		parent := mustCreateTask(t, repo, service.Task{Title: "Pai"})
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa", TeamID: teamID, ParentID: parent, AssignedUsers: []int{ana}})
+		blocked := mustCreateTask(t, repo, service.Task{Title: "Bloqueada"})
+		expectNoError(t, repo.AddTaskLink(service.TaskLink{BlockerID: taskID, BlockedID: blocked}), "AddTaskLink")
+
+		at := now()
+		expectNoError(t, repo.TrashTask(taskID, at), "TrashTask")
+		expectError(t, repo.TrashTask(taskID, at), service.ErrNotFound, "TrashTask de novo")
+
+		_, err := repo.GetTaskByID(taskID)
+		expectError(t, err, service.ErrNotFound, "GetTaskByID na lixeira")
+		expectTaskIDs(t, allTasks(t, repo), []int{parent, blocked}, "GetAllTasks")
+		expectTaskIDs(t, tasksForUser(t, repo, ana), nil, "GetTasksForUser")
+		children, err := repo.GetTaskChildren(parent)
+		expectNoError(t, err, "GetTaskChildren")
+		expectTaskIDs(t, children, nil, "GetTaskChildren")
+		teamTasks, err := repo.GetTasksForTeam(teamID)
+		expectNoError(t, err, "GetTasksForTeam")
+		expectTaskIDs(t, teamTasks, nil, "GetTasksForTeam")
+		blockers, err := repo.GetOpenBlockers([]int{blocked})
+		expectNoError(t, err, "GetOpenBlockers")
+		expectEqual(t, len(blockers), 0, "GetOpenBlockers com a bloqueadora na lixeira")
+		expectError(t, repo.AssignTaskToUser(taskID, ana), service.ErrNotFound, "AssignTaskToUser na lixeira")
+		_, err = repo.CreateTask(stamped(service.Task{Title: "Filha", ParentID: taskID}))
+		expectError(t, err, service.ErrNotFound, "CreateTask abaixo de tarefa na lixeira")
+
+		// A dependência continua gravada, para voltar a valer com a tarefa
+		links, err := repo.GetTaskLinks(blocked)
+		expectNoError(t, err, "GetTaskLinks")
+		expectEqual(t, links, []service.TaskLink{{BlockerID: taskID, BlockedID: blocked}}, "GetTaskLinks")
+
+		trashed, err := repo.ListTrashedTasks()
+		expectNoError(t, err, "ListTrashedTasks")
+		expectTaskIDs(t, trashed, []int{taskID}, "ListTrashedTasks")
+		if len(trashed) == 1 && !equalTimes(trashed[0].DeletedAt, &at) {
+			t.Errorf("DeletedAt: esperado %v, obtido %v", at, trashed[0].DeletedAt)
+		}
+
+		expectNoError(t, repo.RestoreTask(taskID), "RestoreTask")
+		expectError(t, repo.RestoreTask(taskID), service.ErrNotFound, "RestoreTask fora da lixeira")
+		expectError(t, repo.RestoreTask(missingID), service.ErrNotFound, "RestoreTask inexistente")
+		got, err := repo.GetTaskByID(taskID)
+		expectNoError(t, err, "GetTaskByID depois de RestoreTask")
+		expectTask(t, got, taskID, stamped(service.Task{Title: "Tarefa", TeamID: teamID, ParentID: parent, CreatedAt: got.CreatedAt, UpdatedAt: got.UpdatedAt}))
+		if got.DeletedAt != nil {
+			t.Errorf("DeletedAt depois de RestoreTask: %v", got.DeletedAt)
+		}
+		expectIDs(t, taskAssignees(t, repo, taskID), []int{ana}, "GetTaskAssignees depois de RestoreTask")
+		trashed, err = repo.ListTrashedTasks()
+		expectNoError(t, err, "ListTrashedTasks")
+		expectTaskIDs(t, trashed, nil, "ListTrashedTasks depois de RestoreTask")
+	})
+
+	t.Run("DeleteTask removes a trashed task", func(t *testing.T) {
+		repo := newRepo(t)
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa"})
+		expectNoError(t, repo.TrashTask(taskID, now()), "TrashTask")
+
+		expectNoError(t, repo.DeleteTask(taskID), "DeleteTask")
+		expectError(t, repo.RestoreTask(taskID), service.ErrNotFound, "RestoreTask depois de DeleteTask")
+	})
+
+	t.Run("TrashUser hides the user until RestoreUser", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		bia := mustAddUser(t, repo, service.User{Name: "Bia", Email: "bia@example.com", Role: service.RoleMember})
+		teamID := mustCreateTeam(t, repo, "Equipe")
+		expectNoError(t, repo.AddTeamMember(teamID, ana, service.TeamRoleLead), "AddTeamMember")
+		taskID := mustCreateTask(t, repo, service.Task{Title: "Tarefa", AssignedUsers: []int{ana, bia}})
+
+		at := now()
+		expectNoError(t, repo.TrashUser(ana, at), "TrashUser")
+		expectError(t, repo.TrashUser(ana, at), service.ErrNotFound, "TrashUser de novo")
+
+		_, err := repo.GetUserByID(ana)
+		expectError(t, err, service.ErrNotFound, "GetUserByID na lixeira")
+		_, err = repo.GetUserByEmail("ana@example.com")
+		expectError(t, err, service.ErrNotFound, "GetUserByEmail na lixeira")
+		expectError(t, repo.UpdateUserRole(ana, service.RoleAdmin), service.ErrNotFound, "UpdateUserRole na lixeira")
+		expectIDs(t, taskAssignees(t, repo, taskID), []int{bia}, "GetTaskAssignees")
+		expectIDs(t, memberIDs(t, repo, teamID), nil, "GetTeamMembers")
+		expectError(t, repo.AssignTaskToUser(mustCreateTask(t, repo, service.Task{Title: "Outra"}), ana), service.ErrNotFound, "AssignTaskToUser na lixeira")
+		// O e-mail continua ocupado e o usuário continua contado
+		_, err = repo.AddUser(service.User{Name: "Outra Ana", Email: "ana@example.com", Role: service.RoleMember})
+		expectError(t, err, service.ErrConflict, "AddUser com e-mail de usuário na lixeira")
+		expectCount(t, repo, 2)
+
+		trashed, err := repo.ListTrashedUsers()
+		expectNoError(t, err, "ListTrashedUsers")
+		if len(trashed) != 1 || trashed[0].ID != ana || trashed[0].Email != "ana@example.com" || !equalTimes(trashed[0].DeletedAt, &at) {
+			t.Errorf("ListTrashedUsers: %+v", trashed)
+		}
+
+		expectNoError(t, repo.RestoreUser(ana), "RestoreUser")
+		expectError(t, repo.RestoreUser(ana), service.ErrNotFound, "RestoreUser fora da lixeira")
+		user, err := repo.GetUserByID(ana)
+		expectNoError(t, err, "GetUserByID depois de RestoreUser")
+		if user.DeletedAt != nil {
+			t.Errorf("DeletedAt depois de RestoreUser: %v", user.DeletedAt)
+		}
+		expectIDs(t, taskAssignees(t, repo, taskID), []int{ana, bia}, "GetTaskAssignees depois de RestoreUser")
+		expectIDs(t, memberIDs(t, repo, teamID), []int{ana}, "GetTeamMembers depois de RestoreUser")
+
+		expectNoError(t, repo.TrashUser(ana, now()), "TrashUser")
+		expectNoError(t, repo.RemoveUser(ana), "RemoveUser na lixeira")
+		trashed, err = repo.ListTrashedUsers()
+		expectNoError(t, err, "ListTrashedUsers")
+		expectEqual(t, len(trashed), 0, "ListTrashedUsers depois de RemoveUser")
+	})
+}
+
+func testSessions(t *testing.T, newRepo Factory) {
+	t.Run("CreateSession and GetSession round trip", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		session := newSession("sessao", userID)
+
+		expectNoError(t, repo.CreateSession(session), "CreateSession")
+		expectError(t, repo.CreateSession(session), service.ErrConflict, "CreateSession duplicada")
+
+		got, err := repo.GetSession(session.ID)
+		expectNoError(t, err, "GetSession")
+		expectSession(t, got, session)
+	})
+
+	t.Run("unknown session is not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetSession("inexistente")
+		expectError(t, err, service.ErrNotFound, "GetSession")
+		expectError(t, repo.UpdateSessionRefresh("inexistente", "hash", now()), service.ErrNotFound, "UpdateSessionRefresh")
+		expectError(t, repo.RevokeSession("inexistente", now()), service.ErrNotFound, "RevokeSession")
+	})
+
+	t.Run("refresh rotation and revocation", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := mustAddUser(t, repo, service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember})
+		session := newSession("sessao", userID)
+		expectNoError(t, repo.CreateSession(session), "CreateSession")
+
+		session.RefreshHash, session.ExpiresAt = "novo-hash", session.ExpiresAt.Add(time.Hour)
+		expectNoError(t, repo.UpdateSessionRefresh(session.ID, session.RefreshHash, session.ExpiresAt), "UpdateSessionRefresh")
+
+		revokedAt := now()
+		session.RevokedAt = &revokedAt
+		expectNoError(t, repo.RevokeSession(session.ID, revokedAt), "RevokeSession")
+		// Revogar de novo mantém o primeiro instante
+		expectNoError(t, repo.RevokeSession(session.ID, revokedAt.Add(time.Hour)), "RevokeSession de novo")
+
+		got, err := repo.GetSession(session.ID)
+		expectNoError(t, err, "GetSession")
+		expectSession(t, got, session)
+	})
+}
+
+func testTransactions(t *testing.T, newRepo Factory) {
+	t.Run("commit", func(t *testing.T) {
+		repo := newRepo(t)
+
+		var taskID int
+		err := repo.WithinTransaction(func(tx service.Repository) error {
+			var err error
+			taskID, err = tx.CreateTask(stamped(service.Task{Title: "Tarefa"}))
+			return err
+		})
+		expectNoError(t, err, "WithinTransaction")
+
+		_, err = repo.GetTaskByID(taskID)
+		expectNoError(t, err, "GetTaskByID depois do commit")
+	})
+
+	t.Run("rollback returns the error and discards every write", func(t *testing.T) {
+		repo := newRepo(t)
+		failure := errors.New("falha no meio da operação")
+
+		err := repo.WithinTransaction(func(tx service.Repository) error {
+			if _, err := tx.CreateTeam(service.Team{Name: "Equipe"}); err != nil {
+				return err
+			}
+			if _, err := tx.AddUser(service.User{Name: "Ana", Email: "ana@example.com", Role: service.RoleMember}); err != nil {
+				return err
+			}
+			if _, err := tx.CreateTask(stamped(service.Task{Title: "Tarefa"})); err != nil {
+				return err
+			}
+			return failure
+		})
+		expectError(t, err, failure, "WithinTransaction")
+
+		teams, err := repo.GetAllTeams()
+		expectNoError(t, err, "GetAllTeams")
+		expectIDs(t, teamIDs(teams), nil, "GetAllTeams depois do rollback")
+		expectTaskIDs(t, allTasks(t, repo), nil, "GetAllTasks depois do rollback")
+		expectCount(t, repo, 0)
+	})
+
+	t.Run("nested transactions join the outer one", func(t *testing.T) {
+		repo := newRepo(t)
+		failure := errors.New("falha externa")
+
+		err := repo.WithinTransaction(func(tx service.Repository) error {
+			err := tx.WithinTransaction(func(inner service.Repository) error {
+				_, err := inner.CreateTask(stamped(service.Task{Title: "Tarefa"}))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return failure
+		})
+		expectError(t, err, failure, "WithinTransaction")
+		expectTaskIDs(t, allTasks(t, repo), nil, "GetAllTasks depois do rollback")
+	})
+}
+
+// now retorna o instante atual em UTC, truncado ao segundo, a precisão comum a todos os bancos.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func newSession(id string, userID int) service.Session {
+	created := now()
+	return service.Session{ID: id, UserID: userID, RefreshHash: "hash", CreatedAt: created, ExpiresAt: created.Add(24 * time.Hour)}
+}
+
+func mustAddUser(t *testing.T, repo service.Repository, user service.User) int {
+	t.Helper()
+	id, err := repo.AddUser(user)
+	if err != nil || id <= 0 {
+		t.Fatalf("AddUser: id %d, erro %v", id, err)
+	}
+	return id
+}
+
+// stamped preenche as datas de criação e alteração que o serviço grava em toda tarefa.
+func stamped(task service.Task) service.Task {
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = now()
+	}
+	if task.UpdatedAt.IsZero() {
+		task.UpdatedAt = task.CreatedAt
+	}
+	return task
+}
+
+func mustCreateTask(t *testing.T, repo service.Repository, task service.Task) int {
+	t.Helper()
+	id, err := repo.CreateTask(stamped(task))
+	if err != nil || id <= 0 {
+		t.Fatalf("CreateTask: id %d, erro %v", id, err)
+	}
+	return id
+}
+
+func mustCreateTeam(t *testing.T, repo service.Repository, name string) int {
+	t.Helper()
+	id, err := repo.CreateTeam(service.Team{Name: name})
+	if err != nil || id <= 0 {
+		t.Fatalf("CreateTeam: id %d, erro %v", id, err)
+	}
+	return id
+}
+
+func mustCreateAttachment(t *testing.T, repo service.Repository, attachment service.Attachment) int {
+	t.Helper()
+	id, err := repo.CreateAttachment(attachment)
+	if err != nil || id <= 0 {
+		t.Fatalf("CreateAttachment: id %d, erro %v", id, err)
+	}
+	return id
+}
+
+func mustCreateTaskEvent(t *testing.T, repo service.Repository, event service.TaskEvent) int {
+	t.Helper()
+	id, err := repo.CreateTaskEvent(event)
+	if err != nil || id <= 0 {
+		t.Fatalf("CreateTaskEvent: id %d, erro %v", id, err)
+	}
+	return id
+}
+
+func mustCreateComment(t *testing.T, repo service.Repository, comment service.Comment) int {
+	t.Helper()
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = now()
+	}
+	id, err := repo.CreateComment(comment)
+	if err != nil || id <= 0 {
+		t.Fatalf("CreateComment: id %d, erro %v", id, err)
+	}
+	return id
+}
+
+func mustCreateNotification(t *testing.T, repo service.Repository, userID, taskID int) int {
+	t.Helper()
+	id, err := repo.CreateNotification(service.Notification{RecipientID: userID, Type: "assignment", Content: "Olá", TaskID: taskID, CreatedAt: now()})
+	if err != nil || id <= 0 {
+		t.Fatalf("CreateNotification: id %d, erro %v", id, err)
+	}
+	return id
+}
+
+func allTasks(t *testing.T, repo service.Repository) []service.Task {
+	t.Helper()
+	tasks, err := repo.GetAllTasks()
+	expectNoError(t, err, "GetAllTasks")
+	return tasks
+}
+
+func tasksForUser(t *testing.T, repo service.Repository, userID int) []service.Task {
+	t.Helper()
+	tasks, err := repo.GetTasksForUser(userID)
+	expectNoError(t, err, "GetTasksForUser")
+	return tasks
+}
+
+func taskAssignees(t *testing.T, repo service.Repository, taskID int) []int {
+	t.Helper()
+	ids, err := repo.GetTaskAssignees(taskID)
+	expectNoError(t, err, "GetTaskAssignees")
+	return ids
+}
+
+func memberIDs(t *testing.T, repo service.Repository, teamID int) []int {
+	t.Helper()
+	members, err := repo.GetTeamMembers(teamID)
+	expectNoError(t, err, "GetTeamMembers")
+	var ids []int
+	for _, member := range members {
+		ids = append(ids, member.UserID)
+	}
+	return ids
+}
+
+func notificationIDs(t *testing.T, repo service.Repository, userID int, unreadOnly bool) []int {
+	t.Helper()
+	notifications, err := repo.ListNotifications(userID, unreadOnly)
+	expectNoError(t, err, "ListNotifications")
+	var ids []int
+	for _, notification := range notifications {
+		ids = append(ids, notification.ID)
+	}
+	return ids
+}
+
+func workflowIDs(workflows []service.Workflow) []int {
+	var ids []int
+	for _, workflow := range workflows {
+		ids = append(ids, workflow.ID)
+	}
+	return ids
+}
+
+func teamIDs(teams []service.Team) []int {
+	var ids []int
+	for _, team := range teams {
+		ids = append(ids, team.ID)
+	}
+	return ids
+}
+
+func expectCount(t *testing.T, repo service.Repository, want int) {
+	t.Helper()
+	count, err := repo.CountUsers()
+	expectNoError(t, err, "CountUsers")
+	if count != want {
+		t.Errorf("CountUsers: esperado %d, obtido %d", want, count)
+	}
+}
+
+func expectNoError(t *testing.T, err error, what string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: erro inesperado: %v", what, err)
+	}
+}
+
+func expectError(t *testing.T, err, target error, what string) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("%s: esperado %v, obtido %v", what, target, err)
+	}
+}
+
+func expectEqual(t *testing.T, got, want any, what string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s:\nesperado %+v\nobtido   %+v", what, want, got)
+	}
+}
+
+// expectIDs compara listas de IDs; nil e lista vazia são equivalentes.
+func expectIDs(t *testing.T, got, want []int, what string) {
+	t.Helper()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	expectEqual(t, got, want, what)
+}
+
+func expectTaskIDs(t *testing.T, tasks []service.Task, want []int, what string) {
+	t.Helper()
+	var got []int
+	for _, task := range tasks {
+		got = append(got, task.ID)
+	}
+	expectIDs(t, got, want, what)
+}
+
+// expectTask compara os campos gravados de uma tarefa. As atribuições são verificadas
+// por GetTaskAssignees.
+func expectTask(t *testing.T, got service.Task, id int, want service.Task) {
+	t.Helper()
+	if got.ID != id || got.Title != want.Title || got.Description != want.Description ||
+		got.Priority != want.Priority || got.Status != want.Status || got.TeamID != want.TeamID || got.ParentID != want.ParentID || got.Resolution != want.Resolution || got.Type != want.Type ||
+		!equalTimes(got.StartDate, want.StartDate) || !equalTimes(got.DueDate, want.DueDate) ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) || !equalTimes(got.CompletedAt, want.CompletedAt) {
+		t.Errorf("tarefa %d:\nesperado %+v\nobtido   %+v", id, want, got)
+	}
+}
+
+func expectComment(t *testing.T, got, want service.Comment) {
+	t.Helper()
+	if got.ID != want.ID || got.TaskID != want.TaskID || got.AuthorID != want.AuthorID || got.ParentID != want.ParentID ||
+		got.Text != want.Text || !got.CreatedAt.Equal(want.CreatedAt) || !equalTimes(got.EditedAt, want.EditedAt) {
+		t.Errorf("comentário %d:\nesperado %+v\nobtido   %+v", want.ID, want, got)
+	}
+	expectIDs(t, got.Mentions, want.Mentions, "menções")
+}
+
+func expectAttachment(t *testing.T, got, want service.Attachment) {
+	t.Helper()
+	createdAt := got.CreatedAt
+	got.CreatedAt = want.CreatedAt
+	if !createdAt.Equal(want.CreatedAt) || got != want {
+		got.CreatedAt = createdAt
+		t.Errorf("anexo %d:\nesperado %+v\nobtido   %+v", want.ID, want, got)
+	}
+}
+
+func expectTaskEvents(t *testing.T, got, want []service.TaskEvent) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("eventos:\nesperado %+v\nobtido   %+v", want, got)
+	}
+	for i := range want {
+		if !got[i].CreatedAt.Equal(want[i].CreatedAt) {
+			t.Errorf("evento %d: data %v, esperada %v", want[i].ID, got[i].CreatedAt, want[i].CreatedAt)
+		}
+		got[i].CreatedAt = want[i].CreatedAt
+		expectEqual(t, got[i], want[i], fmt.Sprintf("evento %d", want[i].ID))
+	}
+}
+
+func expectNotification(t *testing.T, got, want service.Notification) {
+	t.Helper()
+	createdAt := got.CreatedAt
+	got.CreatedAt = want.CreatedAt
+	if !createdAt.Equal(want.CreatedAt) || got != want {
+		got.CreatedAt = createdAt
+		t.Errorf("notificação %d:\nesperado %+v\nobtido   %+v", want.ID, want, got)
+	}
+}
+
+func expectSession(t *testing.T, got, want service.Session) {
+	t.Helper()
+	if got.ID != want.ID || got.UserID != want.UserID || got.RefreshHash != want.RefreshHash ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.ExpiresAt.Equal(want.ExpiresAt) || !equalTimes(got.RevokedAt, want.RevokedAt) {
+		t.Errorf("sessão %s:\nesperado %+v\nobtido   %+v", want.ID, want, got)
+	}
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
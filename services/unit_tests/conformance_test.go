@@ -0,0 +1,27 @@
+package service_test
+
+import (
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/services/unit_tests/conformance"
+	"github.com/mclcavalcante/teamTask/services/unit_tests/mock"
+)
+
+func TestMockRepositoryConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) service.Repository {
+		return mock.NewTestRepository()
+	})
+}
+
+func TestSQLiteRepositoryConformance(t *testing.T) {
+	conformance.Run(t, newSQLiteRepository)
+}
+
+func TestMySQLRepositoryConformance(t *testing.T) {
+	conformance.Run(t, newMySQLRepository)
+}
+
+func TestPostgresRepositoryConformance(t *testing.T) {
+	conformance.Run(t, newPostgresRepository)
+}
@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// eventKinds resume o histórico nos tipos dos eventos, em ordem.
+func eventKinds(events []service.TaskEvent) []service.TaskEventKind {
+	kinds := make([]service.TaskEventKind, len(events))
+	for i, event := range events {
+		kinds[i] = event.Kind
+	}
+	return kinds
+}
+
+func TestTaskHistory(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	manager := s.AsUser(users[service.RoleManager])
+
+	taskID, err := manager.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição", Priority: "Alta"})
+	if err != nil {
+		t.Fatalf("Erro ao criar tarefa: %v", err)
+	}
+	if _, err := manager.EditTask(taskID, service.Task{Title: "Tarefa revisada", Description: "Descrição", Priority: "Low"}); err != nil {
+		t.Fatalf("Erro ao editar tarefa: %v", err)
+	}
+	// Uma edição sem mudanças não entra no histórico
+	if _, err := manager.EditTask(taskID, service.Task{Title: "Tarefa revisada", Description: "Descrição"}); err != nil {
+		t.Fatalf("Erro ao editar tarefa: %v", err)
+	}
+	if _, err := manager.TransitionTask(taskID, service.TaskTransition{To: "Closed"}); err != nil {
+		t.Fatalf("Erro ao concluir tarefa: %v", err)
+	}
+	if err := manager.AssignMemberToTask(taskID, users[service.RoleMember].ID); err != nil {
+		t.Fatalf("Erro ao atribuir tarefa: %v", err)
+	}
+
+	events, err := s.AsUser(users[service.RoleViewer]).GetTaskHistory(taskID)
+	if err != nil {
+		t.Fatalf("Erro ao obter o histórico: %v", err)
+	}
+	want := []service.TaskEventKind{service.TaskCreated, service.TaskUpdated, service.TaskUpdated, service.TaskAssigned}
+	if kinds := eventKinds(events); !slices.Equal(kinds, want) {
+		t.Fatalf("Eventos incorretos: %v", kinds)
+	}
+	for _, event := range events {
+		if event.ActorID != users[service.RoleManager].ID || event.TaskID != taskID || event.CreatedAt.IsZero() {
+			t.Errorf("Autor, tarefa ou data incorretos no evento: %+v", event)
+		}
+	}
+
+	edit := events[1].Changes
+	wantEdit := []service.FieldChange{{Field: "title", Before: "Tarefa", After: "Tarefa revisada"}, {Field: "priority", Before: "High", After: "Low"}}
+	if !slices.Equal(edit, wantEdit) {
+		t.Errorf("Mudanças da edição incorretas: %+v", edit)
+	}
+	if status := events[2].Changes[0]; status != (service.FieldChange{Field: "status", Before: "Open", After: "Closed"}) {
+		t.Errorf("Mudança de status incorreta: %+v", events[2].Changes)
+	}
+	if assigned := events[3].Changes; !slices.Equal(assigned, []service.FieldChange{{Field: "assignee", After: strconv.Itoa(users[service.RoleMember].ID)}}) {
+		t.Errorf("Atribuição incorreta: %+v", assigned)
+	}
+
+	if _, err := s.GetTaskHistory(999); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para tarefa inexistente, obtido %v", err)
+	}
+}
+
+func TestTaskHistoryOutlivesDeletion(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+	s.AssignMemberToTask(taskID, member.ID)
+	if err := s.DeleteUser(member.ID); err != nil {
+		t.Fatalf("Erro ao excluir usuário: %v", err)
+	}
+	if err := s.DeleteTask(taskID); err != nil {
+		t.Fatalf("Erro ao excluir tarefa: %v", err)
+	}
+
+	events, err := s.GetTaskHistory(taskID)
+	if err != nil {
+		t.Fatalf("O histórico da tarefa excluída deveria continuar disponível: %v", err)
+	}
+	want := []service.TaskEventKind{service.TaskCreated, service.TaskAssigned, service.TaskUnassigned, service.TaskDeleted}
+	if kinds := eventKinds(events); !slices.Equal(kinds, want) {
+		t.Fatalf("Eventos incorretos: %v", kinds)
+	}
+
+	deleted := events[3].Changes
+	if !slices.Contains(deleted, service.FieldChange{Field: "title", Before: "Tarefa"}) || !slices.Contains(deleted, service.FieldChange{Field: "status", Before: "Open"}) {
+		t.Errorf("A exclusão deveria guardar os valores da tarefa: %+v", deleted)
+	}
+	for _, change := range deleted {
+		if change.After != "" {
+			t.Errorf("A exclusão não deveria ter valores depois: %+v", change)
+		}
+	}
+}
+
+func TestTaskHistoryRecordsLinksAndAttachments(t *testing.T) {
+	s, _ := newAttachmentService(t, service.DefaultAttachmentPolicy())
+	blocker, _ := s.CreateTask(service.Task{Title: "Bloqueadora", Description: "Descrição"})
+	blocked, _ := s.CreateTask(service.Task{Title: "Bloqueada", Description: "Descrição"})
+
+	if err := s.AddTaskLink(blocker, blocked); err != nil {
+		t.Fatalf("Erro ao criar a dependência: %v", err)
+	}
+	if err := s.RemoveTaskLink(blocker, blocked); err != nil {
+		t.Fatalf("Erro ao desfazer a dependência: %v", err)
+	}
+	attachment, err := s.AddAttachment(blocked, upload("foto.png", pngHeader))
+	if err != nil {
+		t.Fatalf("Erro ao anexar o arquivo: %v", err)
+	}
+	if err := s.DeleteAttachment(blocked, attachment.ID); err != nil {
+		t.Fatalf("Erro ao excluir o anexo: %v", err)
+	}
+
+	// A dependência aparece no histórico das duas tarefas
+	events, _ := s.GetTaskHistory(blocker)
+	if kinds := eventKinds(events); !slices.Equal(kinds, []service.TaskEventKind{service.TaskCreated, service.TaskLinked, service.TaskUnlinked}) {
+		t.Fatalf("Eventos incorretos na bloqueadora: %v", kinds)
+	}
+	if linked := events[1].Changes; !slices.Equal(linked, []service.FieldChange{{Field: "blocks", After: strconv.Itoa(blocked)}}) {
+		t.Errorf("Dependência incorreta na bloqueadora: %+v", linked)
+	}
+
+	events, _ = s.GetTaskHistory(blocked)
+	want := []service.TaskEventKind{service.TaskCreated, service.TaskLinked, service.TaskUnlinked, service.TaskAttached, service.TaskDetached}
+	if kinds := eventKinds(events); !slices.Equal(kinds, want) {
+		t.Fatalf("Eventos incorretos na bloqueada: %v", kinds)
+	}
+	if unlinked := events[2].Changes; !slices.Equal(unlinked, []service.FieldChange{{Field: "blockedBy", Before: strconv.Itoa(blocker)}}) {
+		t.Errorf("Dependência desfeita incorreta: %+v", unlinked)
+	}
+	if attached := events[3].Changes; !slices.Equal(attached, []service.FieldChange{{Field: "attachment", After: "foto.png"}}) {
+		t.Errorf("Anexo incorreto: %+v", attached)
+	}
+	if detached := events[4].Changes; !slices.Equal(detached, []service.FieldChange{{Field: "attachment", Before: "foto.png"}}) {
+		t.Errorf("Exclusão do anexo incorreta: %+v", detached)
+	}
+
+	// Uma dependência recusada não deixa evento
+	if err := s.AddTaskLink(blocked, blocked); !errors.Is(err, service.ErrValidation) {
+		t.Fatalf("Esperava-se ErrValidation, obtido %v", err)
+	}
+	if events, _ := s.GetTaskHistory(blocked); len(events) != len(want) {
+		t.Errorf("A dependência recusada não deveria entrar no histórico: %v", eventKinds(events))
+	}
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+func TestTeamLabels(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	own, _ := s.CreateTeam(service.Team{Name: "Própria"})
+	other, _ := s.CreateTeam(service.Team{Name: "Outra"})
+	s.AddTeamMember(own, member.ID, service.TeamRoleLead)
+
+	labelID, err := s.AsUser(member).CreateLabel(service.Label{TeamID: own, Name: " Bug ", Color: "#E53935"})
+	if err != nil {
+		t.Fatalf("Líder deveria criar etiquetas na própria equipe: %v", err)
+	}
+	if _, err := s.AsUser(member).CreateLabel(service.Label{TeamID: other, Name: "bug"}); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden ao criar etiqueta em equipe alheia, obtido %v", err)
+	}
+	if _, err := s.CreateLabel(service.Label{TeamID: own, Name: "BUG"}); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict para nome repetido na equipe, obtido %v", err)
+	}
+	if _, err := s.CreateLabel(service.Label{TeamID: own, Name: "api", Color: "azul"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para cor inválida, obtido %v", err)
+	}
+
+	apiID, err := s.CreateLabel(service.Label{TeamID: own, Name: "api"})
+	if err != nil {
+		t.Fatalf("Erro ao criar etiqueta: %v", err)
+	}
+
+	labels, err := s.AsUser(users[service.RoleViewer]).ListTeamLabels(own)
+	if err != nil {
+		t.Fatalf("Erro ao listar etiquetas: %v", err)
+	}
+	want := []service.Label{
+		{ID: apiID, TeamID: own, Name: "api", Color: "#9e9e9e"},
+		{ID: labelID, TeamID: own, Name: "Bug", Color: "#e53935"},
+	}
+	if len(labels) != 2 || labels[0] != want[0] || labels[1] != want[1] {
+		t.Errorf("Etiquetas da equipe não correspondem às esperadas: %+v", labels)
+	}
+
+	if err := s.AsUser(member).UpdateLabel(apiID, service.Label{Name: "bug"}); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict ao renomear para um nome em uso, obtido %v", err)
+	}
+	if err := s.AsUser(member).UpdateLabel(apiID, service.Label{Name: "backend", Color: "#1e88e5"}); err != nil {
+		t.Errorf("Erro ao alterar etiqueta: %v", err)
+	}
+	if err := s.AsUser(member).DeleteLabel(apiID); err != nil {
+		t.Errorf("Erro ao excluir etiqueta: %v", err)
+	}
+	if err := s.DeleteLabel(apiID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para etiqueta excluída, obtido %v", err)
+	}
+}
+
+func TestTaskLabelsAndFiltering(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+
+	teamID, _ := s.CreateTeam(service.Team{Name: "Equipe"})
+	otherTeam, _ := s.CreateTeam(service.Team{Name: "Outra"})
+	bug, _ := s.CreateLabel(service.Label{TeamID: teamID, Name: "bug"})
+	api, _ := s.CreateLabel(service.Label{TeamID: teamID, Name: "api"})
+	foreign, _ := s.CreateLabel(service.Label{TeamID: otherTeam, Name: "ux"})
+
+	first, _ := s.CreateTask(service.Task{Title: "Primeira", Description: "Descrição", TeamID: teamID})
+	second, _ := s.CreateTask(service.Task{Title: "Segunda", Description: "Descrição", TeamID: teamID})
+	third, _ := s.CreateTask(service.Task{Title: "Terceira", Description: "Descrição", TeamID: teamID})
+
+	if err := s.AsUser(users[service.RoleViewer]).AddTaskLabel(first, bug); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para leitor, obtido %v", err)
+	}
+	if err := s.AddTaskLabel(first, foreign); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para etiqueta de outra equipe, obtido %v", err)
+	}
+	for _, pair := range [][2]int{{first, bug}, {first, api}, {second, bug}, {third, api}} {
+		if err := s.AddTaskLabel(pair[0], pair[1]); err != nil {
+			t.Fatalf("Erro ao etiquetar tarefa: %v", err)
+		}
+	}
+	if err := s.AddTaskLabel(first, bug); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict para etiqueta já aplicada, obtido %v", err)
+	}
+
+	task, _ := s.GetTaskByID(first)
+	if len(task.Labels) != 2 || task.Labels[0].ID != api || task.Labels[1].ID != bug {
+		t.Errorf("Etiquetas da tarefa não correspondem às esperadas: %+v", task.Labels)
+	}
+
+	matchAny, err := s.FilterTasks(service.TaskFilter{Labels: []int{bug, api}})
+	if err != nil {
+		t.Fatalf("Erro ao filtrar tarefas: %v", err)
+	}
+	if ids := taskIDs(matchAny); !slices.Equal(ids, []int{first, second, third}) {
+		t.Errorf("Tarefas com qualquer das etiquetas incorretas: %v", ids)
+	}
+
+	all, _ := s.FilterTasks(service.TaskFilter{Labels: []int{bug, api}, MatchAllLabels: true})
+	if ids := taskIDs(all); !slices.Equal(ids, []int{first}) {
+		t.Errorf("Tarefas com todas as etiquetas incorretas: %v", ids)
+	}
+
+	if err := s.RemoveTaskLabel(first, api); err != nil {
+		t.Fatalf("Erro ao retirar etiqueta: %v", err)
+	}
+	if err := s.RemoveTaskLabel(first, api); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para etiqueta não aplicada, obtido %v", err)
+	}
+	all, _ = s.FilterTasks(service.TaskFilter{Labels: []int{bug, api}, MatchAllLabels: true})
+	if len(all) != 0 {
+		t.Errorf("Nenhuma tarefa deveria ter todas as etiquetas, obtido %v", taskIDs(all))
+	}
+
+	// Ao mudar de equipe a tarefa perde as etiquetas da equipe anterior
+	if _, err := s.EditTask(second, service.Task{Title: "Segunda", Description: "Descrição", TeamID: otherTeam}); err != nil {
+		t.Fatalf("Erro ao mudar a tarefa de equipe: %v", err)
+	}
+	task, _ = s.GetTaskByID(second)
+	if len(task.Labels) != 0 {
+		t.Errorf("A tarefa não deveria manter etiquetas da equipe anterior: %+v", task.Labels)
+	}
+}
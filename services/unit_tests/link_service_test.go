@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/services/unit_tests/mock"
+	"go.uber.org/zap"
+)
+
+func TestTaskLinks(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+
+	first, _ := s.CreateTask(service.Task{Title: "Primeira", Description: "Descrição"})
+	second, _ := s.CreateTask(service.Task{Title: "Segunda", Description: "Descrição"})
+	third, _ := s.CreateTask(service.Task{Title: "Terceira", Description: "Descrição"})
+
+	if err := s.AsUser(users[service.RoleViewer]).AddTaskLink(first, second); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para leitor, obtido %v", err)
+	}
+	if err := s.AddTaskLink(first, first); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para a tarefa bloqueando a si mesma, obtido %v", err)
+	}
+	if err := s.AddTaskLink(first, 999); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para tarefa inexistente, obtido %v", err)
+	}
+
+	if err := s.AddTaskLink(first, second); err != nil {
+		t.Fatalf("Erro ao criar dependência: %v", err)
+	}
+	if err := s.AddTaskLink(second, third); err != nil {
+		t.Fatalf("Erro ao criar dependência: %v", err)
+	}
+	if err := s.AddTaskLink(first, second); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict para dependência repetida, obtido %v", err)
+	}
+	if err := s.AddTaskLink(third, first); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para dependência em ciclo, obtido %v", err)
+	}
+
+	links, err := s.GetTaskLinks(second)
+	if err != nil {
+		t.Fatalf("Erro ao listar dependências: %v", err)
+	}
+	want := []service.TaskLink{{BlockerID: first, BlockedID: second}, {BlockerID: second, BlockedID: third}}
+	if !slices.Equal(links, want) {
+		t.Errorf("Dependências incorretas: %+v", links)
+	}
+
+	task, _ := s.GetTaskByID(second)
+	if !task.Blocked || !slices.Equal(task.BlockedBy, []int{first}) {
+		t.Errorf("A tarefa deveria estar bloqueada pela primeira: %+v", task)
+	}
+	if task, _ := s.GetTaskByID(first); task.Blocked {
+		t.Errorf("A primeira tarefa não deveria estar bloqueada")
+	}
+
+	if err := s.RemoveTaskLink(first, second); err != nil {
+		t.Fatalf("Erro ao remover dependência: %v", err)
+	}
+	if err := s.RemoveTaskLink(first, second); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para dependência inexistente, obtido %v", err)
+	}
+	if task, _ := s.GetTaskByID(second); task.Blocked {
+		t.Errorf("A tarefa não deveria mais estar bloqueada")
+	}
+}
+
+func TestBlockedTaskCannotStart(t *testing.T) {
+	s := NewTestService()
+
+	blocker, _ := s.CreateTask(service.Task{Title: "Bloqueadora", Description: "Descrição"})
+	blocked, _ := s.CreateTask(service.Task{Title: "Bloqueada", Description: "Descrição"})
+	if err := s.AddTaskLink(blocker, blocked); err != nil {
+		t.Fatalf("Erro ao criar dependência: %v", err)
+	}
+
+	if _, err := s.TransitionTask(blocked, service.TaskTransition{To: "In Progress"}); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict ao iniciar tarefa bloqueada, obtido %v", err)
+	}
+	if _, err := s.EditTask(blocked, service.Task{Title: "Bloqueada", Description: "Descrição", Status: "In Progress"}); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict ao iniciar pela edição, obtido %v", err)
+	}
+	// Concluir não é iniciar
+	if _, err := s.TransitionTask(blocked, service.TaskTransition{To: "Closed"}); err != nil {
+		t.Errorf("A tarefa bloqueada deveria poder ser fechada: %v", err)
+	}
+
+	other, _ := s.CreateTask(service.Task{Title: "Outra", Description: "Descrição"})
+	s.AddTaskLink(blocker, other)
+	if _, err := s.TransitionTask(blocker, service.TaskTransition{To: "Closed"}); err != nil {
+		t.Fatalf("Erro ao concluir a bloqueadora: %v", err)
+	}
+	task, err := s.TransitionTask(other, service.TaskTransition{To: "In Progress"})
+	if err != nil {
+		t.Errorf("Com a bloqueadora concluída a tarefa deveria iniciar: %v", err)
+	}
+	if task.Blocked {
+		t.Errorf("A tarefa não deveria estar bloqueada depois que a bloqueadora foi concluída")
+	}
+
+	warn := service.NewService(mock.NewTestRepository(), zap.NewNop(), service.WithHashParams(testHashParams),
+		service.WithLinkPolicy(service.LinkPolicy{RefuseBlockedStart: false}))
+	blocker, _ = warn.CreateTask(service.Task{Title: "Bloqueadora", Description: "Descrição"})
+	blocked, _ = warn.CreateTask(service.Task{Title: "Bloqueada", Description: "Descrição"})
+	warn.AddTaskLink(blocker, blocked)
+	task, err = warn.TransitionTask(blocked, service.TaskTransition{To: "In Progress"})
+	if err != nil {
+		t.Errorf("Sem a recusa a tarefa bloqueada deveria iniciar: %v", err)
+	}
+	if !task.Blocked {
+		t.Errorf("A resposta deveria indicar que a tarefa está bloqueada")
+	}
+}
@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mclcavalcante/teamTask/migrations"
+	"github.com/mclcavalcante/teamTask/repository"
+	service "github.com/mclcavalcante/teamTask/services"
+	"go.uber.org/zap"
+)
+
+func TestLoadMigrationsOrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mysql/0002_segunda.up.sql":   {Data: []byte("ALTER TABLE a ADD b INT;")},
+		"mysql/0001_init.up.sql":      {Data: []byte("CREATE TABLE a (id INT);")},
+		"mysql/0001_init.down.sql":    {Data: []byte("DROP TABLE a;")},
+		"mysql/README.md":             {Data: []byte("ignorado")},
+		"mysql/0002_segunda.down.sql": {Data: []byte("ALTER TABLE a DROP b;")},
+	}
+
+	loaded, err := migrations.Load(fsys, "mysql")
+	if err != nil {
+		t.Fatalf("Erro ao carregar migrações: %v", err)
+	}
+
+	if len(loaded) != 2 || loaded[0].Version != 1 || loaded[1].Version != 2 {
+		t.Fatalf("Migrações fora de ordem: %+v", loaded)
+	}
+	if loaded[0].Name != "init" || loaded[0].Down != "DROP TABLE a;" {
+		t.Errorf("Migração 1 carregada incorretamente: %+v", loaded[0])
+	}
+}
+
+func TestLoadMigrationsRequiresUpScript(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mysql/0001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+	}
+
+	if _, err := migrations.Load(fsys, "mysql"); err == nil {
+		t.Error("Esperava-se um erro para migração sem arquivo .up.sql")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- comentário; com ponto e vírgula
+CREATE TABLE a (id INT);
+INSERT INTO a (nome) VALUES ('x;y');
+
+`
+
+	statements := migrations.SplitStatements(script)
+	if len(statements) != 2 {
+		t.Fatalf("Esperava-se 2 comandos, obtido %d: %q", len(statements), statements)
+	}
+	if statements[1] != "INSERT INTO a (nome) VALUES ('x;y')" {
+		t.Errorf("Comando dividido incorretamente: %q", statements[1])
+	}
+}
+
+// legacySchema é o esquema do antigo sql_scripts/ddl.sql, com os tipos do SQLite.
+const legacySchema = `
+CREATE TABLE User (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255), email VARCHAR(100), password VARCHAR(100));
+CREATE TABLE Tasks (id INTEGER PRIMARY KEY AUTOINCREMENT, title VARCHAR(255), description TEXT, status VARCHAR(50), priority VARCHAR(50));
+CREATE TABLE Equipe (equipe_id INTEGER PRIMARY KEY AUTOINCREMENT);
+CREATE TABLE Comentario (comentario_id INTEGER PRIMARY KEY AUTOINCREMENT, texto TEXT, tarefa_id INT, FOREIGN KEY (tarefa_id) REFERENCES Tasks(id));
+CREATE TABLE Notificacao (notificacao_id INTEGER PRIMARY KEY AUTOINCREMENT, tipo VARCHAR(50), conteudo TEXT, destinatario_id INT, FOREIGN KEY (destinatario_id) REFERENCES User(id));
+CREATE TABLE Task_user_associations (user_id INT, task_id INT, PRIMARY KEY (user_id, task_id), FOREIGN KEY (user_id) REFERENCES User(id), FOREIGN KEY (task_id) REFERENCES Tasks(id));
+INSERT INTO User (name, email, password) VALUES ('Ana', 'ana@example.com', 'segredo');
+INSERT INTO Tasks (title, description, status, priority) VALUES ('Tarefa', 'Descrição', 'Open', 'High');
+INSERT INTO Equipe DEFAULT VALUES;
+INSERT INTO Comentario (texto, tarefa_id) VALUES ('Comentário antigo', 1);
+INSERT INTO Notificacao (tipo, conteudo, destinatario_id) VALUES ('assignment', 'Notificação antiga', 1);
+INSERT INTO Task_user_associations (user_id, task_id) VALUES (1, 1);
+`
+
+func TestMigrateAdoptsLegacySchema(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "teamtask.db")
+	db, err := repository.Open(repository.SQLite, dsn)
+	if err != nil {
+		t.Fatalf("Erro ao abrir o banco: %v", err)
+	}
+	defer db.Close()
+	for _, statement := range migrations.SplitStatements(legacySchema) {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Erro ao criar o esquema antigo: %v", err)
+		}
+	}
+
+	migrator, err := migrations.New(db, string(repository.SQLite), zap.NewNop())
+	if err != nil {
+		t.Fatalf("Erro ao carregar as migrações: %v", err)
+	}
+	done, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Erro ao migrar o banco antigo: %v", err)
+	}
+	// O esquema do ddl.sql é a versão 1; só as seguintes são aplicadas
+	statuses, _ := migrator.Status()
+	if len(done) != len(statuses)-1 || done[0].Version != 2 || statuses[0].AppliedAt == nil {
+		t.Fatalf("O banco deveria ser adotado na versão 1: %d aplicadas de %d", len(done), len(statuses))
+	}
+
+	// Os dados antigos continuam lá e são lidos pelo repositório
+	repo := repository.NewRepository(db, repository.SQLite, zap.NewNop())
+	task, err := repo.GetTaskByID(1)
+	if err != nil || task.Title != "Tarefa" || task.Version != 1 {
+		t.Fatalf("Tarefa antiga incorreta: %+v %v", task, err)
+	}
+	if assignees, err := repo.GetTaskAssignees(1); err != nil || len(assignees) != 1 || assignees[0] != 1 {
+		t.Errorf("Atribuição antiga incorreta: %v %v", assignees, err)
+	}
+	user, err := repo.GetUserByEmail("ana@example.com")
+	if err != nil || user.Role != service.RoleMember {
+		t.Errorf("Usuário antigo incorreto: %+v %v", user, err)
+	}
+	if comments, total, err := repo.ListComments(1, 10, 0); err != nil || total != 1 || comments[0].Text != "Comentário antigo" {
+		t.Errorf("Comentário antigo incorreto: %+v %v", comments, err)
+	}
+	if notifications, err := repo.ListNotifications(1, true); err != nil || len(notifications) != 1 {
+		t.Errorf("Notificação antiga incorreta: %+v %v", notifications, err)
+	}
+	if team, err := repo.GetTeamByID(1); err != nil || team.Archived {
+		t.Errorf("Equipe antiga incorreta: %+v %v", team, err)
+	}
+
+	// Com a versão registrada, uma nova execução não aplica nada
+	if done, err := migrator.Up(); err != nil || len(done) != 0 {
+		t.Errorf("Nenhuma migração deveria ficar pendente: %d %v", len(done), err)
+	}
+}
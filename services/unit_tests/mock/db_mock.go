@@ -1,7 +1,10 @@
 package mock
 
 import (
-	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	service "github.com/mclcavalcante/teamTask/services"
 )
@@ -12,34 +15,177 @@ type MockDatabase struct {
 	taskCounter int
 	tasksByUser map[int][]service.Task // Mapeamento de IDs de usuário para tarefas atribuídas a esse usuário
 
+	// A lixeira guarda as tarefas e os usuários excluídos fora dos mapas principais, para
+	// que as consultas comuns não os vejam
+	trashedTasks map[int]service.Task
+	trashedUsers map[int]service.User
+
 	userCounter int
 	usersByID   map[int]service.User // Mapeamento de IDs de usuário para usuários
+
+	sessions map[string]service.Session
+
+	teamCounter int
+	teams       map[int]service.Team
+	teamMembers map[int]map[int]service.TeamRole // equipe -> usuário -> papel
+
+	commentCounter int
+	comments       map[int]service.Comment
+
+	notificationCounter int
+	notifications       map[int]service.Notification
+	notificationPrefs   map[int]map[service.NotificationType]bool
+
+	workflowCounter     int
+	workflows           map[int]service.Workflow
+	workflowAssignments []service.WorkflowAssignment
+
+	labelCounter int
+	labels       map[int]service.Label
+	taskLabels   map[int]map[int]bool // tarefa -> etiquetas aplicadas
+
+	taskLinks map[service.TaskLink]bool
+
+	attachmentCounter int
+	attachments       map[int]service.Attachment
+
+	taskEvents []service.TaskEvent // nunca alterados, nem quando a tarefa é excluída
+
+	inTx bool // true enquanto WithinTransaction está em andamento
+}
+
+// WithinTransaction simula uma transação: guarda uma cópia do estado e a restaura se fn
+// falhar. Chamadas aninhadas participam da transação já aberta.
+func (d *MockDatabase) WithinTransaction(fn func(repo service.Repository) error) error {
+	if d.inTx {
+		return fn(d)
+	}
+
+	snapshot := d.clone()
+	d.inTx = true
+	defer func() { d.inTx = false }()
+
+	if err := fn(d); err != nil {
+		*d = *snapshot
+		return err
+	}
+
+	return nil
+}
+
+// clone copia profundamente o estado do banco simulado.
+func (d *MockDatabase) clone() *MockDatabase {
+	c := *d
+
+	c.tasks = make(map[int]service.Task, len(d.tasks))
+	for id, task := range d.tasks {
+		task.AssignedUsers = append([]int(nil), task.AssignedUsers...)
+		c.tasks[id] = task
+	}
+	c.tasksByUser = make(map[int][]service.Task, len(d.tasksByUser))
+	for id, tasks := range d.tasksByUser {
+		c.tasksByUser[id] = append([]service.Task(nil), tasks...)
+	}
+	c.trashedTasks = copyMap(d.trashedTasks)
+	c.usersByID = copyMap(d.usersByID)
+	c.trashedUsers = copyMap(d.trashedUsers)
+	c.sessions = copyMap(d.sessions)
+	c.teams = copyMap(d.teams)
+	c.teamMembers = make(map[int]map[int]service.TeamRole, len(d.teamMembers))
+	for id, members := range d.teamMembers {
+		c.teamMembers[id] = copyMap(members)
+	}
+	c.comments = make(map[int]service.Comment, len(d.comments))
+	for id, comment := range d.comments {
+		comment.Mentions = append([]int(nil), comment.Mentions...)
+		c.comments[id] = comment
+	}
+	c.notifications = copyMap(d.notifications)
+	c.notificationPrefs = make(map[int]map[service.NotificationType]bool, len(d.notificationPrefs))
+	for id, prefs := range d.notificationPrefs {
+		c.notificationPrefs[id] = copyMap(prefs)
+	}
+	// Os fluxos gravados nunca são alterados no lugar, só substituídos
+	c.workflows = copyMap(d.workflows)
+	c.workflowAssignments = append([]service.WorkflowAssignment(nil), d.workflowAssignments...)
+	c.labels = copyMap(d.labels)
+	c.taskLabels = make(map[int]map[int]bool, len(d.taskLabels))
+	for id, labels := range d.taskLabels {
+		c.taskLabels[id] = copyMap(labels)
+	}
+	c.taskLinks = copyMap(d.taskLinks)
+	c.attachments = copyMap(d.attachments)
+	c.taskEvents = append([]service.TaskEvent(nil), d.taskEvents...)
+
+	return &c
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
 }
 
 // CreateTask cria uma nova tarefa simulada no banco de dados e retorna o ID da tarefa criada.
-func (d *MockDatabase) CreateTask(title, description, status, priority string, assignedUsers []int) (int, error) {
+func (d *MockDatabase) CreateTask(task service.Task) (int, error) {
+	// Como no banco, a tarefa só é criada se a equipe e todos os usuários atribuídos existirem
+	if err := d.requireTeam(task.TeamID); err != nil {
+		return 0, err
+	}
+	if err := d.requireParent(task.ParentID); err != nil {
+		return 0, err
+	}
+	for _, userID := range task.AssignedUsers {
+		if _, ok := d.usersByID[userID]; !ok {
+			return 0, fmt.Errorf("%w: membro da equipe não encontrado", service.ErrNotFound)
+		}
+	}
+	for i, userID := range task.AssignedUsers {
+		for _, other := range task.AssignedUsers[:i] {
+			if other == userID {
+				return 0, service.ErrConflict
+			}
+		}
+	}
+
 	d.taskCounter++
 	taskID := d.taskCounter
 
-	task := service.Task{
-		Title:         title,
-		Description:   description,
-		Status:        status,
-		Priority:      priority,
-		ID:            taskID,
-		AssignedUsers: assignedUsers,
-	}
-
+	task.ID = taskID
+	task.Version = 1
+	task.AssignedUsers = append([]int(nil), task.AssignedUsers...)
+	task.Labels = nil
+	task.Progress = nil
+	task.Blocked, task.BlockedBy = false, nil
 	d.tasks[taskID] = task
 
 	return taskID, nil
 }
 
+// requireTeam verifica a equipe de uma tarefa; zero significa tarefa sem equipe.
+func (d *MockDatabase) requireTeam(teamID int) error {
+	if _, ok := d.teams[teamID]; teamID != 0 && !ok {
+		return fmt.Errorf("%w: equipe não encontrada", service.ErrNotFound)
+	}
+	return nil
+}
+
+// requireParent verifica a tarefa pai de uma subtarefa; zero significa tarefa de
+// primeiro nível.
+func (d *MockDatabase) requireParent(parentID int) error {
+	if _, ok := d.tasks[parentID]; parentID != 0 && !ok {
+		return fmt.Errorf("%w: tarefa pai não encontrada", service.ErrNotFound)
+	}
+	return nil
+}
+
 // GetTaskByID retorna os detalhes de uma tarefa simulada com base no ID da tarefa fornecido.
 func (d *MockDatabase) GetTaskByID(taskID int) (service.Task, error) {
 	task, ok := d.tasks[taskID]
 	if !ok {
-		return service.Task{}, errors.New("tarefa não encontrada")
+		return service.Task{}, service.ErrNotFound
 	}
 	return task, nil
 }
@@ -50,15 +196,20 @@ func (d *MockDatabase) AssignTaskToUser(taskID int, userID int) error {
 	// Verificar se a tarefa existe
 	newMemberTask, taskExists := d.tasks[taskID]
 	if !taskExists {
-		return errors.New("tarefa não encontrada")
+		return fmt.Errorf("%w: tarefa não encontrada", service.ErrNotFound)
 	}
 
 	// Verificar se o membro da equipe existe
 	_, memberExists := d.usersByID[userID]
 	if !memberExists {
-		return errors.New("membro da equipe não encontrado")
+		return fmt.Errorf("%w: membro da equipe não encontrado", service.ErrNotFound)
+	}
+	for _, assigned := range newMemberTask.AssignedUsers {
+		if assigned == userID {
+			return service.ErrConflict
+		}
 	}
-	newMemberTask.AssignedUsers = append(newMemberTask.AssignedUsers, userID)
+	newMemberTask.AssignedUsers = append(append([]int(nil), newMemberTask.AssignedUsers...), userID)
 
 	// Associar o membro da equipe à tarefa
 	d.tasks[taskID] = newMemberTask
@@ -75,15 +226,18 @@ func (d *MockDatabase) GetUserByEmail(email string) (service.User, error) {
 		}
 	}
 
-	return service.User{}, nil // Usuário não encontrado
+	return service.User{}, service.ErrNotFound
 }
 
 // AddUser simula a adição de um novo usuário ao banco de dados.
 func (d *MockDatabase) AddUser(newUser service.User) (int, error) {
 
-	for _, user := range d.usersByID {
-		if user.Email == newUser.Email {
-			return 0, errors.New("e-mail já está em uso")
+	// Como no banco, o e-mail continua ocupado enquanto o usuário estiver na lixeira
+	for _, users := range []map[int]service.User{d.usersByID, d.trashedUsers} {
+		for _, user := range users {
+			if user.Email == newUser.Email {
+				return 0, service.ErrConflict
+			}
 		}
 	}
 
@@ -94,11 +248,42 @@ func (d *MockDatabase) AddUser(newUser service.User) (int, error) {
 	return newUser.ID, nil
 }
 
+// UpdateUserPassword simula a troca do hash da senha de um usuário.
+func (d *MockDatabase) UpdateUserPassword(userID int, passwordHash string) error {
+	user, ok := d.usersByID[userID]
+	if !ok {
+		return service.ErrNotFound
+	}
+
+	user.Password = passwordHash
+	d.usersByID[userID] = user
+
+	return nil
+}
+
+// UpdateUserRole simula a troca do papel de um usuário.
+func (d *MockDatabase) UpdateUserRole(userID int, role service.Role) error {
+	user, ok := d.usersByID[userID]
+	if !ok {
+		return service.ErrNotFound
+	}
+
+	user.Role = role
+	d.usersByID[userID] = user
+
+	return nil
+}
+
+// CountUsers simula a contagem de usuários cadastrados, inclusive os da lixeira.
+func (d *MockDatabase) CountUsers() (int, error) {
+	return len(d.usersByID) + len(d.trashedUsers), nil
+}
+
 // GetUserByID simula a busca de um usuário no banco de dados pelo seu ID.
 func (d *MockDatabase) GetUserByID(id int) (service.User, error) {
 	user, ok := d.usersByID[id]
 	if !ok {
-		return service.User{}, errors.New("usuário não existe") // Usuário não encontrado
+		return service.User{}, service.ErrNotFound
 	}
 	return user, nil
 }
@@ -106,36 +291,151 @@ func (d *MockDatabase) GetUserByID(id int) (service.User, error) {
 // GetTasksForUser simula a obtenção de tarefas atribuídas ao usuário especificado.
 func (d *MockDatabase) GetTasksForUser(userID int) ([]service.Task, error) {
 	var tasksForUser []service.Task
-	for _, task := range d.tasks {
+	for id := 1; id <= d.taskCounter; id++ {
+		task, ok := d.tasks[id]
+		if !ok {
+			continue
+		}
 		for _, member := range task.AssignedUsers {
 			if member == userID {
 				tasksForUser = append(tasksForUser, task)
 			}
 		}
-
 	}
 
 	return tasksForUser, nil
 }
 
+// GetAssigneesForTasks simula a listagem dos responsáveis por cada tarefa, em ordem de ID.
+func (d *MockDatabase) GetAssigneesForTasks(taskIDs []int) (map[int][]int, error) {
+	assignees := make(map[int][]int)
+	for _, taskID := range taskIDs {
+		ids, _ := d.GetTaskAssignees(taskID)
+		if len(ids) > 0 {
+			sort.Ints(ids)
+			assignees[taskID] = ids
+		}
+	}
+	return assignees, nil
+}
+
+// UnassignTaskFromUser simula a retirada de um usuário da tarefa.
+func (d *MockDatabase) UnassignTaskFromUser(taskID, userID int) error {
+	task, ok := d.tasks[taskID]
+	_, active := d.usersByID[userID]
+	if !ok || !active {
+		return fmt.Errorf("%w: o usuário não está atribuído à tarefa", service.ErrNotFound)
+	}
+
+	var remaining []int
+	for _, assigned := range task.AssignedUsers {
+		if assigned != userID {
+			remaining = append(remaining, assigned)
+		}
+	}
+	if len(remaining) == len(task.AssignedUsers) {
+		return fmt.Errorf("%w: o usuário não está atribuído à tarefa", service.ErrNotFound)
+	}
+	task.AssignedUsers = remaining
+	d.tasks[taskID] = task
+	return nil
+}
+
+// GetTaskAssignees simula a listagem dos usuários atribuídos a uma tarefa.
+func (d *MockDatabase) GetTaskAssignees(taskID int) ([]int, error) {
+	task, ok := d.tasks[taskID]
+	if !ok {
+		return nil, nil
+	}
+
+	var assignees []int
+	for _, userID := range task.AssignedUsers {
+		if _, ok := d.usersByID[userID]; ok {
+			assignees = append(assignees, userID)
+		}
+	}
+	sort.Ints(assignees)
+	return assignees, nil
+}
+
+// GetAllTasks simula a listagem de todas as tarefas, em ordem de ID.
 func (d *MockDatabase) GetAllTasks() ([]service.Task, error) {
 	tasks := make([]service.Task, 0, len(d.tasks))
-	for _, task := range d.tasks {
-		tasks = append(tasks, task)
+	for id := 1; id <= d.taskCounter; id++ {
+		if task, ok := d.tasks[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// GetOverdueTasks simula a listagem das tarefas não concluídas com prazo anterior a now.
+func (d *MockDatabase) GetOverdueTasks(now time.Time) ([]service.Task, error) {
+	var tasks []service.Task
+	for id := 1; id <= d.taskCounter; id++ {
+		task, ok := d.tasks[id]
+		if ok && task.DueDate != nil && task.DueDate.Before(now) && task.CompletedAt == nil {
+			tasks = append(tasks, task)
+		}
 	}
+
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].DueDate.Before(*tasks[j].DueDate) })
 	return tasks, nil
 }
 
-// DeleteTask simula a exclusão de uma tarefa do banco de dados.
+// GetTaskChildren simula a listagem das subtarefas diretas, em ordem de ID.
+func (d *MockDatabase) GetTaskChildren(parentID int) ([]service.Task, error) {
+	var children []service.Task
+	for id := 1; id <= d.taskCounter; id++ {
+		if task, ok := d.tasks[id]; ok && task.ParentID == parentID {
+			children = append(children, task)
+		}
+	}
+	return children, nil
+}
+
+// DeleteTask simula a exclusão definitiva de uma tarefa, que pode estar na lixeira.
 func (d *MockDatabase) DeleteTask(taskID int) error {
 	// Verificar se a tarefa existe
 	_, taskExists := d.tasks[taskID]
-	if !taskExists {
-		return errors.New("tarefa não encontrada")
+	_, trashed := d.trashedTasks[taskID]
+	if !taskExists && !trashed {
+		return service.ErrNotFound
 	}
 
-	// Excluir a tarefa do banco de dados mockado
+	// Excluir a tarefa do banco de dados mockado, com seus comentários, notificações, etiquetas, dependências
+	// e anexos; as subtarefas passam a ser de primeiro nível
 	delete(d.tasks, taskID)
+	delete(d.trashedTasks, taskID)
+	delete(d.taskLabels, taskID)
+	for link := range d.taskLinks {
+		if link.BlockerID == taskID || link.BlockedID == taskID {
+			delete(d.taskLinks, link)
+		}
+	}
+	for _, tasks := range []map[int]service.Task{d.tasks, d.trashedTasks} {
+		for id, task := range tasks {
+			if task.ParentID == taskID {
+				task.ParentID = 0
+				tasks[id] = task
+			}
+		}
+	}
+	for id, comment := range d.comments {
+		if comment.TaskID == taskID {
+			delete(d.comments, id)
+		}
+	}
+	for id, notification := range d.notifications {
+		if notification.TaskID == taskID {
+			delete(d.notifications, id)
+		}
+	}
+	for id, attachment := range d.attachments {
+		if attachment.TaskID == taskID {
+			delete(d.attachments, id)
+		}
+	}
 
 	return nil
 }
@@ -143,31 +443,842 @@ func (d *MockDatabase) DeleteTask(taskID int) error {
 // UpdateTask é um método para atualizar uma tarefa existente no banco de dados.
 func (d *MockDatabase) UpdateTask(taskID int, updatedTask service.Task) error {
 	// Verificar se a tarefa existe
-	_, ok := d.tasks[taskID]
+	current, ok := d.tasks[taskID]
 	if !ok {
-		return errors.New("tarefa não encontrada")
+		return service.ErrNotFound
+	}
+	if err := d.requireTeam(updatedTask.TeamID); err != nil {
+		return err
+	}
+	if err := d.requireParent(updatedTask.ParentID); err != nil {
+		return err
+	}
+	if updatedTask.Version != current.Version {
+		return &service.VersionError{Current: current.Version}
 	}
 
-	// Atualizar a tarefa
+	// Atualizar a tarefa; como no banco, as atribuições e a data de criação não mudam
+	updatedTask.ID = taskID
+	updatedTask.Version++
+	updatedTask.AssignedUsers = current.AssignedUsers
+	updatedTask.CreatedAt = current.CreatedAt
+	updatedTask.Labels = nil
+	updatedTask.Progress = nil
+	updatedTask.Blocked, updatedTask.BlockedBy = false, nil
 	d.tasks[taskID] = updatedTask
 
 	return nil
 }
 
-// RemoveUser é um método para remover um usuário existente do banco de dados.
+// RemoveUser simula a exclusão definitiva de um usuário, que pode estar na lixeira.
 func (d *MockDatabase) RemoveUser(userID int) error {
 	// Verificar se o usuário existe
 	_, ok := d.usersByID[userID]
+	_, trashed := d.trashedUsers[userID]
+	if !ok && !trashed {
+		return service.ErrNotFound
+	}
+
+	// Remover o usuário e, como no banco, tudo o que pertence a ele
+	delete(d.usersByID, userID)
+	delete(d.trashedUsers, userID)
+	for _, tasks := range []map[int]service.Task{d.tasks, d.trashedTasks} {
+		for id, task := range tasks {
+			task.AssignedUsers = removeID(task.AssignedUsers, userID)
+			tasks[id] = task
+		}
+	}
+	for _, members := range d.teamMembers {
+		delete(members, userID)
+	}
+	for id, session := range d.sessions {
+		if session.UserID == userID {
+			delete(d.sessions, id)
+		}
+	}
+	for id, notification := range d.notifications {
+		if notification.RecipientID == userID {
+			delete(d.notifications, id)
+		}
+	}
+	delete(d.notificationPrefs, userID)
+	for id, comment := range d.comments {
+		if comment.AuthorID == userID {
+			comment.AuthorID = 0
+		}
+		comment.Mentions = removeID(comment.Mentions, userID)
+		d.comments[id] = comment
+	}
+	for id, attachment := range d.attachments {
+		if attachment.UploaderID == userID {
+			attachment.UploaderID = 0
+			d.attachments[id] = attachment
+		}
+	}
+
+	return nil
+}
+
+// removeID retorna uma cópia de ids sem id.
+func removeID(ids []int, id int) []int {
+	var kept []int
+	for _, other := range ids {
+		if other != id {
+			kept = append(kept, other)
+		}
+	}
+	return kept
+}
+
+// CreateSession simula a gravação de uma nova sessão.
+func (d *MockDatabase) CreateSession(session service.Session) error {
+	if _, exists := d.sessions[session.ID]; exists {
+		return service.ErrConflict
+	}
+	d.sessions[session.ID] = session
+	return nil
+}
+
+// GetSession simula a busca de uma sessão pelo ID.
+func (d *MockDatabase) GetSession(sessionID string) (service.Session, error) {
+	session, ok := d.sessions[sessionID]
+	if !ok {
+		return service.Session{}, service.ErrNotFound
+	}
+	return session, nil
+}
+
+// UpdateSessionRefresh simula a rotação do refresh token de uma sessão.
+func (d *MockDatabase) UpdateSessionRefresh(sessionID, refreshHash string, expiresAt time.Time) error {
+	session, ok := d.sessions[sessionID]
+	if !ok {
+		return service.ErrNotFound
+	}
+
+	session.RefreshHash = refreshHash
+	session.ExpiresAt = expiresAt
+	d.sessions[sessionID] = session
+
+	return nil
+}
+
+// RevokeSession simula a revogação de uma sessão.
+func (d *MockDatabase) RevokeSession(sessionID string, revokedAt time.Time) error {
+	session, ok := d.sessions[sessionID]
+	if !ok {
+		return service.ErrNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	session.RevokedAt = &revokedAt
+	d.sessions[sessionID] = session
+
+	return nil
+}
+
+// CreateTeam simula a criação de uma equipe.
+func (d *MockDatabase) CreateTeam(team service.Team) (int, error) {
+	d.teamCounter++
+	team.ID = d.teamCounter
+	team.Members = nil
+
+	d.teams[team.ID] = team
+	d.teamMembers[team.ID] = make(map[int]service.TeamRole)
+
+	return team.ID, nil
+}
+
+// GetTeamByID simula a busca de uma equipe pelo ID.
+func (d *MockDatabase) GetTeamByID(teamID int) (service.Team, error) {
+	team, ok := d.teams[teamID]
+	if !ok {
+		return service.Team{}, service.ErrNotFound
+	}
+	return team, nil
+}
+
+// GetAllTeams simula a listagem de todas as equipes.
+func (d *MockDatabase) GetAllTeams() ([]service.Team, error) {
+	var teams []service.Team
+	for id := 1; id <= d.teamCounter; id++ {
+		if team, ok := d.teams[id]; ok {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
+
+// GetTeamsForUser simula a listagem das equipes de um usuário.
+func (d *MockDatabase) GetTeamsForUser(userID int) ([]service.Team, error) {
+	var teams []service.Team
+	for id := 1; id <= d.teamCounter; id++ {
+		if _, ok := d.teamMembers[id][userID]; ok {
+			teams = append(teams, d.teams[id])
+		}
+	}
+	return teams, nil
+}
+
+// UpdateTeam simula a atualização de uma equipe.
+func (d *MockDatabase) UpdateTeam(team service.Team) error {
+	if _, ok := d.teams[team.ID]; !ok {
+		return service.ErrNotFound
+	}
+
+	team.Members = nil
+	d.teams[team.ID] = team
+
+	return nil
+}
+
+// AddTeamMember simula a inclusão de um membro na equipe.
+func (d *MockDatabase) AddTeamMember(teamID, userID int, role service.TeamRole) error {
+	members, ok := d.teamMembers[teamID]
+	if !ok {
+		return service.ErrNotFound
+	}
+	if _, ok := d.usersByID[userID]; !ok {
+		return service.ErrNotFound
+	}
+	if _, exists := members[userID]; exists {
+		return service.ErrConflict
+	}
+
+	members[userID] = role
+	return nil
+}
+
+// UpdateTeamMemberRole simula a troca do papel de um membro da equipe.
+func (d *MockDatabase) UpdateTeamMemberRole(teamID, userID int, role service.TeamRole) error {
+	if _, ok := d.teamMembers[teamID][userID]; !ok {
+		return service.ErrNotFound
+	}
+	d.teamMembers[teamID][userID] = role
+	return nil
+}
+
+// RemoveTeamMember simula a remoção de um membro da equipe.
+func (d *MockDatabase) RemoveTeamMember(teamID, userID int) error {
+	if _, ok := d.teamMembers[teamID][userID]; !ok {
+		return service.ErrNotFound
+	}
+	delete(d.teamMembers[teamID], userID)
+	return nil
+}
+
+// GetTeamMembers simula a listagem dos membros de uma equipe.
+func (d *MockDatabase) GetTeamMembers(teamID int) ([]service.TeamMember, error) {
+	var members []service.TeamMember
+	for userID, role := range d.teamMembers[teamID] {
+		if _, ok := d.usersByID[userID]; ok {
+			members = append(members, service.TeamMember{UserID: userID, Role: role})
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+	return members, nil
+}
+
+// GetTasksForTeam simula a listagem das tarefas de uma equipe.
+func (d *MockDatabase) GetTasksForTeam(teamID int) ([]service.Task, error) {
+	var tasks []service.Task
+	for id := 1; id <= d.taskCounter; id++ {
+		if task, ok := d.tasks[id]; ok && task.TeamID == teamID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// CreateComment simula a gravação de um comentário.
+func (d *MockDatabase) CreateComment(comment service.Comment) (int, error) {
+	if _, ok := d.tasks[comment.TaskID]; !ok {
+		return 0, fmt.Errorf("%w: tarefa não encontrada", service.ErrNotFound)
+	}
+
+	d.commentCounter++
+	comment.ID = d.commentCounter
+	comment.Mentions = sortedIDs(comment.Mentions)
+	d.comments[comment.ID] = comment
+
+	return comment.ID, nil
+}
+
+// GetComment simula a busca de um comentário pelo ID.
+func (d *MockDatabase) GetComment(commentID int) (service.Comment, error) {
+	comment, ok := d.comments[commentID]
+	if !ok {
+		return service.Comment{}, service.ErrNotFound
+	}
+	return comment, nil
+}
+
+// UpdateComment simula a edição de um comentário.
+func (d *MockDatabase) UpdateComment(comment service.Comment) error {
+	current, ok := d.comments[comment.ID]
+	if !ok {
+		return service.ErrNotFound
+	}
+
+	current.Text = comment.Text
+	current.EditedAt = comment.EditedAt
+	current.Mentions = sortedIDs(comment.Mentions)
+	d.comments[comment.ID] = current
+
+	return nil
+}
+
+// DeleteComment simula a exclusão de um comentário e de suas respostas.
+func (d *MockDatabase) DeleteComment(commentID int) error {
+	if _, ok := d.comments[commentID]; !ok {
+		return service.ErrNotFound
+	}
+	for id, comment := range d.comments {
+		if comment.ParentID == commentID {
+			delete(d.comments, id)
+		}
+	}
+	delete(d.comments, commentID)
+
+	return nil
+}
+
+// sortedIDs copia ids em ordem crescente, como o banco devolve as menções.
+func sortedIDs(ids []int) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+// ListComments simula a listagem paginada dos comentários de uma tarefa.
+func (d *MockDatabase) ListComments(taskID, limit, offset int) ([]service.Comment, int, error) {
+	var all []service.Comment
+	for id := 1; id <= d.commentCounter; id++ {
+		if comment, ok := d.comments[id]; ok && comment.TaskID == taskID {
+			all = append(all, comment)
+		}
+	}
+
+	if offset >= len(all) {
+		return nil, len(all), nil
+	}
+	end := offset + limit
+	if end > len(all) {
+		end = len(all)
+	}
+
+	return all[offset:end], len(all), nil
+}
+
+// CreateNotification simula a gravação de uma notificação.
+func (d *MockDatabase) CreateNotification(notification service.Notification) (int, error) {
+	d.notificationCounter++
+	notification.ID = d.notificationCounter
+	d.notifications[notification.ID] = notification
+
+	return notification.ID, nil
+}
+
+// GetNotification simula a busca de uma notificação pelo ID.
+func (d *MockDatabase) GetNotification(notificationID int) (service.Notification, error) {
+	notification, ok := d.notifications[notificationID]
+	if !ok {
+		return service.Notification{}, service.ErrNotFound
+	}
+	return notification, nil
+}
+
+// ListNotifications simula a listagem das notificações de um usuário, da mais recente à mais antiga.
+func (d *MockDatabase) ListNotifications(userID int, unreadOnly bool) ([]service.Notification, error) {
+	var notifications []service.Notification
+	for id := d.notificationCounter; id >= 1; id-- {
+		notification, ok := d.notifications[id]
+		if ok && notification.RecipientID == userID && (!unreadOnly || !notification.Read) {
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications, nil
+}
+
+// MarkNotificationRead simula a leitura de uma notificação.
+func (d *MockDatabase) MarkNotificationRead(notificationID int) error {
+	notification, ok := d.notifications[notificationID]
+	if !ok {
+		return service.ErrNotFound
+	}
+	notification.Read = true
+	d.notifications[notificationID] = notification
+	return nil
+}
+
+// MarkAllNotificationsRead simula a leitura de todas as notificações de um usuário.
+func (d *MockDatabase) MarkAllNotificationsRead(userID int) error {
+	for id, notification := range d.notifications {
+		if notification.RecipientID == userID {
+			notification.Read = true
+			d.notifications[id] = notification
+		}
+	}
+	return nil
+}
+
+// GetNotificationPreferences simula a leitura das preferências gravadas de um usuário.
+func (d *MockDatabase) GetNotificationPreferences(userID int) (map[service.NotificationType]bool, error) {
+	prefs := make(map[service.NotificationType]bool)
+	for notificationType, enabled := range d.notificationPrefs[userID] {
+		prefs[notificationType] = enabled
+	}
+	return prefs, nil
+}
+
+// SetNotificationPreference simula a gravação de uma preferência de notificação.
+func (d *MockDatabase) SetNotificationPreference(userID int, notificationType service.NotificationType, enabled bool) error {
+	if d.notificationPrefs[userID] == nil {
+		d.notificationPrefs[userID] = make(map[service.NotificationType]bool)
+	}
+	d.notificationPrefs[userID][notificationType] = enabled
+	return nil
+}
+
+// copyWorkflow copia os estados e transições para que o fluxo gravado não seja alterado
+// por quem o recebeu.
+func copyWorkflow(workflow service.Workflow) service.Workflow {
+	workflow.States = append([]service.WorkflowState(nil), workflow.States...)
+	workflow.Transitions = append([]service.WorkflowTransition(nil), workflow.Transitions...)
+	return workflow
+}
+
+// CreateWorkflow simula o cadastro de um fluxo de trabalho.
+func (d *MockDatabase) CreateWorkflow(workflow service.Workflow) (int, error) {
+	d.workflowCounter++
+	workflow.ID = d.workflowCounter
+	d.workflows[workflow.ID] = copyWorkflow(workflow)
+	return workflow.ID, nil
+}
+
+// GetWorkflow simula a busca de um fluxo de trabalho pelo ID.
+func (d *MockDatabase) GetWorkflow(workflowID int) (service.Workflow, error) {
+	workflow, ok := d.workflows[workflowID]
+	if !ok {
+		return service.Workflow{}, service.ErrNotFound
+	}
+	return copyWorkflow(workflow), nil
+}
+
+// ListWorkflows simula a listagem dos fluxos de trabalho.
+func (d *MockDatabase) ListWorkflows() ([]service.Workflow, error) {
+	var ids []int
+	for id := range d.workflows {
+		ids = append(ids, id)
+	}
+
+	var workflows []service.Workflow
+	for _, id := range sortedIDs(ids) {
+		workflows = append(workflows, copyWorkflow(d.workflows[id]))
+	}
+	return workflows, nil
+}
+
+// UpdateWorkflow simula a alteração de um fluxo de trabalho.
+func (d *MockDatabase) UpdateWorkflow(workflow service.Workflow) error {
+	if _, ok := d.workflows[workflow.ID]; !ok {
+		return service.ErrNotFound
+	}
+	d.workflows[workflow.ID] = copyWorkflow(workflow)
+	return nil
+}
+
+// DeleteWorkflow simula a exclusão de um fluxo de trabalho e de suas atribuições.
+func (d *MockDatabase) DeleteWorkflow(workflowID int) error {
+	if _, ok := d.workflows[workflowID]; !ok {
+		return service.ErrNotFound
+	}
+	delete(d.workflows, workflowID)
+
+	var kept []service.WorkflowAssignment
+	for _, assignment := range d.workflowAssignments {
+		if assignment.WorkflowID != workflowID {
+			kept = append(kept, assignment)
+		}
+	}
+	d.workflowAssignments = kept
+	return nil
+}
+
+// ListWorkflowAssignments simula a listagem das atribuições de fluxos, na ordem do banco.
+func (d *MockDatabase) ListWorkflowAssignments() ([]service.WorkflowAssignment, error) {
+	assignments := append([]service.WorkflowAssignment(nil), d.workflowAssignments...)
+	sort.Slice(assignments, func(i, j int) bool {
+		a, b := assignments[i], assignments[j]
+		if a.WorkflowID != b.WorkflowID {
+			return a.WorkflowID < b.WorkflowID
+		}
+		if a.TeamID != b.TeamID {
+			return a.TeamID < b.TeamID
+		}
+		return a.TaskType < b.TaskType
+	})
+	return assignments, nil
+}
+
+// SetWorkflowAssignment simula a gravação de uma atribuição, substituindo a de mesmo escopo.
+func (d *MockDatabase) SetWorkflowAssignment(assignment service.WorkflowAssignment) error {
+	if _, ok := d.workflows[assignment.WorkflowID]; !ok {
+		return fmt.Errorf("%w: fluxo de trabalho não encontrado", service.ErrNotFound)
+	}
+	if err := d.requireTeam(assignment.TeamID); err != nil {
+		return err
+	}
+
+	_ = d.RemoveWorkflowAssignment(assignment.TeamID, assignment.TaskType)
+	d.workflowAssignments = append(d.workflowAssignments, assignment)
+	return nil
+}
+
+// RemoveWorkflowAssignment simula a remoção de uma atribuição.
+func (d *MockDatabase) RemoveWorkflowAssignment(teamID int, taskType string) error {
+	for i, assignment := range d.workflowAssignments {
+		if assignment.TeamID == teamID && assignment.TaskType == taskType {
+			d.workflowAssignments = append(d.workflowAssignments[:i:i], d.workflowAssignments[i+1:]...)
+			return nil
+		}
+	}
+	return service.ErrNotFound
+}
+
+// CreateLabel simula a criação de uma etiqueta; o nome é único na equipe.
+func (d *MockDatabase) CreateLabel(label service.Label) (int, error) {
+	if _, ok := d.teams[label.TeamID]; !ok {
+		return 0, fmt.Errorf("%w: equipe não encontrada", service.ErrNotFound)
+	}
+	if d.labelNameTaken(label) {
+		return 0, service.ErrConflict
+	}
+
+	d.labelCounter++
+	label.ID = d.labelCounter
+	d.labels[label.ID] = label
+	return label.ID, nil
+}
+
+// labelNameTaken informa se outra etiqueta da equipe já usa o nome.
+func (d *MockDatabase) labelNameTaken(label service.Label) bool {
+	for id, other := range d.labels {
+		if id != label.ID && other.TeamID == label.TeamID && other.Name == label.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLabel simula a busca de uma etiqueta pelo ID.
+func (d *MockDatabase) GetLabel(labelID int) (service.Label, error) {
+	label, ok := d.labels[labelID]
+	if !ok {
+		return service.Label{}, service.ErrNotFound
+	}
+	return label, nil
+}
+
+// ListLabels simula a listagem das etiquetas de uma equipe, em ordem de nome.
+func (d *MockDatabase) ListLabels(teamID int) ([]service.Label, error) {
+	var labels []service.Label
+	for _, label := range d.labels {
+		if label.TeamID == teamID {
+			labels = append(labels, label)
+		}
+	}
+
+	sortLabels(labels)
+	return labels, nil
+}
+
+// sortLabels ordena as etiquetas por nome, sem diferenciar maiúsculas de minúsculas, e
+// por ID, como o banco.
+func sortLabels(labels []service.Label) {
+	sort.Slice(labels, func(i, j int) bool {
+		if a, b := strings.ToLower(labels[i].Name), strings.ToLower(labels[j].Name); a != b {
+			return a < b
+		}
+		return labels[i].ID < labels[j].ID
+	})
+}
+
+// UpdateLabel simula a alteração do nome e da cor de uma etiqueta.
+func (d *MockDatabase) UpdateLabel(label service.Label) error {
+	current, ok := d.labels[label.ID]
+	if !ok {
+		return service.ErrNotFound
+	}
+	label.TeamID = current.TeamID
+	if d.labelNameTaken(label) {
+		return service.ErrConflict
+	}
+
+	d.labels[label.ID] = label
+	return nil
+}
+
+// DeleteLabel simula a exclusão de uma etiqueta, que sai das tarefas em cascata.
+func (d *MockDatabase) DeleteLabel(labelID int) error {
+	if _, ok := d.labels[labelID]; !ok {
+		return service.ErrNotFound
+	}
+
+	delete(d.labels, labelID)
+	for _, labels := range d.taskLabels {
+		delete(labels, labelID)
+	}
+	return nil
+}
+
+// AddTaskLabel simula a aplicação de uma etiqueta a uma tarefa.
+func (d *MockDatabase) AddTaskLabel(taskID, labelID int) error {
+	if _, ok := d.tasks[taskID]; !ok {
+		return fmt.Errorf("%w: tarefa não encontrada", service.ErrNotFound)
+	}
+	if _, ok := d.labels[labelID]; !ok {
+		return fmt.Errorf("%w: etiqueta não encontrada", service.ErrNotFound)
+	}
+	if d.taskLabels[taskID][labelID] {
+		return service.ErrConflict
+	}
+
+	if d.taskLabels[taskID] == nil {
+		d.taskLabels[taskID] = make(map[int]bool)
+	}
+	d.taskLabels[taskID][labelID] = true
+	return nil
+}
+
+// RemoveTaskLabel simula a retirada de uma etiqueta da tarefa.
+func (d *MockDatabase) RemoveTaskLabel(taskID, labelID int) error {
+	if !d.taskLabels[taskID][labelID] {
+		return service.ErrNotFound
+	}
+	delete(d.taskLabels[taskID], labelID)
+	return nil
+}
+
+// GetLabelsForTasks simula a listagem das etiquetas de cada tarefa, em ordem de nome.
+func (d *MockDatabase) GetLabelsForTasks(taskIDs []int) (map[int][]service.Label, error) {
+	labels := make(map[int][]service.Label)
+	for _, taskID := range taskIDs {
+		for labelID := range d.taskLabels[taskID] {
+			labels[taskID] = append(labels[taskID], d.labels[labelID])
+		}
+		sortLabels(labels[taskID])
+	}
+	return labels, nil
+}
+
+// AddTaskLink simula a gravação de uma dependência entre tarefas.
+func (d *MockDatabase) AddTaskLink(link service.TaskLink) error {
+	if _, ok := d.tasks[link.BlockerID]; !ok {
+		return fmt.Errorf("%w: tarefa bloqueadora não encontrada", service.ErrNotFound)
+	}
+	if _, ok := d.tasks[link.BlockedID]; !ok {
+		return fmt.Errorf("%w: tarefa bloqueada não encontrada", service.ErrNotFound)
+	}
+	if d.taskLinks[link] {
+		return service.ErrConflict
+	}
+
+	d.taskLinks[link] = true
+	return nil
+}
+
+// RemoveTaskLink simula a exclusão de uma dependência entre tarefas.
+func (d *MockDatabase) RemoveTaskLink(link service.TaskLink) error {
+	if !d.taskLinks[link] {
+		return service.ErrNotFound
+	}
+	delete(d.taskLinks, link)
+	return nil
+}
+
+// GetTaskLinks simula a listagem das dependências da tarefa, em ordem de bloqueadora e
+// bloqueada.
+func (d *MockDatabase) GetTaskLinks(taskID int) ([]service.TaskLink, error) {
+	var links []service.TaskLink
+	for link := range d.taskLinks {
+		if link.BlockerID == taskID || link.BlockedID == taskID {
+			links = append(links, link)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].BlockerID != links[j].BlockerID {
+			return links[i].BlockerID < links[j].BlockerID
+		}
+		return links[i].BlockedID < links[j].BlockedID
+	})
+	return links, nil
+}
+
+// GetOpenBlockers simula a listagem das tarefas não concluídas que bloqueiam cada tarefa.
+func (d *MockDatabase) GetOpenBlockers(taskIDs []int) (map[int][]int, error) {
+	blockers := make(map[int][]int)
+	for _, taskID := range taskIDs {
+		for link := range d.taskLinks {
+			if blocker, ok := d.tasks[link.BlockerID]; ok && link.BlockedID == taskID && blocker.CompletedAt == nil {
+				blockers[taskID] = append(blockers[taskID], link.BlockerID)
+			}
+		}
+		sort.Ints(blockers[taskID])
+	}
+	return blockers, nil
+}
+
+// CreateTaskEvent simula a gravação de um evento no histórico da tarefa.
+func (d *MockDatabase) CreateTaskEvent(event service.TaskEvent) (int, error) {
+	event.ID = len(d.taskEvents) + 1
+	event.Changes = append([]service.FieldChange(nil), event.Changes...)
+	d.taskEvents = append(d.taskEvents, event)
+	return event.ID, nil
+}
+
+// ListTaskEvents simula a listagem dos eventos da tarefa, em ordem de ID.
+func (d *MockDatabase) ListTaskEvents(taskID int) ([]service.TaskEvent, error) {
+	var events []service.TaskEvent
+	for _, event := range d.taskEvents {
+		if event.TaskID == taskID {
+			event.Changes = append([]service.FieldChange(nil), event.Changes...)
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// CreateAttachment simula o registro de um anexo e retorna seu ID.
+func (d *MockDatabase) CreateAttachment(attachment service.Attachment) (int, error) {
+	if _, ok := d.tasks[attachment.TaskID]; !ok {
+		return 0, fmt.Errorf("%w: tarefa não encontrada", service.ErrNotFound)
+	}
+	for _, existing := range d.attachments {
+		if existing.StorageKey == attachment.StorageKey {
+			return 0, service.ErrConflict
+		}
+	}
+
+	d.attachmentCounter++
+	attachment.ID = d.attachmentCounter
+	d.attachments[attachment.ID] = attachment
+	return attachment.ID, nil
+}
+
+// GetAttachment simula a busca de um anexo pelo ID.
+func (d *MockDatabase) GetAttachment(attachmentID int) (service.Attachment, error) {
+	attachment, ok := d.attachments[attachmentID]
+	if !ok {
+		return service.Attachment{}, service.ErrNotFound
+	}
+	return attachment, nil
+}
+
+// ListAttachments simula a listagem dos anexos da tarefa, em ordem de ID.
+func (d *MockDatabase) ListAttachments(taskID int) ([]service.Attachment, error) {
+	var attachments []service.Attachment
+	for _, attachment := range d.attachments {
+		if attachment.TaskID == taskID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].ID < attachments[j].ID })
+	return attachments, nil
+}
+
+// DeleteAttachment simula a exclusão do registro de um anexo.
+func (d *MockDatabase) DeleteAttachment(attachmentID int) error {
+	if _, ok := d.attachments[attachmentID]; !ok {
+		return service.ErrNotFound
+	}
+	delete(d.attachments, attachmentID)
+	return nil
+}
+
+// TrashTask simula a ida da tarefa para a lixeira.
+func (d *MockDatabase) TrashTask(taskID int, at time.Time) error {
+	task, ok := d.tasks[taskID]
+	if !ok {
+		return service.ErrNotFound
+	}
+
+	task.DeletedAt = &at
+	d.trashedTasks[taskID] = task
+	delete(d.tasks, taskID)
+	return nil
+}
+
+// RestoreTask simula a volta da tarefa da lixeira.
+func (d *MockDatabase) RestoreTask(taskID int) error {
+	task, ok := d.trashedTasks[taskID]
+	if !ok {
+		return service.ErrNotFound
+	}
+
+	task.DeletedAt = nil
+	d.tasks[taskID] = task
+	delete(d.trashedTasks, taskID)
+	return nil
+}
+
+// ListTrashedTasks simula a listagem da lixeira de tarefas, em ordem de exclusão.
+func (d *MockDatabase) ListTrashedTasks() ([]service.Task, error) {
+	var tasks []service.Task
+	for id := 1; id <= d.taskCounter; id++ {
+		if task, ok := d.trashedTasks[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].DeletedAt.Before(*tasks[j].DeletedAt) })
+	return tasks, nil
+}
+
+// TrashUser simula a ida do usuário para a lixeira.
+func (d *MockDatabase) TrashUser(userID int, at time.Time) error {
+	user, ok := d.usersByID[userID]
 	if !ok {
-		return errors.New("usuário não encontrado")
+		return service.ErrNotFound
 	}
 
-	// Remover o usuário
+	user.DeletedAt = &at
+	d.trashedUsers[userID] = user
 	delete(d.usersByID, userID)
+	return nil
+}
+
+// RestoreUser simula a volta do usuário da lixeira.
+func (d *MockDatabase) RestoreUser(userID int) error {
+	user, ok := d.trashedUsers[userID]
+	if !ok {
+		return service.ErrNotFound
+	}
 
+	user.DeletedAt = nil
+	d.usersByID[userID] = user
+	delete(d.trashedUsers, userID)
 	return nil
 }
 
+// ListTrashedUsers simula a listagem da lixeira de usuários, em ordem de exclusão.
+func (d *MockDatabase) ListTrashedUsers() ([]service.User, error) {
+	var users []service.User
+	for id := 1; id <= d.userCounter; id++ {
+		if user, ok := d.trashedUsers[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	sort.SliceStable(users, func(i, j int) bool { return users[i].DeletedAt.Before(*users[j].DeletedAt) })
+	return users, nil
+}
+
 // NewTestDatabase cria uma nova instância do banco de dados simulado para testes.
 func NewTestRepository() *MockDatabase {
 	return &MockDatabase{
@@ -175,7 +1286,29 @@ func NewTestRepository() *MockDatabase {
 		taskCounter: 0,
 		tasksByUser: make(map[int][]service.Task),
 
+		trashedTasks: make(map[int]service.Task),
+		trashedUsers: make(map[int]service.User),
+
 		userCounter: 0,
 		usersByID:   make(map[int]service.User),
+
+		sessions: make(map[string]service.Session),
+
+		teams:       make(map[int]service.Team),
+		teamMembers: make(map[int]map[int]service.TeamRole),
+
+		comments: make(map[int]service.Comment),
+
+		notifications:     make(map[int]service.Notification),
+		notificationPrefs: make(map[int]map[service.NotificationType]bool),
+
+		workflows: make(map[int]service.Workflow),
+
+		labels:     make(map[int]service.Label),
+		taskLabels: make(map[int]map[int]bool),
+
+		taskLinks: make(map[service.TaskLink]bool),
+
+		attachments: make(map[int]service.Attachment),
 	}
 }
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// notificationTypes retorna as categorias das notificações, na ordem recebida.
+func notificationTypes(notifications []service.Notification) []service.NotificationType {
+	var types []service.NotificationType
+	for _, notification := range notifications {
+		types = append(types, notification.Type)
+	}
+	return types
+}
+
+func TestAssignmentNotifiesMember(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	manager, member := users[service.RoleManager], users[service.RoleMember]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	if err := s.AsUser(manager).AssignMemberToTask(taskID, member.ID); err != nil {
+		t.Fatalf("Erro inesperado ao atribuir tarefa: %v", err)
+	}
+
+	inbox, err := s.AsUser(member).ListNotifications(false)
+	if err != nil {
+		t.Fatalf("Erro inesperado ao listar notificações: %v", err)
+	}
+	if len(inbox) != 1 || inbox[0].Type != service.NotificationAssignment || inbox[0].TaskID != taskID || inbox[0].Read {
+		t.Errorf("Notificação de atribuição não corresponde à esperada: %+v", inbox)
+	}
+
+	// Quem age não é notificado sobre a própria ação
+	managerInbox, _ := s.AsUser(manager).ListNotifications(false)
+	if len(managerInbox) != 0 {
+		t.Errorf("O autor da atribuição não deveria ser notificado: %+v", managerInbox)
+	}
+}
+
+func TestStatusChangeAndCommentNotifications(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	manager, member, viewer := users[service.RoleManager], users[service.RoleMember], users[service.RoleViewer]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description", Status: "Open"})
+	s.AssignMemberToTask(taskID, member.ID)
+
+	s.AsUser(manager).EditTask(taskID, service.Task{Title: "Task", Description: "Description", Status: "Closed"})
+	s.AsUser(manager).CreateComment(taskID, service.Comment{Text: "Fechando. @viewer@example.com, confira"})
+
+	inbox, _ := s.AsUser(member).ListNotifications(false)
+	types := notificationTypes(inbox)
+	expected := []service.NotificationType{service.NotificationComment, service.NotificationStatusChange, service.NotificationAssignment}
+	if len(types) != len(expected) {
+		t.Fatalf("Notificações esperadas %v, obtidas %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Notificações esperadas %v, obtidas %v", expected, types)
+			break
+		}
+	}
+
+	viewerInbox, _ := s.AsUser(viewer).ListNotifications(false)
+	if len(viewerInbox) != 1 || viewerInbox[0].Type != service.NotificationMention {
+		t.Errorf("O usuário mencionado deveria receber apenas a menção: %v", notificationTypes(viewerInbox))
+	}
+}
+
+func TestMarkNotificationsRead(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	for i := 0; i < 3; i++ {
+		taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+		s.AssignMemberToTask(taskID, member.ID)
+	}
+
+	inbox, _ := s.AsUser(member).ListNotifications(false)
+	if err := s.AsUser(member).MarkNotificationRead(inbox[0].ID); err != nil {
+		t.Fatalf("Erro inesperado ao marcar notificação como lida: %v", err)
+	}
+
+	unread, _ := s.AsUser(member).ListNotifications(true)
+	if len(unread) != 2 {
+		t.Errorf("Esperava-se 2 notificações não lidas, obtido %d", len(unread))
+	}
+
+	// Ninguém marca notificações de outra pessoa
+	err := s.AsUser(users[service.RoleAdmin]).MarkNotificationRead(unread[0].ID)
+	if !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound ao marcar notificação alheia, obtido: %v", err)
+	}
+
+	if err := s.AsUser(member).MarkAllNotificationsRead(); err != nil {
+		t.Fatalf("Erro inesperado ao marcar todas como lidas: %v", err)
+	}
+	unread, _ = s.AsUser(member).ListNotifications(true)
+	if len(unread) != 0 {
+		t.Errorf("Todas as notificações deveriam estar lidas, restaram %d", len(unread))
+	}
+}
+
+func TestNotificationPreferencesOptOut(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	prefs, _ := s.AsUser(member).GetNotificationPreferences()
+	if len(prefs) != len(service.NotificationTypes) || !prefs[service.NotificationAssignment] {
+		t.Errorf("Todas as categorias deveriam vir ativadas por padrão: %v", prefs)
+	}
+
+	err := s.AsUser(member).UpdateNotificationPreferences(map[service.NotificationType]bool{service.NotificationAssignment: false})
+	if err != nil {
+		t.Fatalf("Erro inesperado ao salvar preferências: %v", err)
+	}
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	s.AssignMemberToTask(taskID, member.ID)
+
+	inbox, _ := s.AsUser(member).ListNotifications(false)
+	if len(inbox) != 0 {
+		t.Errorf("Categoria desativada não deveria gerar notificações: %+v", inbox)
+	}
+
+	err = s.AsUser(member).UpdateNotificationPreferences(map[service.NotificationType]bool{"spam": false})
+	if !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para categoria desconhecida, obtido: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package service_test
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+func TestPatchTaskWithMergePatch(t *testing.T) {
+	s := NewTestService()
+	due := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição", Priority: "High", Status: "In Progress", DueDate: &due})
+
+	patch, err := service.MergePatch([]byte(`{"title": "Tarefa revisada"}`))
+	if err != nil {
+		t.Fatalf("Erro ao ler o merge patch: %v", err)
+	}
+	task, err := s.PatchTask(taskID, patch)
+	if err != nil {
+		t.Fatalf("Erro ao aplicar o patch: %v", err)
+	}
+	// Os campos ausentes do patch continuam como estavam
+	if task.Title != "Tarefa revisada" || task.Description != "Descrição" || task.Status != "In Progress" ||
+		task.Priority != "High" || task.DueDate == nil || !task.DueDate.Equal(due) || task.Version != 2 {
+		t.Fatalf("Tarefa incorreta após o patch: %+v", task)
+	}
+
+	// null apaga o campo
+	patch, _ = service.MergePatch([]byte(`{"dueDate": null, "priority": "Baixa"}`))
+	task, err = s.PatchTask(taskID, patch)
+	if err != nil || task.DueDate != nil || task.Priority != "Low" {
+		t.Fatalf("O prazo deveria ser apagado e a prioridade trocada: %+v %v", task, err)
+	}
+
+	events, _ := s.GetTaskHistory(taskID)
+	if len(events) != 3 {
+		t.Fatalf("Esperava-se 3 eventos, obtidos %d", len(events))
+	}
+	want := []service.FieldChange{{Field: "title", Before: "Tarefa", After: "Tarefa revisada"}}
+	if !slices.Equal(events[1].Changes, want) {
+		t.Errorf("O histórico deveria ter apenas o título: %+v", events[1].Changes)
+	}
+	if changes := events[2].Changes; len(changes) != 2 || changes[0].Field != "priority" || changes[1].Field != "dueDate" {
+		t.Errorf("O histórico deveria ter a prioridade e o prazo: %+v", changes)
+	}
+}
+
+func TestPatchTaskWithJSONPatch(t *testing.T) {
+	s := NewTestService()
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+
+	patch, err := service.JSONPatch([]byte(`[
+		{"op": "test", "path": "/version", "value": 1},
+		{"op": "replace", "path": "/title", "value": "Nova"},
+		{"op": "copy", "from": "/title", "path": "/description"},
+		{"op": "add", "path": "/type", "value": "bug"}
+	]`))
+	if err != nil {
+		t.Fatalf("Erro ao ler o JSON patch: %v", err)
+	}
+	task, err := s.PatchTask(taskID, patch)
+	if err != nil {
+		t.Fatalf("Erro ao aplicar o patch: %v", err)
+	}
+	if task.Title != "Nova" || task.Description != "Nova" || task.Type != "bug" {
+		t.Fatalf("Tarefa incorreta após o patch: %+v", task)
+	}
+
+	// O mesmo patch falha no teste da versão e nada é gravado
+	if _, err := s.PatchTask(taskID, patch); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict no teste da versão, obtido %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); task.Version != 2 {
+		t.Errorf("A tarefa não deveria mudar: %+v", task)
+	}
+}
+
+func TestPatchTaskClearsFields(t *testing.T) {
+	s := NewTestService()
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição", Priority: "High", Type: "bug"})
+	patch, _ := service.MergePatch([]byte(`{"resolution": "Corrigida"}`))
+	if _, err := s.PatchTask(taskID, patch); err != nil {
+		t.Fatalf("Erro ao informar a resolução: %v", err)
+	}
+
+	// null apaga tipo, resolução e prioridade, que podem ficar vazios
+	for _, document := range []string{`{"type": null}`, `{"resolution": null}`, `{"priority": null}`} {
+		patch, _ := service.MergePatch([]byte(document))
+		if _, err := s.PatchTask(taskID, patch); err != nil {
+			t.Fatalf("Erro ao aplicar %s: %v", document, err)
+		}
+	}
+	task, _ := s.GetTaskByID(taskID)
+	if task.Type != "" || task.Resolution != "" || task.Priority != "" || task.Version != 5 {
+		t.Fatalf("Os campos deveriam ser apagados: %+v", task)
+	}
+
+	patch, _ = service.JSONPatch([]byte(`[{"op": "add", "path": "/priority", "value": "Low"}]`))
+	if task, _ = s.PatchTask(taskID, patch); task.Priority != "Low" {
+		t.Fatalf("A prioridade deveria ser informada: %+v", task)
+	}
+	patch, _ = service.JSONPatch([]byte(`[{"op": "remove", "path": "/priority"}]`))
+	if task, err := s.PatchTask(taskID, patch); err != nil || task.Priority != "" {
+		t.Fatalf("A prioridade deveria ser apagada: %+v %v", task, err)
+	}
+	events, _ := s.GetTaskHistory(taskID)
+	if changes := events[len(events)-1].Changes; len(changes) != 1 || changes[0].Field != "priority" || changes[0].After != "" {
+		t.Errorf("O histórico deveria registrar a prioridade apagada: %+v", changes)
+	}
+
+	// O status não pode ser apagado
+	patch, _ = service.MergePatch([]byte(`{"status": null}`))
+	if _, err := s.PatchTask(taskID, patch); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao apagar o status, obtido %v", err)
+	}
+	patch, _ = service.JSONPatch([]byte(`[{"op": "remove", "path": "/status"}]`))
+	if _, err := s.PatchTask(taskID, patch); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao remover o status, obtido %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); task.Status != "Open" {
+		t.Errorf("O status não deveria mudar: %+v", task)
+	}
+}
+
+func TestPatchTaskAssignees(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	manager, member := users[service.RoleManager].ID, users[service.RoleMember].ID
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição", AssignedUsers: []int{member}})
+
+	patch, _ := service.JSONPatch([]byte(`[{"op": "add", "path": "/assignedUsers/-", "value": ` + strconv.Itoa(manager) + `}]`))
+	task, err := s.PatchTask(taskID, patch)
+	if err != nil || !slices.Equal(task.AssignedUsers, []int{manager, member}) {
+		t.Fatalf("O gerente deveria ser atribuído: %+v %v", task, err)
+	}
+
+	patch, _ = service.JSONPatch([]byte(`[{"op": "remove", "path": "/assignedUsers/1"}]`))
+	if task, err = s.PatchTask(taskID, patch); err != nil || !slices.Equal(task.AssignedUsers, []int{manager}) {
+		t.Fatalf("O membro deveria ser retirado: %+v %v", task, err)
+	}
+
+	// No merge patch, null retira todos os responsáveis
+	patch, _ = service.MergePatch([]byte(`{"assignedUsers": null}`))
+	if task, err = s.PatchTask(taskID, patch); err != nil || len(task.AssignedUsers) != 0 {
+		t.Fatalf("A tarefa deveria ficar sem responsáveis: %+v %v", task, err)
+	}
+}
+
+func TestPatchTaskValidation(t *testing.T) {
+	s := NewTestService()
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+
+	for _, document := range []string{`[]`, `{"id": 2}`, `{"version": 5}`, `{"parentId": 1}`, `{"desconhecido": 1}`} {
+		if _, err := service.MergePatch([]byte(document)); !errors.Is(err, service.ErrValidation) {
+			t.Errorf("Esperava-se ErrValidation para o merge patch %s, obtido %v", document, err)
+		}
+	}
+	for _, document := range []string{`{}`, `[{"op": "apagar", "path": "/title"}]`, `[{"op": "replace", "path": "/title"}]`,
+		`[{"op": "move", "from": "/id", "path": "/title"}]`, `[{"op": "remove", "path": ""}]`, `[{"op": "add", "path": "title", "value": "x"}]`} {
+		if _, err := service.JSONPatch([]byte(document)); !errors.Is(err, service.ErrValidation) {
+			t.Errorf("Esperava-se ErrValidation para o JSON patch %s, obtido %v", document, err)
+		}
+	}
+
+	// O resultado passa pelas validações da criação
+	for _, document := range []string{`{"title": ""}`, `{"description": null}`, `{"priority": "Urgente"}`,
+		`{"status": "Pendente"}`, `{"teamId": "um"}`, `{"startDate": "2030-02-01T00:00:00Z", "dueDate": "2030-01-01T00:00:00Z"}`} {
+		patch, err := service.MergePatch([]byte(document))
+		if err != nil {
+			t.Fatalf("Erro ao ler o merge patch %s: %v", document, err)
+		}
+		if _, err := s.PatchTask(taskID, patch); !errors.Is(err, service.ErrValidation) {
+			t.Errorf("Esperava-se ErrValidation para %s, obtido %v", document, err)
+		}
+	}
+
+	patch, _ := service.MergePatch([]byte(`{"title": "Nova"}`))
+	if _, err := s.IfMatch(3).PatchTask(taskID, patch); !errors.Is(err, service.ErrPreconditionFailed) {
+		t.Errorf("Esperava-se ErrPreconditionFailed para versão antiga, obtido %v", err)
+	}
+	if _, err := s.PatchTask(999, patch); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para tarefa inexistente, obtido %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); task.Title != "Tarefa" || task.Version != 1 {
+		t.Errorf("A tarefa não deveria mudar: %+v", task)
+	}
+}
@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// newUsersWithRoles cadastra um usuário de cada papel e os retorna já com ID e papel preenchidos.
+func newUsersWithRoles(t *testing.T, s service.Service) map[service.Role]service.User {
+	users := make(map[service.Role]service.User)
+
+	// O primeiro usuário cadastrado vira administrador
+	admin := service.User{Name: "Admin", Email: "admin@example.com", Password: "123"}
+	admin.ID, _ = s.RegisterNewUser(admin)
+	admin, _ = s.GetUserByID(admin.ID)
+	if admin.Role != service.RoleAdmin {
+		t.Fatalf("O primeiro usuário deveria ser administrador, obtido: %q", admin.Role)
+	}
+	users[service.RoleAdmin] = admin
+
+	for _, role := range []service.Role{service.RoleManager, service.RoleMember, service.RoleViewer} {
+		user := service.User{Name: string(role), Email: string(role) + "@example.com", Password: "123", Role: role}
+		id, err := s.AsUser(admin).RegisterNewUser(user)
+		if err != nil {
+			t.Fatalf("Erro ao cadastrar usuário %s: %v", role, err)
+		}
+		users[role], _ = s.GetUserByID(id)
+	}
+
+	return users
+}
+
+func TestSelfRegistrationCannotChooseRole(t *testing.T) {
+	s := NewTestService()
+	s.RegisterNewUser(service.User{Name: "Admin", Email: "admin@example.com", Password: "123"})
+
+	_, err := s.RegisterNewUser(service.User{Name: "Esperto", Email: "esperto@example.com", Password: "123", Role: service.RoleAdmin})
+	if !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden ao escolher papel no autocadastro, obtido: %v", err)
+	}
+
+	id, _ := s.RegisterNewUser(service.User{Name: "Normal", Email: "normal@example.com", Password: "123"})
+	user, _ := s.GetUserByID(id)
+	if user.Role != service.RoleMember {
+		t.Errorf("Usuários autocadastrados deveriam ser membros, obtido: %q", user.Role)
+	}
+}
+
+func TestOnlyManagersDeleteTasks(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+
+	for role, allowed := range map[service.Role]bool{
+		service.RoleAdmin:   true,
+		service.RoleManager: true,
+		service.RoleMember:  false,
+		service.RoleViewer:  false,
+	} {
+		taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+
+		err := s.AsUser(users[role]).DeleteTask(taskID)
+		if allowed && err != nil {
+			t.Errorf("%s deveria poder excluir tarefas: %v", role, err)
+		}
+		if !allowed && !errors.Is(err, service.ErrForbidden) {
+			t.Errorf("%s não deveria poder excluir tarefas, obtido: %v", role, err)
+		}
+	}
+}
+
+func TestOnlyAdminsDeleteUsers(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+
+	err := s.AsUser(users[service.RoleManager]).DeleteUser(users[service.RoleViewer].ID)
+	if !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para gerente excluindo usuário, obtido: %v", err)
+	}
+
+	err = s.AsUser(users[service.RoleAdmin]).DeleteUser(users[service.RoleViewer].ID)
+	if err != nil {
+		t.Errorf("Erro inesperado ao administrador excluir usuário: %v", err)
+	}
+}
+
+func TestMembersEditOnlyAssignedTasks(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	assigned, _ := s.CreateTask(service.Task{Title: "Minha", Description: "Atribuída"})
+	s.AssignMemberToTask(assigned, member.ID)
+	other, _ := s.CreateTask(service.Task{Title: "Outra", Description: "Não atribuída"})
+
+	if _, err := s.AsUser(member).EditTask(assigned, service.Task{Title: "Editada", Description: "Atribuída"}); err != nil {
+		t.Errorf("Membro deveria editar tarefa atribuída a ele: %v", err)
+	}
+
+	_, err := s.AsUser(member).EditTask(other, service.Task{Title: "Editada", Description: "Não atribuída"})
+	if !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden ao membro editar tarefa de outro, obtido: %v", err)
+	}
+
+	_, err = s.AsUser(users[service.RoleViewer]).CreateTask(service.Task{Title: "Nova", Description: "Viewer"})
+	if !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden ao visualizador criar tarefa, obtido: %v", err)
+	}
+}
+
+func TestChangeUserRole(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	err := s.AsUser(users[service.RoleManager]).ChangeUserRole(member.ID, service.RoleManager)
+	if !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para gerente alterando papéis, obtido: %v", err)
+	}
+
+	err = s.AsUser(users[service.RoleAdmin]).ChangeUserRole(member.ID, "superuser")
+	if !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para papel inexistente, obtido: %v", err)
+	}
+
+	if err := s.AsUser(users[service.RoleAdmin]).ChangeUserRole(member.ID, service.RoleManager); err != nil {
+		t.Fatalf("Erro inesperado ao alterar papel: %v", err)
+	}
+	updated, _ := s.GetUserByID(member.ID)
+	if updated.Role != service.RoleManager {
+		t.Errorf("O papel não foi alterado, obtido: %q", updated.Role)
+	}
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/services/unit_tests/mock"
+	"go.uber.org/zap"
+)
+
+func TestTaskPriorityCodesAndLabels(t *testing.T) {
+	s := NewTestService()
+
+	// Códigos e nomes exibidos são aceitos; a tarefa guarda o código
+	taskID, err := s.CreateTask(service.Task{Title: "Task", Description: "Description", Priority: "alta"})
+	if err != nil {
+		t.Fatalf("Erro ao criar tarefa: %v", err)
+	}
+	task, _ := s.GetTaskByID(taskID)
+	if task.Priority != "High" {
+		t.Errorf("Esperava-se o código High, obtido %q", task.Priority)
+	}
+
+	if _, err := s.CreateTask(service.Task{Title: "Task", Description: "Description", Priority: "Urgente"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para prioridade desconhecida, obtido %v", err)
+	}
+
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task", Description: "Description", Priority: "Urgente"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao editar com prioridade desconhecida, obtido %v", err)
+	}
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task", Description: "Description", Priority: "Baixa"}); err != nil {
+		t.Fatalf("Erro ao editar tarefa: %v", err)
+	}
+	task, _ = s.GetTaskByID(taskID)
+	if task.Priority != "Low" {
+		t.Errorf("Esperava-se o código Low, obtido %q", task.Priority)
+	}
+
+	// Editar sem prioridade mantém a atual
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task revisada", Description: "Description"}); err != nil {
+		t.Fatalf("Erro ao editar tarefa: %v", err)
+	}
+	task, _ = s.GetTaskByID(taskID)
+	if task.Priority != "Low" {
+		t.Errorf("A prioridade deveria ser mantida, obtido %q", task.Priority)
+	}
+
+	tasks, err := s.FilterTasksByStatusAndPriority("", "média")
+	if err != nil {
+		t.Fatalf("Erro ao filtrar tarefas: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("Nenhuma tarefa tem prioridade Medium, obtido %+v", tasks)
+	}
+	tasks, _ = s.FilterTasksByStatusAndPriority("", "baixa")
+	if len(tasks) != 1 || tasks[0].ID != taskID {
+		t.Errorf("Esperava-se a tarefa filtrada pelo nome exibido, obtido %+v", tasks)
+	}
+}
+
+func TestTaskPrioritySLA(t *testing.T) {
+	priorities := service.DefaultPriorities()
+	priorities[0].SLA = 24 * time.Hour
+	s := service.NewService(mock.NewTestRepository(), zap.NewNop(), service.WithHashParams(testHashParams), service.WithPriorities(priorities))
+
+	taskID, err := s.CreateTask(service.Task{Title: "Task", Description: "Description", Priority: "High"})
+	if err != nil {
+		t.Fatalf("Erro ao criar tarefa: %v", err)
+	}
+	task, _ := s.GetTaskByID(taskID)
+	if task.DueDate == nil || !task.DueDate.Equal(task.CreatedAt.Add(24*time.Hour)) {
+		t.Errorf("Esperava-se o prazo do SLA a partir da criação: %+v", task)
+	}
+
+	// Um prazo informado vence o SLA, e prioridades sem SLA não definem prazo
+	due := time.Now().UTC().Add(72 * time.Hour).Truncate(time.Second)
+	taskID, _ = s.CreateTask(service.Task{Title: "Task", Description: "Description", Priority: "High", DueDate: &due})
+	task, _ = s.GetTaskByID(taskID)
+	if task.DueDate == nil || !task.DueDate.Equal(due) {
+		t.Errorf("Esperava-se o prazo informado, obtido %v", task.DueDate)
+	}
+	taskID, _ = s.CreateTask(service.Task{Title: "Task", Description: "Description", Priority: "Low"})
+	task, _ = s.GetTaskByID(taskID)
+	if task.DueDate != nil {
+		t.Errorf("Prioridade sem SLA não deveria definir prazo, obtido %v", task.DueDate)
+	}
+}
+
+func TestSortTasksByPriority(t *testing.T) {
+	tasks := []service.Task{
+		{ID: 1, Priority: "Low"},
+		{ID: 2},
+		{ID: 3, Priority: "High"},
+		{ID: 4, Priority: "Medium"},
+		{ID: 5, Priority: "High"},
+	}
+
+	service.SortTasksByPriority(tasks, service.DefaultPriorities())
+
+	want := []int{3, 5, 4, 1, 2}
+	for i, task := range tasks {
+		if task.ID != want[i] {
+			t.Fatalf("Ordem inesperada na posição %d: esperado %d, obtido %d", i, want[i], task.ID)
+		}
+	}
+}
+
+func TestPrioritiesValidate(t *testing.T) {
+	if err := service.DefaultPriorities().Validate(); err != nil {
+		t.Errorf("As prioridades padrão deveriam ser válidas: %v", err)
+	}
+
+	invalid := service.Priorities{
+		{Code: "High", Labels: map[string]string{"pt-BR": "Alta"}, Rank: 1},
+		{Code: "high", Rank: 2},
+		{Code: "Top", Labels: map[string]string{"pt-BR": "Alta"}, Rank: 3, SLA: -time.Hour},
+	}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Esperava-se erro para código repetido, nome em comum e SLA negativo")
+	}
+}
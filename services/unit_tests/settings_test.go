@@ -0,0 +1,240 @@
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mclcavalcante/teamTask/config"
+)
+
+func writeSettingsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "teamtask.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Erro ao gravar o arquivo de configuração: %v", err)
+	}
+	return path
+}
+
+func TestLoadSettingsPrecedence(t *testing.T) {
+	path := writeSettingsFile(t, `
+database:
+  dsn: "arquivo:senha@/teamtask"
+  maxOpenConns: 20
+server:
+  address: ":9000"
+log:
+  level: debug
+`)
+	t.Setenv("TEAMTASK_LISTEN_ADDR", ":9100")
+	t.Setenv("TEAMTASK_LOG_LEVEL", "warn")
+
+	settings, args, err := config.LoadSettings([]string{"-config", path, "-log-level", "error", "migrate", "status"})
+	if err != nil {
+		t.Fatalf("Erro ao carregar a configuração: %v", err)
+	}
+
+	// padrão < arquivo < ambiente < flags
+	if settings.Database.DSN != "arquivo:senha@/teamtask" || settings.Database.MaxOpenConns != 20 {
+		t.Errorf("Valores do arquivo não aplicados: %+v", settings.Database)
+	}
+	if settings.Database.MaxIdleConns != 5 || settings.Auth.AccessTokenTTL != 15*time.Minute {
+		t.Errorf("Valores padrão não preservados: %+v", settings)
+	}
+	if settings.Server.Address != ":9100" {
+		t.Errorf("Variável de ambiente deveria sobrepor o arquivo, obtido %q", settings.Server.Address)
+	}
+	if settings.Log.Level != "error" {
+		t.Errorf("Flag deveria sobrepor a variável de ambiente, obtido %q", settings.Log.Level)
+	}
+	if strings.Join(args, " ") != "migrate status" {
+		t.Errorf("Argumentos restantes incorretos: %v", args)
+	}
+}
+
+func TestLoadSettingsReportsEveryInvalidField(t *testing.T) {
+	t.Setenv("TEAMTASK_DB_DSN", "")
+	t.Setenv("TEAMTASK_DB_MAX_IDLE_CONNS", "muitas")
+
+	_, _, err := config.LoadSettings([]string{
+		"-config", writeSettingsFile(t, "log:\n  level: verbose\n"),
+		"-listen", "8000",
+		"-cors-origins", "ftp://exemplo.com",
+		"-token-secret", "curta",
+	})
+	if err == nil {
+		t.Fatal("Esperava-se um erro de configuração inválida")
+	}
+
+	for _, field := range []string{"TEAMTASK_DB_MAX_IDLE_CONNS", "database.dsn", "server.address", "server.corsOrigins", "log.level", "auth.tokenSecret"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Erro não menciona %s: %v", field, err)
+		}
+	}
+}
+
+func TestLoadSettingsRejectsUnknownFileFields(t *testing.T) {
+	t.Setenv("TEAMTASK_DB_DSN", "u:p@/teamtask")
+
+	_, _, err := config.LoadSettings([]string{"-config", writeSettingsFile(t, "server:\n  port: 8000\n")})
+	if err == nil {
+		t.Error("Esperava-se um erro para campo desconhecido no arquivo")
+	}
+}
+
+func TestLoadSettingsWorkflow(t *testing.T) {
+	t.Setenv("TEAMTASK_DB_DSN", "u:p@/teamtask")
+
+	settings, _, err := config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+workflow:
+  initial: Aberto
+  states:
+    - name: Aberto
+    - name: Resolvido
+      completed: true
+  transitions:
+    - {from: Aberto, to: Resolvido, requireResolution: true}
+`)})
+	if err != nil {
+		t.Fatalf("Erro ao carregar a configuração: %v", err)
+	}
+	if len(settings.Workflow.States) != 2 || !settings.Workflow.IsCompleted("resolvido") || len(settings.Workflow.Transitions) != 1 {
+		t.Errorf("Fluxo do arquivo não aplicado: %+v", settings.Workflow)
+	}
+
+	_, _, err = config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+workflow:
+  initial: Novo
+  states:
+    - name: Aberto
+  transitions:
+    - {from: Aberto, to: Fechado}
+`)})
+	if err == nil || !strings.Contains(err.Error(), `"Novo"`) || !strings.Contains(err.Error(), `"Fechado"`) {
+		t.Errorf("Esperava-se erro citando o estado inicial e a transição inválidos, obtido %v", err)
+	}
+}
+
+func TestLoadSettingsPriorities(t *testing.T) {
+	t.Setenv("TEAMTASK_DB_DSN", "u:p@/teamtask")
+
+	settings, _, err := config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+priorities:
+  - {code: P1, labels: {pt-BR: Crítica}, rank: 1, sla: 4h}
+  - {code: P2, rank: 2}
+`)})
+	if err != nil {
+		t.Fatalf("Erro ao carregar a configuração: %v", err)
+	}
+	if len(settings.Priorities) != 2 || settings.Priorities[0].SLA != 4*time.Hour {
+		t.Errorf("Prioridades do arquivo não aplicadas: %+v", settings.Priorities)
+	}
+	if p, ok := settings.Priorities.Lookup("crítica"); !ok || p.Code != "P1" {
+		t.Errorf("Esperava-se encontrar P1 pelo nome exibido, obtido %+v", p)
+	}
+
+	_, _, err = config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+priorities:
+  - {code: P1, rank: 1}
+  - {code: p1, rank: 2}
+`)})
+	if err == nil || !strings.Contains(err.Error(), "priorities") {
+		t.Errorf("Esperava-se erro para código de prioridade repetido, obtido %v", err)
+	}
+}
+
+func TestLoadSettingsSubtasks(t *testing.T) {
+	t.Setenv("TEAMTASK_DB_DSN", "u:p@/teamtask")
+
+	settings, _, err := config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+subtasks:
+  maxDepth: 2
+  requireClosedChildren: false
+`)})
+	if err != nil {
+		t.Fatalf("Erro ao carregar a configuração: %v", err)
+	}
+	if settings.Subtasks.MaxDepth != 2 || settings.Subtasks.RequireClosedChildren {
+		t.Errorf("Política de subtarefas do arquivo não aplicada: %+v", settings.Subtasks)
+	}
+
+	_, _, err = config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+subtasks:
+  maxDepth: 0
+`)})
+	if err == nil || !strings.Contains(err.Error(), "subtasks") {
+		t.Errorf("Esperava-se erro para maxDepth zero, obtido %v", err)
+	}
+}
+
+func TestLoadSettingsAttachments(t *testing.T) {
+	t.Setenv("TEAMTASK_DB_DSN", "u:p@/teamtask")
+	t.Setenv("TEAMTASK_S3_SECRET_KEY", "segredo")
+
+	settings, _, err := config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+attachments:
+  storage: s3
+  maxSize: 1048576
+  allowedTypes: [image/*, application/pdf]
+  s3:
+    endpoint: http://localhost:9000
+    region: us-east-1
+    bucket: anexos
+    accessKey: chave
+`)})
+	if err != nil {
+		t.Fatalf("Erro ao carregar a configuração: %v", err)
+	}
+	attachments := settings.Attachments
+	if attachments.Storage != "s3" || attachments.MaxSize != 1<<20 || len(attachments.AllowedTypes) != 2 ||
+		attachments.S3.Bucket != "anexos" || attachments.S3.SecretKey != "segredo" {
+		t.Errorf("Configuração de anexos do arquivo e do ambiente não aplicada: %+v", attachments)
+	}
+
+	_, _, err = config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+attachments:
+  storage: s3
+`)})
+	if err == nil || !strings.Contains(err.Error(), "attachments.s3") {
+		t.Errorf("Esperava-se erro para S3 sem configuração, obtido %v", err)
+	}
+
+	_, _, err = config.LoadSettings([]string{"-config", writeSettingsFile(t, `
+attachments:
+  storage: ftp
+  maxSize: 0
+`)})
+	if err == nil || !strings.Contains(err.Error(), "attachments.storage") || !strings.Contains(err.Error(), "maxSize") {
+		t.Errorf("Esperava-se erro para armazenamento e tamanho inválidos, obtido %v", err)
+	}
+}
+
+func TestLoadSettingsTrash(t *testing.T) {
+	t.Setenv("TEAMTASK_DB_DSN", "u:p@/teamtask")
+
+	settings, _, err := config.LoadSettings(nil)
+	if err != nil {
+		t.Fatalf("Erro ao carregar a configuração: %v", err)
+	}
+	if settings.Trash.RetentionDays != 30 || settings.Trash.PurgeInterval != time.Hour {
+		t.Errorf("Padrões da lixeira incorretos: %+v", settings.Trash)
+	}
+
+	t.Setenv("TEAMTASK_TRASH_RETENTION_DAYS", "7")
+	settings, _, err = config.LoadSettings([]string{"-trash-purge-interval", "15m"})
+	if err != nil {
+		t.Fatalf("Erro ao carregar a configuração: %v", err)
+	}
+	if settings.Trash.RetentionDays != 7 || settings.Trash.PurgeInterval != 15*time.Minute {
+		t.Errorf("Configuração da lixeira do ambiente e das flags não aplicada: %+v", settings.Trash)
+	}
+
+	t.Setenv("TEAMTASK_TRASH_RETENTION_DAYS", "-1")
+	_, _, err = config.LoadSettings([]string{"-trash-purge-interval", "0s"})
+	if err == nil || !strings.Contains(err.Error(), "trash.retentionDays") {
+		t.Errorf("Esperava-se erro para retenção negativa, obtido %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mclcavalcante/teamTask/migrations"
+	"github.com/mclcavalcante/teamTask/repository"
+	service "github.com/mclcavalcante/teamTask/services"
+	"go.uber.org/zap"
+)
+
+// newSQLiteRepository cria um banco SQLite em um diretório temporário com todas as
+// migrações aplicadas.
+func newSQLiteRepository(t *testing.T) service.Repository {
+	return newSQLRepository(t, repository.SQLite, filepath.Join(t.TempDir(), "teamtask.db"))
+}
+
+// newPostgresRepository usa o banco indicado em TEAMTASK_TEST_POSTGRES_DSN, recriando o
+// esquema do zero. Sem a variável o teste é ignorado.
+func newPostgresRepository(t *testing.T) service.Repository {
+	dsn := os.Getenv("TEAMTASK_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEAMTASK_TEST_POSTGRES_DSN não definida")
+	}
+	return newSQLRepository(t, repository.Postgres, dsn)
+}
+
+// newMySQLRepository usa o banco indicado em TEAMTASK_TEST_MYSQL_DSN (com parseTime=true),
+// recriando o esquema do zero. Sem a variável o teste é ignorado.
+func newMySQLRepository(t *testing.T) service.Repository {
+	dsn := os.Getenv("TEAMTASK_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEAMTASK_TEST_MYSQL_DSN não definida")
+	}
+	return newSQLRepository(t, repository.MySQL, dsn)
+}
+
+func newSQLRepository(t *testing.T, dialect repository.Dialect, dsn string) service.Repository {
+	t.Helper()
+
+	db, err := repository.Open(dialect, dsn)
+	if err != nil {
+		t.Fatalf("Erro ao abrir o banco %s: %v", dialect, err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := migrations.New(db, string(dialect), zap.NewNop())
+	if err != nil {
+		t.Fatalf("Erro ao carregar as migrações: %v", err)
+	}
+	// Bancos compartilhados podem ter dados de execuções anteriores
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Erro ao consultar as migrações: %v", err)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			if _, err := migrator.Down(); err != nil {
+				t.Fatalf("Erro ao desfazer as migrações: %v", err)
+			}
+		}
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Erro ao aplicar as migrações: %v", err)
+	}
+
+	return repository.NewRepository(db, dialect, zap.NewNop())
+}
+
+func TestSQLiteRepositoryServesTheService(t *testing.T) {
+	testRepositoryServesTheService(t, newSQLiteRepository(t))
+}
+
+func TestPostgresRepositoryServesTheService(t *testing.T) {
+	testRepositoryServesTheService(t, newPostgresRepository(t))
+}
+
+func testRepositoryServesTheService(t *testing.T, repo service.Repository) {
+	s := service.NewService(repo, zap.NewNop(), service.WithHashParams(testHashParams))
+
+	userID, err := s.RegisterNewUser(service.User{Name: "User 1", Email: "user1@example.com", Password: "123"})
+	if err != nil {
+		t.Fatalf("Erro ao registrar usuário: %v", err)
+	}
+	if _, err := s.VerifyCredentials("user1@example.com", "123"); err != nil {
+		t.Fatalf("Erro ao verificar credenciais: %v", err)
+	}
+
+	taskID, err := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição", Priority: "Alta", AssignedUsers: []int{userID}})
+	if err != nil {
+		t.Fatalf("Erro ao criar tarefa: %v", err)
+	}
+
+	tasks, err := s.GetVisibleTasksForUser(userID)
+	if err != nil {
+		t.Fatalf("Erro ao listar tarefas: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != taskID {
+		t.Errorf("Tarefas visíveis incorretas: %+v", tasks)
+	}
+
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		t.Fatalf("Erro ao buscar usuário: %v", err)
+	}
+	if _, err := s.AsUser(user).CreateComment(taskID, service.Comment{Text: "Comentário para @user1@example.com"}); err != nil {
+		t.Fatalf("Erro ao comentar: %v", err)
+	}
+
+	if err := s.DeleteTask(taskID); err != nil {
+		t.Fatalf("Erro ao excluir tarefa: %v", err)
+	}
+	if _, err := s.GetTaskByID(taskID); err == nil {
+		t.Error("Esperava-se que a tarefa tivesse sido excluída")
+	}
+}
+
+func TestSQLiteRepositoryRollsBackFailedTaskCreation(t *testing.T) {
+	testRepositoryRollsBackFailedTaskCreation(t, newSQLiteRepository(t))
+}
+
+func TestPostgresRepositoryRollsBackFailedTaskCreation(t *testing.T) {
+	testRepositoryRollsBackFailedTaskCreation(t, newPostgresRepository(t))
+}
+
+func testRepositoryRollsBackFailedTaskCreation(t *testing.T, repo service.Repository) {
+
+	userID, err := repo.AddUser(service.User{Name: "User 1", Email: "user1@example.com", Role: service.RoleMember})
+	if err != nil {
+		t.Fatalf("Erro ao adicionar usuário: %v", err)
+	}
+
+	if _, err := repo.CreateTask(service.Task{Title: "Tarefa", AssignedUsers: []int{userID, 99}}); err == nil {
+		t.Fatal("Esperava-se um erro ao atribuir a tarefa a um usuário inexistente")
+	}
+
+	tasks, err := repo.GetAllTasks()
+	if err != nil {
+		t.Fatalf("Erro ao listar tarefas: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("Esperava-se nenhuma tarefa gravada, obtido %d", len(tasks))
+	}
+}
+
+func TestSQLiteRepositoryReportsDuplicates(t *testing.T) {
+	testRepositoryReportsDuplicates(t, newSQLiteRepository(t))
+}
+
+func TestPostgresRepositoryReportsDuplicates(t *testing.T) {
+	testRepositoryReportsDuplicates(t, newPostgresRepository(t))
+}
+
+func testRepositoryReportsDuplicates(t *testing.T, repo service.Repository) {
+
+	userID, _ := repo.AddUser(service.User{Name: "User 1", Email: "user1@example.com", Role: service.RoleMember})
+	teamID, err := repo.CreateTeam(service.Team{Name: "Equipe"})
+	if err != nil {
+		t.Fatalf("Erro ao criar equipe: %v", err)
+	}
+
+	if err := repo.AddTeamMember(teamID, userID, service.TeamRoleMember); err != nil {
+		t.Fatalf("Erro ao adicionar membro: %v", err)
+	}
+	if err := repo.AddTeamMember(teamID, userID, service.TeamRoleMember); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict, obtido %v", err)
+	}
+}
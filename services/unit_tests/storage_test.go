@@ -0,0 +1,156 @@
+package service_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/storage"
+)
+
+// testBlobStore verifica o contrato de service.BlobStore.
+func testBlobStore(t *testing.T, store service.BlobStore) {
+	t.Helper()
+	content := []byte("conteúdo do anexo")
+
+	if err := store.Put("tasks/1/arquivo", bytes.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, err := store.Get("tasks/1/arquivo")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(got)
+	got.Close()
+	if !bytes.Equal(data, content) {
+		t.Errorf("Get: conteúdo %q, esperado %q", data, content)
+	}
+
+	if err := store.Delete("tasks/1/arquivo"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get("tasks/1/arquivo"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Get depois de Delete: esperado ErrNotFound, obtido %v", err)
+	}
+	if err := store.Delete("tasks/1/arquivo"); err != nil {
+		t.Errorf("Delete de chave inexistente: %v", err)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("Erro ao criar o armazenamento: %v", err)
+	}
+	testBlobStore(t, store)
+
+	if err := store.Put("../fora", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Errorf("Uma chave fora do diretório deveria ser recusada")
+	}
+	// Um envio interrompido não deixa o arquivo pela metade
+	if err := store.Put("tasks/1/curto", strings.NewReader("abc"), 10, "text/plain"); err == nil {
+		t.Errorf("Um conteúdo menor que o informado deveria ser recusado")
+	}
+	if _, err := store.Get("tasks/1/curto"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("O envio recusado não deveria ser gravado, obtido %v", err)
+	}
+}
+
+// authorization é o formato do cabeçalho Authorization do Signature Version 4.
+var authorization = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=chave/\d{8}/sa-east-1/s3/aws4_request, ` +
+	`SignedHeaders=[a-z0-9;-]*host;[a-z0-9;-]*x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`)
+
+// fakeS3 é um substituto em memória do S3, que confere o endereçamento e o formato da
+// assinatura das requisições.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	t       *testing.T
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorization.MatchString(r.Header.Get("Authorization")) || r.Header.Get("X-Amz-Date") == "" {
+		f.t.Errorf("Assinatura em formato inesperado: %q", r.Header.Get("Authorization"))
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/anexos/")
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		if int64(len(data)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = data
+	case http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}, t: t}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := storage.NewS3(storage.S3Config{Endpoint: server.URL, Region: "sa-east-1", Bucket: "anexos", AccessKey: "chave", SecretKey: "segredo"})
+	if err != nil {
+		t.Fatalf("Erro ao criar o armazenamento: %v", err)
+	}
+	testBlobStore(t, store)
+
+	if _, err := storage.NewS3(storage.S3Config{Endpoint: "localhost:9000", Bucket: "anexos"}); err == nil {
+		t.Errorf("Esperava-se erro para configuração incompleta")
+	}
+}
+
+// TestS3StorageEndpoint roda o contrato contra um serviço compatível com o S3 de verdade
+// (ex.: MinIO), indicado por TEAMTASK_TEST_S3_ENDPOINT, _BUCKET, _ACCESS_KEY e _SECRET_KEY.
+// Sem a variável o teste é ignorado.
+func TestS3StorageEndpoint(t *testing.T) {
+	endpoint := os.Getenv("TEAMTASK_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("TEAMTASK_TEST_S3_ENDPOINT não definida")
+	}
+	region := os.Getenv("TEAMTASK_TEST_S3_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	store, err := storage.NewS3(storage.S3Config{
+		Endpoint:  endpoint,
+		Region:    region,
+		Bucket:    os.Getenv("TEAMTASK_TEST_S3_BUCKET"),
+		AccessKey: os.Getenv("TEAMTASK_TEST_S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("TEAMTASK_TEST_S3_SECRET_KEY"),
+	})
+	if err != nil {
+		t.Fatalf("Erro ao criar o armazenamento: %v", err)
+	}
+	testBlobStore(t, store)
+}
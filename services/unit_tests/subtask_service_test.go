@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/services/unit_tests/mock"
+	"go.uber.org/zap"
+)
+
+func TestSubtaskHierarchy(t *testing.T) {
+	s := service.NewService(mock.NewTestRepository(), zap.NewNop(), service.WithHashParams(testHashParams),
+		service.WithSubtaskPolicy(service.SubtaskPolicy{MaxDepth: 3, RequireClosedChildren: true}))
+
+	root, _ := s.CreateTask(service.Task{Title: "Raiz", Description: "Descrição"})
+	child, err := s.CreateTask(service.Task{Title: "Filha", Description: "Descrição", ParentID: root})
+	if err != nil {
+		t.Fatalf("Erro ao criar subtarefa: %v", err)
+	}
+	grandchild, _ := s.CreateTask(service.Task{Title: "Neta", Description: "Descrição", ParentID: child})
+	other, _ := s.CreateTask(service.Task{Title: "Outra", Description: "Descrição"})
+
+	if _, err := s.CreateTask(service.Task{Title: "Bisneta", Description: "Descrição", ParentID: grandchild}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao passar do limite de níveis, obtido %v", err)
+	}
+	if _, err := s.CreateTask(service.Task{Title: "Órfã", Description: "Descrição", ParentID: 999}); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para tarefa pai inexistente, obtido %v", err)
+	}
+
+	// Ciclos e profundidade ao mover
+	if _, err := s.SetTaskParent(root, grandchild); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para ciclo, obtido %v", err)
+	}
+	if _, err := s.SetTaskParent(root, root); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para a tarefa como pai de si mesma, obtido %v", err)
+	}
+	if _, err := s.SetTaskParent(child, other); err != nil {
+		t.Fatalf("Erro ao mover a subárvore: %v", err)
+	}
+	if _, err := s.SetTaskParent(other, root); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation quando a subárvore movida passa do limite, obtido %v", err)
+	}
+	if _, err := s.SetTaskParent(child, root); err != nil {
+		t.Fatalf("Erro ao devolver a subárvore: %v", err)
+	}
+
+	// EditTask não muda a tarefa pai
+	if _, err := s.EditTask(child, service.Task{Title: "Filha editada", Description: "Descrição"}); err != nil {
+		t.Fatalf("Erro ao editar a subtarefa: %v", err)
+	}
+	children, err := s.GetTaskChildren(root)
+	if err != nil {
+		t.Fatalf("Erro ao listar subtarefas: %v", err)
+	}
+	if ids := taskIDs(children); !slices.Equal(ids, []int{child}) || children[0].Title != "Filha editada" {
+		t.Errorf("Subtarefas incorretas: %+v", children)
+	}
+
+	tree, err := s.GetTaskTree(root)
+	if err != nil {
+		t.Fatalf("Erro ao obter a árvore: %v", err)
+	}
+	if len(tree.Children) != 1 || len(tree.Children[0].Children) != 1 || tree.Children[0].Children[0].ID != grandchild {
+		t.Errorf("Árvore incorreta: %+v", tree)
+	}
+
+	if _, err := s.SetTaskParent(child, 0); err != nil {
+		t.Fatalf("Erro ao mover a subtarefa para o primeiro nível: %v", err)
+	}
+	if task, _ := s.GetTaskByID(child); task.ParentID != 0 {
+		t.Errorf("A subtarefa deveria estar no primeiro nível, pai %d", task.ParentID)
+	}
+}
+
+func TestSetTaskParentChecksVersion(t *testing.T) {
+	s := NewTestService()
+
+	root, _ := s.CreateTask(service.Task{Title: "Raiz", Description: "Descrição"})
+	child, _ := s.CreateTask(service.Task{Title: "Filha", Description: "Descrição"})
+
+	moved, err := s.IfMatch(1).SetTaskParent(child, root)
+	if err != nil {
+		t.Fatalf("Erro ao mover a tarefa na versão atual: %v", err)
+	}
+	if moved.Version != 2 || moved.ParentID != root {
+		t.Errorf("Esperava-se a tarefa sob #%d na versão 2, obtido pai %d na versão %d", root, moved.ParentID, moved.Version)
+	}
+	if task, _ := s.GetTaskByID(child); task.Version != 2 {
+		t.Errorf("A versão gravada deveria ser 2, obtido %d", task.Version)
+	}
+
+	if _, err := s.IfMatch(1).SetTaskParent(child, 0); !errors.Is(err, service.ErrPreconditionFailed) {
+		t.Errorf("Esperava-se ErrPreconditionFailed para versão desatualizada, obtido %v", err)
+	}
+	if task, _ := s.GetTaskByID(child); task.ParentID != root {
+		t.Errorf("A tarefa não deveria ter sido movida, pai %d", task.ParentID)
+	}
+}
+
+func TestSubtaskProgressAndClosing(t *testing.T) {
+	s := NewTestService()
+
+	root, _ := s.CreateTask(service.Task{Title: "Raiz", Description: "Descrição"})
+	first, _ := s.CreateTask(service.Task{Title: "Primeira", Description: "Descrição", ParentID: root})
+	second, _ := s.CreateTask(service.Task{Title: "Segunda", Description: "Descrição", ParentID: root})
+	nested, _ := s.CreateTask(service.Task{Title: "Aninhada", Description: "Descrição", ParentID: second})
+	s.CreateTask(service.Task{Title: "Aninhada aberta", Description: "Descrição", ParentID: second})
+
+	if _, err := s.TransitionTask(root, service.TaskTransition{To: "Closed"}); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict ao concluir tarefa com subtarefas abertas, obtido %v", err)
+	}
+	if _, err := s.EditTask(root, service.Task{Title: "Raiz", Description: "Descrição", Status: "Closed"}); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict ao concluir pela edição, obtido %v", err)
+	}
+
+	for _, id := range []int{first, nested} {
+		if _, err := s.TransitionTask(id, service.TaskTransition{To: "Closed"}); err != nil {
+			t.Fatalf("Erro ao concluir subtarefa: %v", err)
+		}
+	}
+
+	// Primeira vale 100 e Segunda, com metade das subtarefas concluídas, vale 50
+	task, _ := s.GetTaskByID(root)
+	if task.Progress == nil || *task.Progress != 75 {
+		t.Errorf("Esperava-se progresso 75, obtido %v", task.Progress)
+	}
+	if task, _ := s.GetTaskByID(first); task.Progress != nil {
+		t.Errorf("Tarefa sem subtarefas não deveria ter progresso, obtido %v", *task.Progress)
+	}
+
+	relaxed := service.NewService(mock.NewTestRepository(), zap.NewNop(), service.WithHashParams(testHashParams),
+		service.WithSubtaskPolicy(service.SubtaskPolicy{MaxDepth: 2}))
+	parent, _ := relaxed.CreateTask(service.Task{Title: "Pai", Description: "Descrição"})
+	relaxed.CreateTask(service.Task{Title: "Aberta", Description: "Descrição", ParentID: parent})
+	if _, err := relaxed.TransitionTask(parent, service.TaskTransition{To: "Closed"}); err != nil {
+		t.Errorf("Sem a exigência a tarefa deveria ser concluída com subtarefas abertas: %v", err)
+	}
+}
@@ -1,7 +1,11 @@
 package service_test
 
 import (
+	"errors"
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	service "github.com/mclcavalcante/teamTask/services"
@@ -9,23 +13,28 @@ import (
 	"go.uber.org/zap"
 )
 
+// testHashParams usa um custo baixo para que os testes não fiquem lentos.
+var testHashParams = service.HashParams{Algorithm: service.AlgorithmBcrypt, Cost: 4}
+
 func NewTestService() service.Service {
 	repo := mock.NewTestRepository()
 	logger := zap.NewNop()
-	return service.NewService(repo, logger)
+	return service.NewService(repo, logger, service.WithHashParams(testHashParams))
 }
 
 func TestCreateTask(t *testing.T) {
 	// Simulando um ambiente de teste, por exemplo,
 	// criando uma instância de banco de dados em memória
 	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	assignees := []int{users[service.RoleManager].ID, users[service.RoleMember].ID}
 
 	// Criando um mock de entrada para o serviço de criação de tarefa
 	input := service.Task{
 		Title:         "Test Task",
 		Description:   "This is a test task",
 		Priority:      "High",
-		AssignedUsers: []int{0, 1, 2},
+		AssignedUsers: assignees,
 	}
 
 	// Chamando o serviço de criação de tarefa
@@ -33,7 +42,7 @@ func TestCreateTask(t *testing.T) {
 
 	// Verificando se houve algum erro na criação da tarefa
 	if err != nil {
-		t.Errorf("Erro ao criar tarefa: %v", err)
+		t.Fatalf("Erro ao criar tarefa: %v", err)
 	}
 
 	// Verificando se a tarefa foi atribuída com sucesso e possui um ID válido
@@ -41,22 +50,22 @@ func TestCreateTask(t *testing.T) {
 		t.Error("ID de tarefa inválido")
 	}
 
-	//TODO
-
 	// Verificando se a tarefa criada existe no banco de dados
-	// task, err := s.GetTaskByID(taskID)
-	// if err != nil {
-	// 	t.Errorf("Erro ao buscar tarefa do banco de dados: %v", err)
-	// }
+	task, err := s.GetTaskByID(taskID)
+	if err != nil {
+		t.Fatalf("Erro ao buscar tarefa do banco de dados: %v", err)
+	}
 
-	// // Comparando os atributos da tarefa criada com os valores esperados
-	// if task.Title != input.Title {
-	// 	t.Errorf("Título da tarefa não corresponde: esperado %s, obtido %s", input.Title, task.Title)
-	// }
-	// if task.Description != input.Description {
-	// 	t.Errorf("Descrição da tarefa não corresponde: esperado %s, obtido %s", input.Description, task.Description)
-	// }
-	// Verifique outras propriedades da tarefa, como prioridade e membros da equipe, da mesma maneira
+	// Comparando os atributos da tarefa criada com os valores esperados
+	if task.Title != input.Title {
+		t.Errorf("Título da tarefa não corresponde: esperado %s, obtido %s", input.Title, task.Title)
+	}
+	if task.Description != input.Description {
+		t.Errorf("Descrição da tarefa não corresponde: esperado %s, obtido %s", input.Description, task.Description)
+	}
+	if task.Priority != input.Priority || !slices.Equal(task.AssignedUsers, assignees) {
+		t.Errorf("Prioridade ou responsáveis não correspondem: %s, %v", task.Priority, task.AssignedUsers)
+	}
 }
 
 func TestCreateTaskWithoutTitleOrDescription(t *testing.T) {
@@ -107,6 +116,52 @@ func TestCreateTaskWithoutTeamMembers(t *testing.T) {
 	}
 }
 
+func TestCreateTaskIsAtomic(t *testing.T) {
+	s := NewTestService()
+
+	userID, err := s.RegisterNewUser(service.User{Name: "User 1", Email: "user1@example.com", Password: "123"})
+	if err != nil {
+		t.Fatalf("Erro ao registrar usuário: %v", err)
+	}
+
+	// O segundo usuário não existe: nem a tarefa nem a primeira atribuição devem ficar gravadas
+	input := service.Task{Title: "Nova Tarefa", Description: "Descrição da tarefa", AssignedUsers: []int{userID, 99}}
+	if _, err := s.CreateTask(input); err == nil {
+		t.Fatal("Esperava-se um erro ao atribuir a tarefa a um usuário inexistente")
+	}
+
+	tasks, err := s.GetAllTasks()
+	if err != nil {
+		t.Fatalf("Erro ao listar tarefas: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("Esperava-se nenhuma tarefa gravada, obtido %d", len(tasks))
+	}
+}
+
+func TestWithinTransactionRollsBack(t *testing.T) {
+	repo := mock.NewTestRepository()
+
+	err := repo.WithinTransaction(func(tx service.Repository) error {
+		if _, err := tx.CreateTeam(service.Team{Name: "Equipe"}); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"}); err != nil {
+			return err
+		}
+		return errors.New("falha no meio da operação")
+	})
+	if err == nil {
+		t.Fatal("Esperava-se o erro retornado pela transação")
+	}
+
+	teams, _ := repo.GetAllTeams()
+	tasks, _ := repo.GetAllTasks()
+	if len(teams) != 0 || len(tasks) != 0 {
+		t.Errorf("Esperava-se que a transação fosse desfeita, obtido %d equipes e %d tarefas", len(teams), len(tasks))
+	}
+}
+
 func TestGetVisibleTasksForUser(t *testing.T) {
 	// Mock do banco de dados
 	s := NewTestService()
@@ -174,20 +229,35 @@ func TestFilterTasksByStatusAndPriority(t *testing.T) {
 	}
 }
 
-func TestFilterTasksForInvalidUser(t *testing.T) {
+func TestFilterTasksWithInvalidPriority(t *testing.T) {
 	// Mock do banco de dados
 	s := NewTestService()
+	s.CreateTask(service.Task{Title: "Task 1", Description: "Description for Task 1", Priority: "High"})
 
-	// Tentar filtrar tarefas para um usuário inválido (ID não existente)
-	_, err := s.FilterTasksByStatusAndPriority("", "")
-	if err == nil {
-		t.Error("Esperava-se um erro ao filtrar tarefas para um usuário inválido")
+	// Um filtro vazio retorna todas as tarefas
+	tasks, err := s.FilterTasksByStatusAndPriority("", "")
+	if err != nil || len(tasks) != 1 {
+		t.Errorf("Esperava-se a tarefa cadastrada com o filtro vazio: %v %v", tasks, err)
+	}
+
+	// Tentar filtrar tarefas por uma prioridade que não existe
+	if _, err := s.FilterTasksByStatusAndPriority("", "Urgente"); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao filtrar por prioridade inválida, obtido %v", err)
 	}
 }
 
+// failingRepository simula um banco de dados que falha ao listar as tarefas.
+type failingRepository struct {
+	service.Repository
+}
+
+func (failingRepository) GetAllTasks() ([]service.Task, error) {
+	return nil, errors.New("conexão perdida")
+}
+
 func TestFilterTasksWithDatabaseError(t *testing.T) {
 	// Mock do banco de dados que sempre retorna um erro
-	s := NewTestService()
+	s := service.NewService(failingRepository{mock.NewTestRepository()}, zap.NewNop(), service.WithHashParams(testHashParams))
 
 	// Tentar filtrar tarefas com um banco de dados que sempre retorna um erro
 	_, err := s.FilterTasksByStatusAndPriority("", "")
@@ -323,7 +393,7 @@ func TestEditTask(t *testing.T) {
 	newDescription := "New description for Task 1"
 
 	// Editar a tarefa
-	err := s.EditTask(1, service.Task{Description: newDescription})
+	_, err := s.EditTask(1, service.Task{Title: "Task 1", Description: newDescription})
 	if err != nil {
 		t.Errorf("Erro inesperado ao editar a tarefa: %v", err)
 	}
@@ -343,12 +413,52 @@ func TestEditNonExistentTask(t *testing.T) {
 	s := NewTestService()
 
 	// Tentar editar uma tarefa inexistente
-	err := s.EditTask(999, service.Task{})
+	_, err := s.EditTask(999, service.Task{Title: "Task", Description: "Description"})
 	if err == nil {
 		t.Error("Esperava-se um erro ao tentar editar uma tarefa inexistente")
 	}
 }
 
+func TestEditTaskRequiresTitleAndDescription(t *testing.T) {
+	s := NewTestService()
+	taskID, _ := s.CreateTask(service.Task{Title: "Task 1", Description: "Description for Task 1"})
+
+	// Título e descrição são obrigatórios no PUT; vazios não apagam os atuais
+	for _, edit := range []service.Task{{Title: "Task 1"}, {Description: "Description"}, {Title: " ", Description: "Description"}} {
+		if _, err := s.EditTask(taskID, edit); !errors.Is(err, service.ErrValidation) {
+			t.Errorf("Esperava-se ErrValidation para %+v, obtido %v", edit, err)
+		}
+	}
+	if task, _ := s.GetTaskByID(taskID); task.Title != "Task 1" || task.Version != 1 {
+		t.Errorf("A tarefa não deveria mudar: %+v", task)
+	}
+}
+
+func TestEditTaskKeepsOmittedFields(t *testing.T) {
+	s := NewTestService()
+	teamID, _ := s.CreateTeam(service.Team{Name: "Plataforma"})
+	labelID, _ := s.CreateLabel(service.Label{TeamID: teamID, Name: "bug"})
+	due := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	taskID, _ := s.CreateTask(service.Task{Title: "Task 1", Description: "Description", TeamID: teamID, DueDate: &due})
+	if err := s.AddTaskLabel(taskID, labelID); err != nil {
+		t.Fatalf("Erro ao aplicar etiqueta: %v", err)
+	}
+
+	// Sem teamId e prazo no PUT, a tarefa continua na equipe, com as etiquetas e o prazo
+	task, err := s.EditTask(taskID, service.Task{Title: "Task 1 revisada", Description: "Description"})
+	if err != nil {
+		t.Fatalf("Erro ao editar a tarefa: %v", err)
+	}
+	if task.TeamID != teamID || len(task.Labels) != 1 || task.DueDate == nil || !task.DueDate.Equal(due) {
+		t.Errorf("Os campos omitidos deveriam continuar os mesmos: %+v", task)
+	}
+
+	events, _ := s.GetTaskHistory(taskID)
+	if changes := events[len(events)-1].Changes; len(changes) != 1 || changes[0].Field != "title" {
+		t.Errorf("O histórico deveria ter apenas o título: %+v", changes)
+	}
+}
+
 func TestEditTaskTitle(t *testing.T) {
 	// Mock do banco de dados
 	s := NewTestService()
@@ -361,7 +471,7 @@ func TestEditTaskTitle(t *testing.T) {
 	newTitle := "New Task Title"
 
 	// Editar o título da tarefa
-	err := s.EditTask(1, service.Task{Title: newTitle})
+	_, err := s.EditTask(1, service.Task{Title: newTitle, Description: "Description for Task 1"})
 	if err != nil {
 		t.Errorf("Erro inesperado ao editar o título da tarefa: %v", err)
 	}
@@ -388,7 +498,7 @@ func TestEditTaskPriority(t *testing.T) {
 	newPriority := "Low"
 
 	// Editar a prioridade da tarefa
-	err := s.EditTask(1, service.Task{Priority: newPriority})
+	_, err := s.EditTask(1, service.Task{Title: "Task 1", Description: "Description for Task 1", Priority: newPriority})
 	if err != nil {
 		t.Errorf("Erro inesperado ao editar a prioridade da tarefa: %v", err)
 	}
@@ -415,7 +525,7 @@ func TestEditTaskStatus(t *testing.T) {
 	newStatus := "Closed"
 
 	// Editar o status da tarefa
-	err := s.EditTask(1, service.Task{Status: newStatus})
+	_, err := s.EditTask(1, service.Task{Title: "Task 1", Description: "Description for Task 1", Status: newStatus})
 	if err != nil {
 		t.Errorf("Erro inesperado ao editar o status da tarefa: %v", err)
 	}
@@ -429,3 +539,258 @@ func TestEditTaskStatus(t *testing.T) {
 		t.Errorf("O status da tarefa não foi editado corretamente. Esperado: %s, Obtido: %s", newStatus, editedTask.Status)
 	}
 }
+
+func TestCreateTaskRecordsDates(t *testing.T) {
+	s := NewTestService()
+
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	due := start.AddDate(0, 0, 14)
+	taskID, err := s.CreateTask(service.Task{Title: "Task 1", Description: "Description", StartDate: &start, DueDate: &due})
+	if err != nil {
+		t.Fatalf("Erro ao criar tarefa: %v", err)
+	}
+
+	task, err := s.GetTaskByID(taskID)
+	if err != nil {
+		t.Fatalf("Erro ao obter tarefa: %v", err)
+	}
+	if task.StartDate == nil || !task.StartDate.Equal(start) || task.DueDate == nil || !task.DueDate.Equal(due) {
+		t.Errorf("Datas de início e prazo incorretas: %v, %v", task.StartDate, task.DueDate)
+	}
+	if task.CreatedAt.IsZero() || !task.UpdatedAt.Equal(task.CreatedAt) {
+		t.Errorf("Datas de criação e alteração incorretas: %v, %v", task.CreatedAt, task.UpdatedAt)
+	}
+	if task.CompletedAt != nil {
+		t.Errorf("Tarefa aberta não deveria ter data de conclusão: %v", task.CompletedAt)
+	}
+
+	_, err = s.CreateTask(service.Task{Title: "Task 2", Description: "Description", StartDate: &due, DueDate: &start})
+	if !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para início depois do prazo, obtido %v", err)
+	}
+}
+
+func TestEditTaskTracksCompletion(t *testing.T) {
+	s := NewTestService()
+
+	taskID, err := s.CreateTask(service.Task{Title: "Task 1", Description: "Description", Status: "Open"})
+	if err != nil {
+		t.Fatalf("Erro ao criar tarefa: %v", err)
+	}
+	created, _ := s.GetTaskByID(taskID)
+
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task 1", Description: "Description", Status: "Closed"}); err != nil {
+		t.Fatalf("Erro ao concluir tarefa: %v", err)
+	}
+	closed, _ := s.GetTaskByID(taskID)
+	if closed.CompletedAt == nil {
+		t.Fatal("Esperava-se a data de conclusão")
+	}
+	if !closed.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("A data de criação mudou: %v → %v", created.CreatedAt, closed.CreatedAt)
+	}
+
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task 1", Description: "Description", Status: "Open"}); err != nil {
+		t.Fatalf("Erro ao reabrir tarefa: %v", err)
+	}
+	reopened, _ := s.GetTaskByID(taskID)
+	if reopened.CompletedAt != nil {
+		t.Errorf("Tarefa reaberta não deveria ter data de conclusão: %v", reopened.CompletedAt)
+	}
+}
+
+func TestOverdueAndUpcomingTasks(t *testing.T) {
+	s := NewTestService()
+
+	userID, err := s.RegisterNewUser(service.User{Name: "User 1", Email: "user1@example.com", Password: "123"})
+	if err != nil {
+		t.Fatalf("Erro ao registrar usuário: %v", err)
+	}
+
+	now := time.Now().UTC()
+	createDue := func(title string, due time.Time, status string) int {
+		t.Helper()
+		taskID, err := s.CreateTask(service.Task{Title: title, Description: "Description", Status: status, DueDate: &due, AssignedUsers: []int{userID}})
+		if err != nil {
+			t.Fatalf("Erro ao criar tarefa: %v", err)
+		}
+		return taskID
+	}
+	lastWeek := createDue("Atrasada há uma semana", now.AddDate(0, 0, -7), "Open")
+	yesterday := createDue("Atrasada desde ontem", now.AddDate(0, 0, -1), "Open")
+	createDue("Concluída", now.AddDate(0, 0, -2), "Closed")
+	inThreeDays := createDue("Em três dias", now.AddDate(0, 0, 3), "Open")
+	tomorrow := createDue("Amanhã", now.AddDate(0, 0, 1), "Open")
+	createDue("Em dez dias", now.AddDate(0, 0, 10), "Open")
+
+	overdue, err := s.GetOverdueTasks()
+	if err != nil {
+		t.Fatalf("Erro ao listar tarefas atrasadas: %v", err)
+	}
+	if ids := taskIDs(overdue); !slices.Equal(ids, []int{lastWeek, yesterday}) {
+		t.Errorf("Tarefas atrasadas incorretas: %v", ids)
+	}
+
+	upcoming, err := s.GetUpcomingTasksForUser(userID, 7)
+	if err != nil {
+		t.Fatalf("Erro ao listar próximas tarefas: %v", err)
+	}
+	if ids := taskIDs(upcoming); !slices.Equal(ids, []int{tomorrow, inThreeDays}) {
+		t.Errorf("Próximas tarefas incorretas: %v", ids)
+	}
+
+	if _, err := s.GetUpcomingTasksForUser(userID, 0); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para zero dias, obtido %v", err)
+	}
+}
+
+func TestTaskVersion(t *testing.T) {
+	s := NewTestService()
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+
+	task, err := s.GetTaskByID(taskID)
+	if err != nil || task.Version != 1 {
+		t.Fatalf("A tarefa criada deveria estar na versão 1: %+v %v", task, err)
+	}
+	edited, err := s.IfMatch(1).EditTask(taskID, service.Task{Title: "Editada", Description: "Descrição"})
+	if err != nil || edited.Version != 2 || edited.Title != "Editada" {
+		t.Fatalf("A edição deveria retornar a tarefa na versão 2: %+v %v", edited, err)
+	}
+	moved, err := s.TransitionTask(taskID, service.TaskTransition{To: "Closed"})
+	if err != nil || moved.Version != 3 {
+		t.Fatalf("A transição deveria retornar a versão 3: %+v %v", moved, err)
+	}
+
+	// Quem leu a versão 1 não sobrescreve as mudanças dos outros
+	_, err = s.IfMatch(1).EditTask(taskID, service.Task{Title: "Antiga", Description: "Descrição"})
+	var stale *service.VersionError
+	if !errors.Is(err, service.ErrPreconditionFailed) || !errors.As(err, &stale) || stale.Current != 3 {
+		t.Fatalf("Esperava-se ErrPreconditionFailed com a versão 3, obtido %v", err)
+	}
+	if err := s.IfMatch(2).DeleteTask(taskID); !errors.Is(err, service.ErrPreconditionFailed) {
+		t.Errorf("Esperava-se ErrPreconditionFailed ao excluir, obtido %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); task.Title != "Editada" || task.Version != 3 {
+		t.Errorf("A tarefa não deveria mudar: %+v", task)
+	}
+
+	if err := s.IfMatch(3).DeleteTask(taskID); err != nil {
+		t.Errorf("Erro ao excluir na versão atual: %v", err)
+	}
+}
+
+func TestEditTaskAssignees(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	manager, member := users[service.RoleManager], users[service.RoleMember]
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição", AssignedUsers: []int{member.ID}})
+
+	task, err := s.GetTaskByID(taskID)
+	if err != nil || !slices.Equal(task.AssignedUsers, []int{member.ID}) {
+		t.Fatalf("A tarefa deveria trazer os responsáveis: %+v %v", task, err)
+	}
+
+	// Sem a lista, os responsáveis continuam os mesmos
+	if _, err := s.EditTask(taskID, service.Task{Title: "Tarefa", Description: "Descrição"}); err != nil {
+		t.Fatalf("Erro ao editar tarefa: %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); !slices.Equal(task.AssignedUsers, []int{member.ID}) {
+		t.Errorf("Os responsáveis não deveriam mudar: %v", task.AssignedUsers)
+	}
+
+	if _, err := s.EditTask(taskID, service.Task{Title: "Tarefa", Description: "Descrição", AssignedUsers: []int{manager.ID, manager.ID}}); err != nil {
+		t.Fatalf("Erro ao trocar os responsáveis: %v", err)
+	}
+	tasks, _ := s.GetAllTasks()
+	if len(tasks) != 1 || !slices.Equal(tasks[0].AssignedUsers, []int{manager.ID}) {
+		t.Errorf("Os responsáveis deveriam ser trocados: %+v", tasks)
+	}
+
+	// Um membro edita as tarefas atribuídas a ele, mas não troca os responsáveis
+	s.AssignMemberToTask(taskID, member.ID)
+	_, err = s.AsUser(member).EditTask(taskID, service.Task{Title: "Tarefa", Description: "Descrição", AssignedUsers: []int{member.ID}})
+	if !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para membro, obtido %v", err)
+	}
+	if _, err := s.EditTask(taskID, service.Task{Title: "Tarefa", Description: "Descrição", AssignedUsers: []int{999}}); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para usuário inexistente, obtido %v", err)
+	}
+	if _, err := s.EditTask(taskID, service.Task{Title: "Tarefa", Description: "Descrição", AssignedUsers: []int{}}); err != nil {
+		t.Fatalf("Erro ao retirar os responsáveis: %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); task.AssignedUsers == nil || len(task.AssignedUsers) != 0 {
+		t.Errorf("A tarefa deveria ficar sem responsáveis: %v", task.AssignedUsers)
+	}
+
+	events, _ := s.GetTaskHistory(taskID)
+	want := []service.TaskEventKind{service.TaskCreated, service.TaskUnassigned, service.TaskAssigned, service.TaskAssigned,
+		service.TaskUnassigned, service.TaskUnassigned}
+	if kinds := eventKinds(events); !slices.Equal(kinds, want) {
+		t.Errorf("Eventos incorretos: %v", kinds)
+	}
+}
+
+func TestAssignmentChangesTaskVersion(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember].ID
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+
+	if err := s.AssignMemberToTask(taskID, member); err != nil {
+		t.Fatalf("Erro ao atribuir tarefa: %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); task.Version != 2 {
+		t.Fatalf("A atribuição deveria avançar a versão: %+v", task)
+	}
+
+	// Quem leu a versão 1 não desfaz a atribuição feita depois
+	_, err := s.IfMatch(1).EditTask(taskID, service.Task{Title: "Tarefa", Description: "Descrição", AssignedUsers: []int{}})
+	if !errors.Is(err, service.ErrPreconditionFailed) {
+		t.Fatalf("Esperava-se ErrPreconditionFailed, obtido %v", err)
+	}
+	if err := s.IfMatch(1).UnassignMemberFromTask(taskID, member); !errors.Is(err, service.ErrPreconditionFailed) {
+		t.Errorf("Esperava-se ErrPreconditionFailed ao retirar, obtido %v", err)
+	}
+	if err := s.IfMatch(2).UnassignMemberFromTask(taskID, member); err != nil {
+		t.Fatalf("Erro ao retirar o responsável: %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); task.Version != 3 || len(task.AssignedUsers) != 0 {
+		t.Errorf("A retirada deveria avançar a versão: %+v", task)
+	}
+}
+
+func TestUnassignMemberFromTask(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição", AssignedUsers: []int{member.ID}})
+
+	if err := s.AsUser(member).UnassignMemberFromTask(taskID, member.ID); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para membro, obtido %v", err)
+	}
+	if err := s.AsUser(users[service.RoleManager]).UnassignMemberFromTask(taskID, member.ID); err != nil {
+		t.Fatalf("Erro ao retirar o responsável: %v", err)
+	}
+	if task, _ := s.GetTaskByID(taskID); len(task.AssignedUsers) != 0 {
+		t.Errorf("A tarefa deveria ficar sem responsáveis: %v", task.AssignedUsers)
+	}
+	if err := s.UnassignMemberFromTask(taskID, member.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para usuário não atribuído, obtido %v", err)
+	}
+	if err := s.UnassignMemberFromTask(999, member.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para tarefa inexistente, obtido %v", err)
+	}
+
+	events, _ := s.GetTaskHistory(taskID)
+	if last := events[len(events)-1]; last.Kind != service.TaskUnassigned || last.Changes[0].Before != strconv.Itoa(member.ID) {
+		t.Errorf("A retirada deveria entrar no histórico: %+v", last)
+	}
+}
+
+func taskIDs(tasks []service.Task) []int {
+	var ids []int
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
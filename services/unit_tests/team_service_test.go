@@ -0,0 +1,119 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+func TestCreateTeamMakesCreatorLead(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	manager := users[service.RoleManager]
+
+	teamID, err := s.AsUser(manager).CreateTeam(service.Team{Name: "Backend"})
+	if err != nil {
+		t.Fatalf("Erro inesperado ao criar equipe: %v", err)
+	}
+
+	team, err := s.GetTeam(teamID)
+	if err != nil {
+		t.Fatalf("Erro ao obter equipe: %v", err)
+	}
+	if team.Name != "Backend" || len(team.Members) != 1 || team.Members[0].UserID != manager.ID || team.Members[0].Role != service.TeamRoleLead {
+		t.Errorf("Equipe criada não corresponde à esperada: %+v", team)
+	}
+
+	_, err = s.AsUser(users[service.RoleMember]).CreateTeam(service.Team{Name: "Frontend"})
+	if !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden ao membro criar equipe, obtido: %v", err)
+	}
+}
+
+func TestTeamLeadManagesOwnTeamOnly(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	own, _ := s.CreateTeam(service.Team{Name: "Própria"})
+	other, _ := s.CreateTeam(service.Team{Name: "Outra"})
+	s.AddTeamMember(own, member.ID, service.TeamRoleLead)
+
+	if err := s.AsUser(member).RenameTeam(own, "Renomeada"); err != nil {
+		t.Errorf("Líder deveria renomear a própria equipe: %v", err)
+	}
+	if err := s.AsUser(member).AddTeamMember(own, users[service.RoleViewer].ID, ""); err != nil {
+		t.Errorf("Líder deveria adicionar membros à própria equipe: %v", err)
+	}
+
+	err := s.AsUser(member).RenameTeam(other, "Invadida")
+	if !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden ao renomear equipe alheia, obtido: %v", err)
+	}
+}
+
+func TestArchivedTeamRejectsNewTasksAndMembers(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+
+	teamID, _ := s.CreateTeam(service.Team{Name: "Antiga"})
+	if err := s.SetTeamArchived(teamID, true); err != nil {
+		t.Fatalf("Erro inesperado ao arquivar equipe: %v", err)
+	}
+
+	_, err := s.CreateTask(service.Task{Title: "Task", Description: "Description", TeamID: teamID})
+	if !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao criar tarefa em equipe arquivada, obtido: %v", err)
+	}
+
+	err = s.AddTeamMember(teamID, users[service.RoleMember].ID, service.TeamRoleMember)
+	if !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao adicionar membro em equipe arquivada, obtido: %v", err)
+	}
+}
+
+func TestRemoveTeamMember(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	teamID, _ := s.CreateTeam(service.Team{Name: "Backend"})
+	s.AddTeamMember(teamID, member.ID, service.TeamRoleMember)
+
+	if err := s.RemoveTeamMember(teamID, member.ID); err != nil {
+		t.Fatalf("Erro inesperado ao remover membro: %v", err)
+	}
+
+	team, _ := s.GetTeam(teamID)
+	if len(team.Members) != 0 {
+		t.Errorf("O membro não foi removido: %+v", team.Members)
+	}
+
+	if err := s.RemoveTeamMember(teamID, member.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound ao remover quem não é membro, obtido: %v", err)
+	}
+}
+
+func TestVisibleTasksIncludeTeamTasks(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+
+	teamID, _ := s.CreateTeam(service.Team{Name: "Backend"})
+	s.AddTeamMember(teamID, member.ID, service.TeamRoleMember)
+
+	direct, _ := s.CreateTask(service.Task{Title: "Direta", Description: "Atribuída", AssignedUsers: []int{member.ID}})
+	fromTeam, _ := s.CreateTask(service.Task{Title: "Da equipe", Description: "Equipe", TeamID: teamID})
+	both, _ := s.CreateTask(service.Task{Title: "Ambas", Description: "Equipe e atribuída", TeamID: teamID, AssignedUsers: []int{member.ID}})
+	s.CreateTask(service.Task{Title: "Alheia", Description: "Sem relação"})
+
+	tasks, err := s.GetVisibleTasksForUser(member.ID)
+	if err != nil {
+		t.Fatalf("Erro inesperado ao obter tarefas visíveis: %v", err)
+	}
+
+	if len(tasks) != 3 || tasks[0].ID != direct || tasks[1].ID != fromTeam || tasks[2].ID != both {
+		t.Errorf("Tarefas visíveis não correspondem às esperadas: %+v", tasks)
+	}
+}
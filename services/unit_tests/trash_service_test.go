@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+func TestTrashAndRestoreTask(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	manager := s.AsUser(users[service.RoleManager])
+
+	parentID, _ := s.CreateTask(service.Task{Title: "Pai", Description: "Descrição"})
+	childID, _ := s.CreateTask(service.Task{Title: "Filha", Description: "Descrição", ParentID: parentID})
+	s.AssignMemberToTask(parentID, users[service.RoleMember].ID)
+
+	if err := s.AsUser(users[service.RoleMember]).DeleteTask(parentID); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para membro, obtido %v", err)
+	}
+	if err := manager.DeleteTask(parentID); err != nil {
+		t.Fatalf("Erro ao excluir a tarefa: %v", err)
+	}
+	if _, err := s.GetTaskByID(parentID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("A tarefa na lixeira não deveria aparecer, obtido %v", err)
+	}
+	// As subtarefas não vão para a lixeira com a tarefa pai
+	if child, err := s.GetTaskByID(childID); err != nil || child.ParentID != 0 {
+		t.Errorf("A subtarefa deveria passar ao primeiro nível: %+v %v", child, err)
+	}
+
+	if _, err := s.AsUser(users[service.RoleMember]).ListTrashedTasks(); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para membro, obtido %v", err)
+	}
+	trashed, err := manager.ListTrashedTasks()
+	if err != nil {
+		t.Fatalf("Erro ao listar a lixeira: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != parentID || trashed[0].DeletedAt == nil {
+		t.Fatalf("Lixeira incorreta: %+v", trashed)
+	}
+
+	if err := manager.RestoreTask(parentID); err != nil {
+		t.Fatalf("Erro ao restaurar a tarefa: %v", err)
+	}
+	task, err := s.GetTaskByID(parentID)
+	if err != nil || task.DeletedAt != nil {
+		t.Fatalf("A tarefa restaurada deveria voltar: %+v %v", task, err)
+	}
+	if err := manager.RestoreTask(parentID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para tarefa fora da lixeira, obtido %v", err)
+	}
+
+	events, _ := s.GetTaskHistory(parentID)
+	want := []service.TaskEventKind{service.TaskCreated, service.TaskAssigned, service.TaskDeleted, service.TaskRestored}
+	if kinds := eventKinds(events); !slices.Equal(kinds, want) {
+		t.Errorf("Eventos incorretos: %v", kinds)
+	}
+}
+
+func TestRestoreTaskWithTrashedParent(t *testing.T) {
+	s := NewTestService()
+	parentID, _ := s.CreateTask(service.Task{Title: "Pai", Description: "Descrição"})
+	childID, _ := s.CreateTask(service.Task{Title: "Filha", Description: "Descrição", ParentID: parentID})
+
+	s.DeleteTask(childID)
+	s.DeleteTask(parentID)
+	if err := s.RestoreTask(childID); err != nil {
+		t.Fatalf("Erro ao restaurar a tarefa: %v", err)
+	}
+
+	child, _ := s.GetTaskByID(childID)
+	if child.ParentID != 0 {
+		t.Errorf("A tarefa pai está na lixeira; a subtarefa deveria voltar no primeiro nível: %+v", child)
+	}
+}
+
+func TestRestoreTaskWithRemovedStatus(t *testing.T) {
+	s := NewTestService()
+	board := service.Workflow{Name: "Quadro", Initial: "Todo",
+		States:      []service.WorkflowState{{Name: "Todo"}, {Name: "Doing"}, {Name: "Done", Completed: true}},
+		Transitions: []service.WorkflowTransition{{From: "Todo", To: "Doing"}, {From: "Doing", To: "Done"}}}
+	boardID, _ := s.CreateWorkflow(board)
+	teamID, _ := s.CreateTeam(service.Team{Name: "Plataforma"})
+	if err := s.AssignWorkflow(service.WorkflowAssignment{WorkflowID: boardID, TeamID: teamID}, nil); err != nil {
+		t.Fatalf("Erro ao atribuir fluxo: %v", err)
+	}
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição", TeamID: teamID})
+	if _, err := s.TransitionTask(taskID, service.TaskTransition{To: "Doing"}); err != nil {
+		t.Fatalf("Erro ao mudar status: %v", err)
+	}
+	s.DeleteTask(taskID)
+
+	// Sem tarefas fora da lixeira no status, a remoção não pede statusMapping
+	board.States = []service.WorkflowState{{Name: "Todo"}, {Name: "Done", Completed: true}}
+	board.Transitions = []service.WorkflowTransition{{From: "Todo", To: "Done"}}
+	if err := s.UpdateWorkflow(boardID, board, nil); err != nil {
+		t.Fatalf("Erro ao alterar fluxo: %v", err)
+	}
+
+	if err := s.RestoreTask(taskID); err != nil {
+		t.Fatalf("Erro ao restaurar a tarefa: %v", err)
+	}
+	task, _ := s.GetTaskByID(taskID)
+	if task.Status != "Todo" {
+		t.Fatalf("A tarefa deveria voltar no estado inicial, obtido %q", task.Status)
+	}
+	if _, err := s.TransitionTask(taskID, service.TaskTransition{To: "Done"}); err != nil {
+		t.Errorf("A tarefa restaurada deveria seguir o fluxo atual: %v", err)
+	}
+
+	events, _ := s.GetTaskHistory(taskID)
+	restored := events[len(events)-2]
+	if restored.Kind != service.TaskUpdated || !slices.Equal(restored.Changes, []service.FieldChange{{Field: "status", Before: "Doing", After: "Todo"}}) {
+		t.Errorf("O ajuste do status deveria entrar no histórico: %+v", restored)
+	}
+}
+
+func TestTrashAndRestoreUser(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+	s.AssignMemberToTask(taskID, member.ID)
+	tokens, err := s.Login(member.Email, "123")
+	if err != nil {
+		t.Fatalf("Erro ao entrar: %v", err)
+	}
+
+	if err := s.DeleteUser(member.ID); err != nil {
+		t.Fatalf("Erro ao excluir o usuário: %v", err)
+	}
+	if _, err := s.GetUserByID(member.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("O usuário na lixeira não deveria aparecer, obtido %v", err)
+	}
+	if _, err := s.Login(member.Email, "123"); !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Errorf("O usuário na lixeira não deveria entrar, obtido %v", err)
+	}
+	if _, err := s.RefreshSession(tokens.RefreshToken); !errors.Is(err, service.ErrUnauthorized) {
+		t.Errorf("O usuário na lixeira não deveria renovar a sessão, obtido %v", err)
+	}
+
+	if _, err := s.AsUser(users[service.RoleManager]).ListTrashedUsers(); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para gerente, obtido %v", err)
+	}
+	trashed, err := s.AsUser(users[service.RoleAdmin]).ListTrashedUsers()
+	if err != nil {
+		t.Fatalf("Erro ao listar a lixeira: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != member.ID || trashed[0].Password != "" || trashed[0].DeletedAt == nil {
+		t.Fatalf("Lixeira incorreta: %+v", trashed)
+	}
+
+	if err := s.RestoreUser(member.ID); err != nil {
+		t.Fatalf("Erro ao restaurar o usuário: %v", err)
+	}
+	if _, err := s.Login(member.Email, "123"); err != nil {
+		t.Errorf("O usuário restaurado deveria entrar: %v", err)
+	}
+	if err := s.RestoreUser(member.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para usuário fora da lixeira, obtido %v", err)
+	}
+
+	events, _ := s.GetTaskHistory(taskID)
+	want := []service.TaskEventKind{service.TaskCreated, service.TaskAssigned, service.TaskUnassigned, service.TaskAssigned}
+	if kinds := eventKinds(events); !slices.Equal(kinds, want) {
+		t.Errorf("Eventos incorretos: %v", kinds)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	member := users[service.RoleMember]
+	taskID, _ := s.CreateTask(service.Task{Title: "Tarefa", Description: "Descrição"})
+	s.DeleteTask(taskID)
+	s.DeleteUser(member.ID)
+
+	if _, _, err := s.AsUser(users[service.RoleManager]).PurgeTrash(time.Now()); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para gerente, obtido %v", err)
+	}
+
+	// Nada foi excluído antes do limite
+	tasks, removed, err := s.PurgeTrash(time.Now().Add(-time.Hour))
+	if err != nil || tasks != 0 || removed != 0 {
+		t.Fatalf("Nada deveria ser apagado: %d tarefas, %d usuários, %v", tasks, removed, err)
+	}
+
+	tasks, removed, err = s.PurgeTrash(time.Now().Add(time.Minute))
+	if err != nil || tasks != 1 || removed != 1 {
+		t.Fatalf("Esperava-se apagar 1 tarefa e 1 usuário: %d tarefas, %d usuários, %v", tasks, removed, err)
+	}
+	if err := s.RestoreTask(taskID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("A tarefa apagada não deveria poder ser restaurada, obtido %v", err)
+	}
+	if err := s.RestoreUser(member.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("O usuário apagado não deveria poder ser restaurado, obtido %v", err)
+	}
+	// O histórico sobrevive à limpeza
+	if events, err := s.GetTaskHistory(taskID); err != nil || len(events) == 0 {
+		t.Errorf("O histórico deveria continuar disponível: %v %v", events, err)
+	}
+}
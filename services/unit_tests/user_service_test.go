@@ -1,10 +1,14 @@
 package service_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	_ "github.com/go-sql-driver/mysql"
 	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/services/unit_tests/mock"
+	"go.uber.org/zap"
 )
 
 func TestRegisterNewUserWithValidData(t *testing.T) {
@@ -141,3 +145,102 @@ func TestGetNonExistentUserByID(t *testing.T) {
 		t.Error("Esperava-se um erro ao tentar buscar um usuário inexistente pelo ID")
 	}
 }
+
+func TestRegisterNewUserHashesPassword(t *testing.T) {
+	repo := mock.NewTestRepository()
+	s := service.NewService(repo, zap.NewNop(), service.WithHashParams(testHashParams))
+
+	_, err := s.RegisterNewUser(service.User{Name: "John Doe", Email: "john@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Erro ao registrar novo usuário: %v", err)
+	}
+
+	stored, _ := repo.GetUserByEmail("john@example.com")
+	if stored.Password == "password123" || !strings.HasPrefix(stored.Password, "$2") {
+		t.Errorf("A senha não foi gravada como hash: %s", stored.Password)
+	}
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	s := NewTestService()
+
+	id, _ := s.RegisterNewUser(service.User{Name: "John Doe", Email: "john@example.com", Password: "password123"})
+
+	user, err := s.VerifyCredentials("john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Erro inesperado ao verificar credenciais válidas: %v", err)
+	}
+	if user.ID != id || user.Password != "" {
+		t.Error("O usuário autenticado não corresponde ao esperado")
+	}
+
+	_, err = s.VerifyCredentials("john@example.com", "senha-errada")
+	if !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Errorf("Esperava-se ErrInvalidCredentials para senha errada, obtido: %v", err)
+	}
+
+	_, err = s.VerifyCredentials("ninguem@example.com", "password123")
+	if !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Errorf("Esperava-se ErrInvalidCredentials para e-mail inexistente, obtido: %v", err)
+	}
+}
+
+func TestVerifyCredentialsRehashesOutdatedHash(t *testing.T) {
+	repo := mock.NewTestRepository()
+	old := service.NewService(repo, zap.NewNop(), service.WithHashParams(testHashParams))
+	old.RegisterNewUser(service.User{Name: "John Doe", Email: "john@example.com", Password: "password123"})
+
+	// Mesmo banco, mas agora o serviço usa argon2id
+	params := service.HashParams{Algorithm: service.AlgorithmArgon2id, Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	s := service.NewService(repo, zap.NewNop(), service.WithHashParams(params))
+
+	if _, err := s.VerifyCredentials("john@example.com", "password123"); err != nil {
+		t.Fatalf("Erro inesperado ao verificar credenciais: %v", err)
+	}
+
+	stored, _ := repo.GetUserByEmail("john@example.com")
+	if !strings.HasPrefix(stored.Password, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("O hash não foi refeito com os novos parâmetros: %s", stored.Password)
+	}
+
+	// O novo hash continua válido
+	if _, err := s.VerifyCredentials("john@example.com", "password123"); err != nil {
+		t.Errorf("Erro ao verificar credenciais após o rehash: %v", err)
+	}
+}
+
+func TestVerifyCredentialsUpgradesLegacyPlaintext(t *testing.T) {
+	repo := mock.NewTestRepository()
+	repo.AddUser(service.User{Name: "Legado", Email: "legado@example.com", Password: "antiga"})
+	s := service.NewService(repo, zap.NewNop(), service.WithHashParams(testHashParams))
+
+	if _, err := s.VerifyCredentials("legado@example.com", "antiga"); err != nil {
+		t.Fatalf("Erro inesperado ao verificar senha legada: %v", err)
+	}
+
+	stored, _ := repo.GetUserByEmail("legado@example.com")
+	if stored.Password == "antiga" {
+		t.Error("A senha legada em texto puro não foi convertida em hash")
+	}
+}
+
+func TestVerifyCredentialsRejectsEmptyPassword(t *testing.T) {
+	repo := mock.NewTestRepository()
+	repo.AddUser(service.User{Name: "Sem Senha", Email: "vazio@example.com", Password: ""})
+	s := service.NewService(repo, zap.NewNop(), service.WithHashParams(testHashParams))
+
+	_, err := s.VerifyCredentials("vazio@example.com", "")
+	if !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Errorf("Esperava-se ErrInvalidCredentials para senha vazia, obtido: %v", err)
+	}
+
+	_, err = s.VerifyCredentials("vazio@example.com", "qualquer")
+	if !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Errorf("Esperava-se ErrInvalidCredentials contra senha armazenada vazia, obtido: %v", err)
+	}
+
+	ok, _, err := service.NewPasswordHasher(testHashParams).Verify("", "")
+	if ok || err != nil {
+		t.Errorf("Senhas vazias não deveriam conferir: ok=%v, err=%v", ok, err)
+	}
+}
@@ -0,0 +1,268 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	service "github.com/mclcavalcante/teamTask/services"
+	"github.com/mclcavalcante/teamTask/services/unit_tests/mock"
+	"go.uber.org/zap"
+)
+
+func TestEditTaskEnforcesWorkflow(t *testing.T) {
+	s := NewTestService()
+
+	taskID, err := s.CreateTask(service.Task{Title: "Task 1", Description: "Description"})
+	if err != nil {
+		t.Fatalf("Erro ao criar tarefa: %v", err)
+	}
+	task, _ := s.GetTaskByID(taskID)
+	if task.Status != "Open" {
+		t.Errorf("Tarefa sem status deveria começar no estado inicial, obtido %q", task.Status)
+	}
+
+	if _, err := s.CreateTask(service.Task{Title: "Task 2", Description: "Description", Status: "Aberto"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para status desconhecido na criação, obtido %v", err)
+	}
+
+	_, err = s.EditTask(taskID, service.Task{Title: "Task 1", Description: "Description", Status: "Resolved", Resolution: "Corrigida"})
+	if !errors.Is(err, service.ErrInvalidTransition) {
+		t.Errorf("Esperava-se ErrInvalidTransition de Open para Resolved, obtido %v", err)
+	}
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task 1", Description: "Description", Status: "Pendente"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para status desconhecido, obtido %v", err)
+	}
+
+	// Os nomes dos estados não diferenciam maiúsculas de minúsculas
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task 1", Description: "Description", Status: "in progress"}); err != nil {
+		t.Fatalf("Erro ao iniciar tarefa: %v", err)
+	}
+	task, _ = s.GetTaskByID(taskID)
+	if task.Status != "In Progress" {
+		t.Errorf("Esperava-se o nome do estado como no fluxo, obtido %q", task.Status)
+	}
+
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task 1", Description: "Description", Status: "Resolved"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para resolução sem motivo, obtido %v", err)
+	}
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task 1", Description: "Description", Status: "Resolved", Resolution: "Corrigida"}); err != nil {
+		t.Fatalf("Erro ao resolver tarefa: %v", err)
+	}
+
+	// Editar sem status mantém o status e a resolução
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task 1 revisada", Description: "Description"}); err != nil {
+		t.Fatalf("Erro ao editar tarefa: %v", err)
+	}
+	task, _ = s.GetTaskByID(taskID)
+	if task.Status != "Resolved" || task.Resolution != "Corrigida" || task.CompletedAt == nil {
+		t.Errorf("Tarefa resolvida não corresponde à esperada: %+v", task)
+	}
+
+	// Reabrir uma tarefa resolvida exige um comentário, que EditTask não recebe
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task 1", Description: "Description", Status: "In Progress"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para reabertura sem comentário, obtido %v", err)
+	}
+}
+
+func TestTransitionTask(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	manager, member, viewer := users[service.RoleManager], users[service.RoleMember], users[service.RoleViewer]
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description", AssignedUsers: []int{member.ID}})
+
+	if _, err := s.AsUser(viewer).TransitionTask(taskID, service.TaskTransition{To: "In Progress"}); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para leitor, obtido %v", err)
+	}
+	if _, err := s.AsUser(manager).TransitionTask(taskID, service.TaskTransition{To: "Open"}); !errors.Is(err, service.ErrInvalidTransition) {
+		t.Errorf("Esperava-se ErrInvalidTransition para o status atual, obtido %v", err)
+	}
+	if _, err := s.AsUser(manager).TransitionTask(999, service.TaskTransition{To: "In Progress"}); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Esperava-se ErrNotFound para tarefa inexistente, obtido %v", err)
+	}
+
+	if _, err := s.AsUser(member).TransitionTask(taskID, service.TaskTransition{To: "In Progress"}); err != nil {
+		t.Fatalf("Erro ao iniciar tarefa atribuída: %v", err)
+	}
+	task, err := s.AsUser(member).TransitionTask(taskID, service.TaskTransition{To: "Resolved", Resolution: "Corrigida"})
+	if err != nil {
+		t.Fatalf("Erro ao resolver tarefa: %v", err)
+	}
+	if task.Status != "Resolved" || task.Resolution != "Corrigida" || task.CompletedAt == nil {
+		t.Errorf("Tarefa resolvida não corresponde à esperada: %+v", task)
+	}
+
+	if _, err := s.AsUser(manager).TransitionTask(taskID, service.TaskTransition{To: "In Progress"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para reabertura sem comentário, obtido %v", err)
+	}
+	task, err = s.AsUser(manager).TransitionTask(taskID, service.TaskTransition{To: "In Progress", Comment: "Voltou a falhar em produção"})
+	if err != nil {
+		t.Fatalf("Erro ao reabrir tarefa: %v", err)
+	}
+	if task.Resolution != "" || task.CompletedAt != nil {
+		t.Errorf("Tarefa reaberta não deveria ter resolução nem conclusão: %+v", task)
+	}
+
+	page, _ := s.ListComments(taskID, 0, 0)
+	if page.Total != 1 || page.Comments[0].Text != "Voltou a falhar em produção" || page.Comments[0].AuthorID != manager.ID {
+		t.Errorf("Comentário da transição não publicado: %+v", page.Comments)
+	}
+
+	inbox, _ := s.AsUser(member).ListNotifications(false)
+	if types := notificationTypes(inbox); len(types) == 0 || types[0] != service.NotificationStatusChange {
+		t.Errorf("Esperava-se a notificação de mudança de status, obtido %v", types)
+	}
+}
+
+func TestCustomWorkflow(t *testing.T) {
+	workflow := service.Workflow{
+		Initial: "Backlog",
+		States:  []service.WorkflowState{{Name: "Backlog"}, {Name: "Feito", Completed: true}},
+		Transitions: []service.WorkflowTransition{
+			{From: "Backlog", To: "Feito"},
+		},
+	}
+	if err := workflow.Validate(); err != nil {
+		t.Fatalf("Fluxo válido rejeitado: %v", err)
+	}
+	s := service.NewService(mock.NewTestRepository(), zap.NewNop(), service.WithHashParams(testHashParams), service.WithWorkflow(workflow))
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	task, err := s.TransitionTask(taskID, service.TaskTransition{To: "Feito"})
+	if err != nil {
+		t.Fatalf("Erro ao concluir tarefa: %v", err)
+	}
+	if task.CompletedAt == nil {
+		t.Error("Esperava-se a data de conclusão no estado concluído do fluxo")
+	}
+
+	// Sem transição de volta, a tarefa concluída fica onde está
+	if _, err := s.TransitionTask(taskID, service.TaskTransition{To: "Backlog"}); !errors.Is(err, service.ErrInvalidTransition) {
+		t.Errorf("Esperava-se ErrInvalidTransition, obtido %v", err)
+	}
+
+	invalid := service.Workflow{Initial: "Novo", States: []service.WorkflowState{{Name: "A"}, {Name: "a"}}}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Esperava-se erro para estado inicial desconhecido e estados repetidos")
+	}
+	startedAndCompleted := service.Workflow{Initial: "A", States: []service.WorkflowState{{Name: "A", Started: true, Completed: true}}}
+	if err := startedAndCompleted.Validate(); err == nil {
+		t.Error("Esperava-se erro para estado iniciado e concluído")
+	}
+}
+
+func TestWorkflowsPerTeamAndTaskType(t *testing.T) {
+	s := NewTestService()
+	users := newUsersWithRoles(t, s)
+	admin, manager := s.AsUser(users[service.RoleAdmin]), s.AsUser(users[service.RoleManager])
+
+	bugs := service.Workflow{
+		Name:    "Bugs",
+		Initial: "triage",
+		States:  []service.WorkflowState{{Name: "Triage"}, {Name: "Fixing"}, {Name: "Done", Completed: true}},
+		Transitions: []service.WorkflowTransition{
+			{From: "Triage", To: "Fixing"},
+			{From: "Fixing", To: "Done"},
+		},
+	}
+	if _, err := manager.CreateWorkflow(bugs); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("Esperava-se ErrForbidden para gerente, obtido %v", err)
+	}
+	if _, err := admin.CreateWorkflow(service.Workflow{Name: "Vazio"}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation para fluxo sem estados, obtido %v", err)
+	}
+	bugsID, err := admin.CreateWorkflow(bugs)
+	if err != nil {
+		t.Fatalf("Erro ao cadastrar fluxo: %v", err)
+	}
+	boardID, _ := admin.CreateWorkflow(service.Workflow{Name: "Quadro", Initial: "Todo", States: []service.WorkflowState{{Name: "Todo"}, {Name: "Doing"}}})
+	teamID, _ := admin.CreateTeam(service.Team{Name: "Plataforma"})
+
+	if err := admin.AssignWorkflow(service.WorkflowAssignment{WorkflowID: bugsID, TaskType: "Bug"}, nil); err != nil {
+		t.Fatalf("Erro ao atribuir fluxo ao tipo: %v", err)
+	}
+	if err := admin.AssignWorkflow(service.WorkflowAssignment{WorkflowID: boardID, TeamID: teamID}, nil); err != nil {
+		t.Fatalf("Erro ao atribuir fluxo à equipe: %v", err)
+	}
+
+	statusOf := func(taskID int) string {
+		t.Helper()
+		task, err := s.GetTaskByID(taskID)
+		if err != nil {
+			t.Fatalf("Erro ao buscar tarefa: %v", err)
+		}
+		return task.Status
+	}
+
+	bugID, _ := manager.CreateTask(service.Task{Title: "Bug", Description: "Description", Type: "bug"})
+	plainID, _ := manager.CreateTask(service.Task{Title: "Tarefa", Description: "Description"})
+	teamBugID, _ := manager.CreateTask(service.Task{Title: "Bug da equipe", Description: "Description", Type: "bug", TeamID: teamID})
+	if got := []string{statusOf(bugID), statusOf(plainID), statusOf(teamBugID)}; got[0] != "Triage" || got[1] != "Open" || got[2] != "Todo" {
+		t.Errorf("Status iniciais incorretos, a equipe deveria vencer o tipo: %v", got)
+	}
+
+	workflow, err := manager.GetTaskWorkflow(bugID)
+	if err != nil || workflow.ID != bugsID {
+		t.Errorf("Esperava-se o fluxo %d para a tarefa, obtido %+v (%v)", bugsID, workflow, err)
+	}
+
+	if _, err := manager.TransitionTask(bugID, service.TaskTransition{To: "Fixing"}); err != nil {
+		t.Fatalf("Erro ao mudar status: %v", err)
+	}
+
+	// Renomear um status exige informar para onde vão as tarefas que estão nele
+	bugs.States[1].Name = "In Fix"
+	bugs.Transitions = []service.WorkflowTransition{{From: "Triage", To: "In Fix"}, {From: "In Fix", To: "Done"}}
+	if err := admin.UpdateWorkflow(bugsID, bugs, nil); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation sem statusMapping, obtido %v", err)
+	}
+	if statusOf(bugID) != "Fixing" {
+		t.Errorf("Uma alteração recusada não deveria migrar tarefas, obtido %q", statusOf(bugID))
+	}
+	if err := admin.UpdateWorkflow(bugsID, bugs, map[string]string{"Fixing": "in fix"}); err != nil {
+		t.Fatalf("Erro ao alterar fluxo: %v", err)
+	}
+	if statusOf(bugID) != "In Fix" {
+		t.Errorf("Tarefa não migrada para o status renomeado, obtido %q", statusOf(bugID))
+	}
+
+	if err := admin.DeleteWorkflow(bugsID); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Esperava-se ErrConflict ao excluir fluxo atribuído, obtido %v", err)
+	}
+
+	// Sem a atribuição, os bugs voltam ao fluxo da configuração
+	if err := admin.UnassignWorkflow(0, "BUG", map[string]string{"In Fix": "In Progress"}); err != nil {
+		t.Fatalf("Erro ao remover atribuição: %v", err)
+	}
+	if statusOf(bugID) != "In Progress" {
+		t.Errorf("Tarefa não migrada para o fluxo padrão, obtido %q", statusOf(bugID))
+	}
+	if err := admin.DeleteWorkflow(bugsID); err != nil {
+		t.Errorf("Erro ao excluir fluxo sem atribuições: %v", err)
+	}
+}
+
+func TestEditTaskMovingToAnotherWorkflow(t *testing.T) {
+	s := NewTestService()
+
+	boardID, _ := s.CreateWorkflow(service.Workflow{Name: "Quadro", Initial: "Todo", States: []service.WorkflowState{{Name: "Todo"}, {Name: "Done", Completed: true}}})
+	teamID, _ := s.CreateTeam(service.Team{Name: "Plataforma"})
+	if err := s.AssignWorkflow(service.WorkflowAssignment{WorkflowID: boardID, TeamID: teamID}, nil); err != nil {
+		t.Fatalf("Erro ao atribuir fluxo: %v", err)
+	}
+
+	taskID, _ := s.CreateTask(service.Task{Title: "Task", Description: "Description"})
+	_, err := s.EditTask(taskID, service.Task{Title: "Task", Description: "Description", TeamID: teamID})
+	if !errors.Is(err, service.ErrValidation) {
+		t.Errorf("Esperava-se ErrValidation ao mudar de fluxo sem um status do novo fluxo, obtido %v", err)
+	}
+
+	// Ao mudar de fluxo o novo status é aceito sem transição
+	if _, err := s.EditTask(taskID, service.Task{Title: "Task", Description: "Description", TeamID: teamID, Status: "Done"}); err != nil {
+		t.Fatalf("Erro ao mover tarefa para a equipe: %v", err)
+	}
+	task, _ := s.GetTaskByID(taskID)
+	if task.Status != "Done" || task.CompletedAt == nil {
+		t.Errorf("Tarefa não corresponde à esperada: %+v", task)
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+// IfMatch retorna uma cópia do serviço que só edita ou exclui uma tarefa se ela ainda
+// estiver na versão informada; zero aceita qualquer versão.
+func (service teamTaskService) IfMatch(version int) Service {
+	service.ifMatch = version
+	return &service
+}
+
+// checkVersion confere a versão exigida por IfMatch com a versão atual da tarefa.
+func (service teamTaskService) checkVersion(current Task) error {
+	if service.ifMatch != 0 && service.ifMatch != current.Version {
+		return &VersionError{Current: current.Version, IfMatch: true}
+	}
+	return nil
+}
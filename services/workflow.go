@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WorkflowState é um status possível de uma tarefa. Tarefas em estados concluídos
+// recebem CompletedAt e saem das listas de atrasadas e de próximos prazos. Estados
+// iniciados marcam o trabalho em andamento: uma tarefa bloqueada não entra neles.
+type WorkflowState struct {
+	Name      string `json:"name" yaml:"name"`
+	Completed bool   `json:"completed" yaml:"completed"`
+	Started   bool   `json:"started,omitempty" yaml:"started"`
+}
+
+// WorkflowTransition é uma mudança de status permitida. RequireResolution exige que a
+// tarefa informe uma resolução e RequireComment, um comentário justificando a mudança.
+type WorkflowTransition struct {
+	From              string `json:"from" yaml:"from"`
+	To                string `json:"to" yaml:"to"`
+	RequireResolution bool   `json:"requireResolution,omitempty" yaml:"requireResolution"`
+	RequireComment    bool   `json:"requireComment,omitempty" yaml:"requireComment"`
+}
+
+// Workflow define os status das tarefas e as transições permitidas entre eles.
+// Initial é o status das tarefas criadas sem status. O fluxo da configuração não tem ID;
+// os cadastrados pela API ficam no banco e são atribuídos por WorkflowAssignment.
+type Workflow struct {
+	ID          int                  `json:"id,omitempty" yaml:"-"`
+	Name        string               `json:"name,omitempty" yaml:"name,omitempty"`
+	Initial     string               `json:"initial" yaml:"initial"`
+	States      []WorkflowState      `json:"states" yaml:"states"`
+	Transitions []WorkflowTransition `json:"transitions" yaml:"transitions"`
+}
+
+// WorkflowAssignment atribui um fluxo às tarefas de uma equipe, de um tipo ou de um tipo
+// dentro de uma equipe. TeamID zero vale para todas as equipes e TaskType vazio para
+// todos os tipos; a atribuição mais específica vence.
+type WorkflowAssignment struct {
+	WorkflowID int    `json:"workflowId"`
+	TeamID     int    `json:"teamId,omitempty"`
+	TaskType   string `json:"taskType,omitempty"`
+}
+
+// TaskTransition é o pedido de mudança de status de uma tarefa.
+type TaskTransition struct {
+	To         string `json:"to"`
+	Resolution string `json:"resolution,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// DefaultWorkflow retorna o fluxo usado quando nenhum é configurado.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Initial: "Open",
+		States: []WorkflowState{
+			{Name: "Open"},
+			{Name: "In Progress", Started: true},
+			{Name: "Resolved", Completed: true},
+			{Name: "Closed", Completed: true},
+		},
+		Transitions: []WorkflowTransition{
+			{From: "Open", To: "In Progress"},
+			{From: "Open", To: "Closed"},
+			{From: "In Progress", To: "Open"},
+			{From: "In Progress", To: "Resolved", RequireResolution: true},
+			{From: "Resolved", To: "In Progress", RequireComment: true},
+			{From: "Resolved", To: "Closed"},
+			{From: "Closed", To: "Open"},
+		},
+	}
+}
+
+// Validate verifica se o fluxo é consistente: estados únicos e não vazios, estado
+// inicial conhecido e transições apenas entre estados conhecidos.
+func (w Workflow) Validate() error {
+	var errs []error
+	if len(w.States) == 0 {
+		errs = append(errs, errors.New("informe ao menos um estado"))
+	}
+
+	seen := make(map[string]bool, len(w.States))
+	for _, state := range w.States {
+		key := strings.ToLower(strings.TrimSpace(state.Name))
+		switch {
+		case key == "":
+			errs = append(errs, errors.New("estado sem nome"))
+		case seen[key]:
+			errs = append(errs, fmt.Errorf("estado repetido %q", state.Name))
+		case state.Started && state.Completed:
+			errs = append(errs, fmt.Errorf("o estado %q não pode ser iniciado e concluído", state.Name))
+		}
+		seen[key] = true
+	}
+
+	if _, ok := w.State(w.Initial); !ok {
+		errs = append(errs, fmt.Errorf("estado inicial desconhecido %q", w.Initial))
+	}
+
+	for _, transition := range w.Transitions {
+		if _, ok := w.State(transition.From); !ok {
+			errs = append(errs, fmt.Errorf("transição de um estado desconhecido %q", transition.From))
+		}
+		if _, ok := w.State(transition.To); !ok {
+			errs = append(errs, fmt.Errorf("transição para um estado desconhecido %q", transition.To))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// State procura um estado pelo nome, sem diferenciar maiúsculas de minúsculas.
+func (w Workflow) State(name string) (WorkflowState, bool) {
+	name = strings.TrimSpace(name)
+	for _, state := range w.States {
+		if strings.EqualFold(state.Name, name) {
+			return state, true
+		}
+	}
+
+	return WorkflowState{}, false
+}
+
+// Transition procura a transição de from para to.
+func (w Workflow) Transition(from, to string) (WorkflowTransition, bool) {
+	for _, transition := range w.Transitions {
+		if strings.EqualFold(transition.From, from) && strings.EqualFold(transition.To, to) {
+			return transition, true
+		}
+	}
+
+	return WorkflowTransition{}, false
+}
+
+// IsCompleted informa se o status é um estado concluído do fluxo.
+func (w Workflow) IsCompleted(status string) bool {
+	state, ok := w.State(status)
+	return ok && state.Completed
+}
+
+// targets lista os estados alcançáveis a partir de from, para as mensagens de erro.
+func (w Workflow) targets(from string) []string {
+	var names []string
+	for _, transition := range w.Transitions {
+		if strings.EqualFold(transition.From, from) {
+			names = append(names, transition.To)
+		}
+	}
+
+	return names
+}
+
+// WithWorkflow define o fluxo das tarefas sem fluxo atribuído. O fluxo deve ter sido validado.
+func WithWorkflow(workflow Workflow) Option {
+	return func(s *teamTaskService) {
+		s.workflow = workflow
+	}
+}
+
+// checkTransition valida, no fluxo w, a mudança de status de current para o estado to.
+// Tarefas com um status fora do fluxo, gravadas antes dele existir, podem ir para
+// qualquer estado.
+func checkTransition(w Workflow, current Task, to WorkflowState, resolution, comment string) error {
+	if _, known := w.State(current.Status); !known {
+		return nil
+	}
+
+	transition, ok := w.Transition(current.Status, to.Name)
+	if !ok {
+		allowed := w.targets(current.Status)
+		if len(allowed) == 0 {
+			return fmt.Errorf("%w: %q → %q (nenhuma transição sai de %q)", ErrInvalidTransition, current.Status, to.Name, current.Status)
+		}
+		return fmt.Errorf("%w: %q → %q (permitidas a partir de %q: %s)", ErrInvalidTransition, current.Status, to.Name, current.Status, strings.Join(allowed, ", "))
+	}
+
+	if transition.RequireResolution && strings.TrimSpace(resolution) == "" {
+		return fmt.Errorf("%w: a transição %q → %q exige uma resolução", ErrValidation, current.Status, to.Name)
+	}
+	if transition.RequireComment && strings.TrimSpace(comment) == "" {
+		return fmt.Errorf("%w: a transição %q → %q exige um comentário", ErrValidation, current.Status, to.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// workflowSet reúne os fluxos cadastrados e suas atribuições para decidir o fluxo de
+// cada tarefa.
+type workflowSet struct {
+	fallback    Workflow
+	workflows   map[int]Workflow
+	assignments []WorkflowAssignment
+}
+
+// forTask retorna o fluxo da tarefa: a atribuição à equipe e ao tipo da tarefa vence a
+// atribuição só à equipe, que vence a só ao tipo, que vence a geral. Sem atribuição vale
+// o fluxo da configuração.
+func (set workflowSet) forTask(task Task) Workflow {
+	best, bestRank := set.fallback, 0
+	for _, assignment := range set.assignments {
+		if assignment.TeamID != 0 && assignment.TeamID != task.TeamID {
+			continue
+		}
+		if assignment.TaskType != "" && assignment.TaskType != task.Type {
+			continue
+		}
+
+		rank := 1
+		if assignment.TeamID != 0 {
+			rank += 2
+		}
+		if assignment.TaskType != "" {
+			rank++
+		}
+		if workflow, ok := set.workflows[assignment.WorkflowID]; ok && rank > bestRank {
+			best, bestRank = workflow, rank
+		}
+	}
+
+	return best
+}
+
+// loadWorkflows lê os fluxos cadastrados e suas atribuições.
+func (service teamTaskService) loadWorkflows() (workflowSet, error) {
+	workflows, err := service.db.ListWorkflows()
+	if err != nil {
+		return workflowSet{}, errors.Join(err, errors.New("erro ao obter os fluxos de trabalho"))
+	}
+	assignments, err := service.db.ListWorkflowAssignments()
+	if err != nil {
+		return workflowSet{}, errors.Join(err, errors.New("erro ao obter as atribuições de fluxos"))
+	}
+
+	set := workflowSet{fallback: service.workflow, workflows: make(map[int]Workflow, len(workflows)), assignments: assignments}
+	for _, workflow := range workflows {
+		set.workflows[workflow.ID] = workflow
+	}
+
+	return set, nil
+}
+
+// workflowFor retorna o fluxo que vale para a tarefa.
+func (service teamTaskService) workflowFor(task Task) (Workflow, error) {
+	set, err := service.loadWorkflows()
+	if err != nil {
+		return Workflow{}, err
+	}
+
+	return set.forTask(task), nil
+}
+
+// GetTaskWorkflow retorna o fluxo que vale para a tarefa, com os status e transições
+// disponíveis para ela.
+func (service teamTaskService) GetTaskWorkflow(taskID int) (Workflow, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return Workflow{}, err
+	}
+
+	task, err := service.GetTaskByID(taskID)
+	if err != nil {
+		return Workflow{}, err
+	}
+
+	return service.workflowFor(task)
+}
+
+// ListWorkflows retorna os fluxos cadastrados. O fluxo da configuração não aparece.
+func (service teamTaskService) ListWorkflows() ([]Workflow, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	workflows, err := service.db.ListWorkflows()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter os fluxos de trabalho"))
+	}
+
+	return workflows, nil
+}
+
+// GetWorkflow busca um fluxo cadastrado pelo ID.
+func (service teamTaskService) GetWorkflow(workflowID int) (Workflow, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return Workflow{}, err
+	}
+
+	workflow, err := service.db.GetWorkflow(workflowID)
+	if err != nil {
+		return Workflow{}, errors.Join(err, errors.New("fluxo de trabalho não encontrado"))
+	}
+
+	return workflow, nil
+}
+
+// CreateWorkflow cadastra um fluxo. Ele só passa a valer depois de atribuído.
+func (service teamTaskService) CreateWorkflow(workflow Workflow) (int, error) {
+	if err := service.authorize(PermManageWorkflows); err != nil {
+		return 0, err
+	}
+
+	workflow, err := normalizeWorkflow(workflow)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := service.db.CreateWorkflow(workflow)
+	if err != nil {
+		return 0, errors.Join(err, errors.New("erro ao cadastrar o fluxo de trabalho"))
+	}
+
+	return id, nil
+}
+
+// UpdateWorkflow substitui a definição de um fluxo. As tarefas que usam o fluxo e estão
+// em um status renomeado ou removido passam para o status indicado em statusMapping
+// (status antigo → novo); faltando o novo status de alguma delas, nada é alterado.
+func (service teamTaskService) UpdateWorkflow(workflowID int, workflow Workflow, statusMapping map[string]string) error {
+	if err := service.authorize(PermManageWorkflows); err != nil {
+		return err
+	}
+
+	workflow, err := normalizeWorkflow(workflow)
+	if err != nil {
+		return err
+	}
+	workflow.ID = workflowID
+
+	return service.inTransaction(func(tx teamTaskService) error {
+		before, err := tx.loadWorkflows()
+		if err != nil {
+			return err
+		}
+
+		if err := tx.db.UpdateWorkflow(workflow); err != nil {
+			return errors.Join(err, errors.New("erro ao alterar o fluxo de trabalho"))
+		}
+
+		return tx.migrateTaskStatuses(before, statusMapping)
+	})
+}
+
+// DeleteWorkflow exclui um fluxo que não esteja atribuído.
+func (service teamTaskService) DeleteWorkflow(workflowID int) error {
+	if err := service.authorize(PermManageWorkflows); err != nil {
+		return err
+	}
+
+	return service.inTransaction(func(tx teamTaskService) error {
+		assignments, err := tx.db.ListWorkflowAssignments()
+		if err != nil {
+			return errors.Join(err, errors.New("erro ao obter as atribuições de fluxos"))
+		}
+		for _, assignment := range assignments {
+			if assignment.WorkflowID == workflowID {
+				return fmt.Errorf("%w: o fluxo de trabalho ainda está atribuído; remova as atribuições antes", ErrConflict)
+			}
+		}
+
+		if err := tx.db.DeleteWorkflow(workflowID); err != nil {
+			return errors.Join(err, errors.New("erro ao excluir o fluxo de trabalho"))
+		}
+
+		return nil
+	})
+}
+
+// ListWorkflowAssignments retorna as atribuições de fluxos.
+func (service teamTaskService) ListWorkflowAssignments() ([]WorkflowAssignment, error) {
+	if err := service.authorize(PermViewTasks); err != nil {
+		return nil, err
+	}
+
+	assignments, err := service.db.ListWorkflowAssignments()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("erro ao obter as atribuições de fluxos"))
+	}
+
+	return assignments, nil
+}
+
+// AssignWorkflow atribui um fluxo a uma equipe, a um tipo de tarefa ou aos dois,
+// substituindo a atribuição anterior. Tarefas cujo status não existe no novo fluxo são
+// migradas por statusMapping, como em UpdateWorkflow.
+func (service teamTaskService) AssignWorkflow(assignment WorkflowAssignment, statusMapping map[string]string) error {
+	if err := service.authorize(PermManageWorkflows); err != nil {
+		return err
+	}
+	assignment.TaskType = normalizeTaskType(assignment.TaskType)
+
+	return service.inTransaction(func(tx teamTaskService) error {
+		before, err := tx.loadWorkflows()
+		if err != nil {
+			return err
+		}
+
+		if err := tx.db.SetWorkflowAssignment(assignment); err != nil {
+			return errors.Join(err, errors.New("erro ao atribuir o fluxo de trabalho"))
+		}
+
+		return tx.migrateTaskStatuses(before, statusMapping)
+	})
+}
+
+// UnassignWorkflow remove a atribuição da equipe e do tipo de tarefa informados; as
+// tarefas passam para o próximo fluxo que valer para elas.
+func (service teamTaskService) UnassignWorkflow(teamID int, taskType string, statusMapping map[string]string) error {
+	if err := service.authorize(PermManageWorkflows); err != nil {
+		return err
+	}
+
+	return service.inTransaction(func(tx teamTaskService) error {
+		before, err := tx.loadWorkflows()
+		if err != nil {
+			return err
+		}
+
+		if err := tx.db.RemoveWorkflowAssignment(teamID, normalizeTaskType(taskType)); err != nil {
+			return errors.Join(err, errors.New("erro ao remover a atribuição do fluxo de trabalho"))
+		}
+
+		return tx.migrateTaskStatuses(before, statusMapping)
+	})
+}
+
+// migrateTaskStatuses ajusta as tarefas depois de uma mudança nos fluxos: as que estavam
+// em um status válido do fluxo anterior e ficaram sem ele no novo vão para o status de
+// statusMapping, e as que mudaram de estado concluído para aberto, ou o contrário,
+// recebem ou perdem a data de conclusão. Status fora de qualquer fluxo são mantidos. As
+// tarefas da lixeira ficam como estão; RestoreTask ajusta o status delas.
+func (tx teamTaskService) migrateTaskStatuses(before workflowSet, statusMapping map[string]string) error {
+	after, err := tx.loadWorkflows()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := tx.db.GetAllTasks()
+	if err != nil {
+		return errors.Join(err, errors.New("erro ao obter tarefas"))
+	}
+
+	now := time.Now().UTC()
+	unmapped := make(map[string]bool)
+	for _, task := range tasks {
+		if _, known := before.forTask(task).State(task.Status); !known {
+			continue
+		}
+
+		workflow := after.forTask(task)
+		state, ok := workflow.State(task.Status)
+		if !ok {
+			state, ok = workflow.State(lookupStatus(statusMapping, task.Status))
+		}
+		if !ok {
+			unmapped[task.Status] = true
+			continue
+		}
+		if state.Name == task.Status && state.Completed == (task.CompletedAt != nil) {
+			continue
+		}
+
+		updated := task
+		updated.Status = state.Name
+		updated = stampTask(updated, &task, now, state.Completed)
+		if err := tx.db.UpdateTask(task.ID, updated); err != nil {
+			return errors.Join(err, errors.New("erro ao migrar o status da tarefa"))
+		}
+		if err := tx.recordUpdate(task, updated); err != nil {
+			return err
+		}
+	}
+
+	if len(unmapped) > 0 {
+		statuses := make([]string, 0, len(unmapped))
+		for status := range unmapped {
+			statuses = append(statuses, fmt.Sprintf("%q", status))
+		}
+		sort.Strings(statuses)
+		return fmt.Errorf("%w: informe em statusMapping um status do novo fluxo para as tarefas em %s", ErrValidation, strings.Join(statuses, ", "))
+	}
+
+	return nil
+}
+
+// lookupStatus procura o novo status de status em statusMapping, sem diferenciar
+// maiúsculas de minúsculas.
+func lookupStatus(statusMapping map[string]string, status string) string {
+	for from, to := range statusMapping {
+		if strings.EqualFold(strings.TrimSpace(from), status) {
+			return to
+		}
+	}
+
+	return ""
+}
+
+// normalizeTaskType grava os tipos de tarefa sem espaços e em minúsculas, para que
+// "Bug" e "bug" escolham o mesmo fluxo.
+func normalizeTaskType(taskType string) string {
+	return strings.ToLower(strings.TrimSpace(taskType))
+}
+
+// normalizeWorkflow valida um fluxo recebido pela API e usa nas transições e no estado
+// inicial os nomes dos estados como foram declarados.
+func normalizeWorkflow(workflow Workflow) (Workflow, error) {
+	workflow.Name = strings.TrimSpace(workflow.Name)
+	if workflow.Name == "" {
+		return Workflow{}, fmt.Errorf("%w: nome do fluxo de trabalho é obrigatório", ErrValidation)
+	}
+	// Cópias, para não alterar os slices de quem chamou
+	workflow.States = append([]WorkflowState(nil), workflow.States...)
+	workflow.Transitions = append([]WorkflowTransition(nil), workflow.Transitions...)
+	for i := range workflow.States {
+		workflow.States[i].Name = strings.TrimSpace(workflow.States[i].Name)
+	}
+	if err := workflow.Validate(); err != nil {
+		return Workflow{}, fmt.Errorf("%w: %s", ErrValidation, strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
+
+	initial, _ := workflow.State(workflow.Initial)
+	workflow.Initial = initial.Name
+	for i, transition := range workflow.Transitions {
+		from, _ := workflow.State(transition.From)
+		to, _ := workflow.State(transition.To)
+		workflow.Transitions[i].From, workflow.Transitions[i].To = from.Name, to.Name
+	}
+
+	return workflow, nil
+}
@@ -0,0 +1,96 @@
+// Package storage guarda o conteúdo dos anexos das tarefas. Local grava no sistema de
+// arquivos e S3 em qualquer serviço compatível com a API do Amazon S3.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// Local guarda os anexos em arquivos abaixo de um diretório.
+type Local struct {
+	dir string
+}
+
+// NewLocal cria o diretório, se preciso, e retorna o armazenamento sobre ele.
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("diretório de anexos: %w", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+// Put grava o conteúdo em um arquivo temporário e só o move para o caminho final depois
+// de receber exatamente size bytes, para que uma falha no envio não deixe arquivos pela
+// metade.
+func (l *Local) Put(key string, content io.Reader, size int64, contentType string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(content, size+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("tamanho do conteúdo diferente do informado: %d de %d bytes", written, size)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get abre o arquivo do anexo. Uma chave inexistente retorna service.ErrNotFound.
+func (l *Local) Get(key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, service.ErrNotFound
+	}
+	return file, err
+}
+
+// Delete apaga o arquivo do anexo; uma chave inexistente não é erro.
+func (l *Local) Delete(key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path converte a chave em um caminho abaixo do diretório, recusando chaves que
+// escapariam dele.
+func (l *Local) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("chave de anexo inválida %q", key)
+	}
+	return filepath.Join(l.dir, clean), nil
+}
@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	service "github.com/mclcavalcante/teamTask/services"
+)
+
+// S3Config configura o acesso a um bucket compatível com o S3.
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"` // ex.: https://s3.us-east-1.amazonaws.com ou http://localhost:9000
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"accessKey"`
+	SecretKey string `yaml:"secretKey"`
+}
+
+// Validate confere os campos obrigatórios.
+func (c S3Config) Validate() error {
+	var errs []error
+	if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("endpoint inválido %q", c.Endpoint))
+	}
+	if c.Region == "" {
+		errs = append(errs, errors.New("region é obrigatório"))
+	}
+	if c.Bucket == "" {
+		errs = append(errs, errors.New("bucket é obrigatório"))
+	}
+	if c.AccessKey == "" || c.SecretKey == "" {
+		errs = append(errs, errors.New("accessKey e secretKey são obrigatórios"))
+	}
+	return errors.Join(errs...)
+}
+
+// S3 guarda os anexos como objetos de um bucket, endereçado no estilo de caminho
+// (endpoint/bucket/chave), aceito pela AWS e pelos serviços compatíveis.
+type S3 struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3 retorna o armazenamento sobre o bucket configurado.
+func NewS3(config S3Config) (*S3, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+
+	return &S3{config: config, client: &http.Client{Timeout: 5 * time.Minute}, now: time.Now}, nil
+}
+
+// Put envia o objeto com PUT.
+func (s *S3) Put(key string, content io.Reader, size int64, contentType string) error {
+	req, err := s.request(http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Get busca o objeto. Um objeto inexistente retorna service.ErrNotFound.
+func (s *S3) Get(key string) (io.ReadCloser, error) {
+	req, err := s.request(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Delete apaga o objeto; o S3 não trata um objeto inexistente como erro.
+func (s *S3) Delete(key string) error {
+	req, err := s.request(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (s *S3) request(method, key string, body io.Reader) (*http.Request, error) {
+	if key == "" {
+		return nil, errors.New("chave de anexo vazia")
+	}
+
+	path := "/" + uriEncode(s.config.Bucket, false) + "/" + uriEncode(key, true)
+	req, err := http.NewRequest(method, s.config.Endpoint+path, body)
+	if err != nil {
+		return nil, err
+	}
+	// O caminho vai exatamente como foi assinado
+	req.URL.RawPath = path
+
+	return req, nil
+}
+
+// do assina e envia a requisição. Respostas fora da faixa 2xx viram erro, com a mensagem
+// devolvida pelo serviço.
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	// O conteúdo não entra na assinatura para que os anexos sejam enviados sem passar
+	// duas vezes pelo disco
+	signV4(req, "UNSIGNED-PAYLOAD", s.config, s.now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, service.ErrNotFound
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3: %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(message)))
+}
+
+// signV4 assina a requisição com o AWS Signature Version 4, no cabeçalho Authorization.
+// Entram na assinatura o host, o Content-Type, o Range e os cabeçalhos x-amz-*.
+func signV4(req *http.Request, payloadHash string, config S3Config, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+config.SecretKey), date)
+	key = hmacSHA256(key, config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		config.AccessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, false)+"="+uriEncode(value, false))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode codifica como pede a assinatura: apenas letras, dígitos e -_.~ ficam como
+// estão, e a barra só é preservada quando keepSlash.
+func uriEncode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}